@@ -0,0 +1,19 @@
+package main
+
+import (
+	xInit "github.com/bamboo-services/bamboo-base-go/init"
+	"github.com/bamboo-services/bamboo-sso/pkg/startup"
+)
+
+// main 是重新加密命令的入口点，使用配置文件中当前启用的主密钥重新加密数据库中的全部加密字段。
+//
+// 轮换主密钥的步骤：
+//  1. 在 sso.secret.keys 中加入新密钥，并将 sso.secret.active_key 指向新密钥。
+//  2. 重启服务，使新写入的数据使用新密钥加密。
+//  3. 执行本命令，将存量数据改用新密钥加密。
+//  4. 从 sso.secret.keys 中移除旧密钥并重启服务。
+//
+// 从单一主密钥升级时，保留原来的 sso.secret.master_key 并按上述步骤配置新密钥，执行本命令后即可移除 master_key。
+func main() {
+	startup.Reencrypt(xInit.Register())
+}
@@ -0,0 +1,11 @@
+package handler
+
+// Handler 汇集 HTTP 请求的处理函数。
+//
+// 处理函数只负责参数绑定与响应输出，业务逻辑统一交由 logic 包处理。
+type Handler struct{}
+
+// New 创建一个新的 Handler 实例。
+func New() *Handler {
+	return &Handler{}
+}
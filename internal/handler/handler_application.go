@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ApplicationAccessGet 获取接入应用的访问策略，应用 UUID 取自路径参数。
+func (h *Handler) ApplicationAccessGet(c *gin.Context) {
+	applicationUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("应用 UUID 格式错误"))
+		return
+	}
+
+	access, err := logic.NewApplication(c).Access(applicationUUID)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取访问策略成功", access)
+}
+
+// ApplicationAccessUpdate 修改接入应用的访问策略，限制哪些角色或用户可以登录该应用。
+func (h *Handler) ApplicationAccessUpdate(c *gin.Context) {
+	applicationUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("应用 UUID 格式错误"))
+		return
+	}
+	var req request.ApplicationAccess
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	access, err := logic.NewApplication(c).UpdateAccess(c, applicationUUID, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "修改访问策略成功", access)
+}
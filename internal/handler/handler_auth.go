@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordLogin 处理账号密码登录。
+func (h *Handler) PasswordLogin(c *gin.Context) {
+	var req request.PasswordLogin
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	login, err := logic.NewAuth(c).PasswordLogin(c, req.Account, req.Password)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	loginSuccess(c, login)
+}
+
+// MFAEnroll 在登录过程中为必须启用两步验证的用户登记身份验证器。
+func (h *Handler) MFAEnroll(c *gin.Context) {
+	var req request.MFAChallenge
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	enrollment, err := logic.NewMFA(c).Enroll(c, req.Token)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "请使用身份验证器扫描二维码", enrollment)
+}
+
+// MFAVerifyTOTP 使用动态口令完成两步验证。
+func (h *Handler) MFAVerifyTOTP(c *gin.Context) {
+	var req request.MFAVerify
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	login, err := logic.NewMFA(c).VerifyTOTP(c, req.Token, req.Code)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	loginSuccess(c, login)
+}
+
+// MFAVerifyRecovery 使用一次性恢复码完成两步验证。
+func (h *Handler) MFAVerifyRecovery(c *gin.Context) {
+	var req request.MFAVerify
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	login, err := logic.NewMFA(c).VerifyRecovery(c, req.Token, req.Code)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	loginSuccess(c, login)
+}
+
+// MFAPasskeyBegin 开始使用通行密钥完成两步验证。
+func (h *Handler) MFAPasskeyBegin(c *gin.Context) {
+	var req request.MFAChallenge
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	ceremony, err := logic.NewMFA(c).BeginPasskey(c, req.Token)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "请按浏览器提示选择通行密钥", ceremony)
+}
+
+// MFAVerifyPasskey 使用通行密钥完成两步验证。
+func (h *Handler) MFAVerifyPasskey(c *gin.Context) {
+	var req request.PasskeyAssertion
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	login, err := logic.NewMFA(c).VerifyPasskey(c, req.Token, req.Credential)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	loginSuccess(c, login)
+}
+
+// loginSuccess 按登录结果输出成功响应，需要两步验证或修改密码时提示用户继续操作。
+func loginSuccess(c *gin.Context, login *dto.Login) {
+	if login.MFA != nil {
+		result.Success(c, "请完成两步验证", login)
+		return
+	}
+	if login.PasswordChange != nil {
+		result.Success(c, "请修改密码后继续登录", login)
+		return
+	}
+	result.Success(c, "登录成功", login)
+}
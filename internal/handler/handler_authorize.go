@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthAuthorize 处理接入应用的授权请求，跳转到接入应用的回调地址或前端登录页。
+func (h *Handler) OAuthAuthorize(c *gin.Context) {
+	var req request.Authorize
+	if err := c.ShouldBindQuery(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	location, err := logic.NewAuthorize(c).Authorize(c, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, location)
+}
+
+// OAuthToken 供接入应用的服务端使用授权码换取令牌。
+func (h *Handler) OAuthToken(c *gin.Context) {
+	var req request.OAuthToken
+	if err := c.ShouldBind(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	token, err := logic.NewAuthorize(c).Exchange(c, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取令牌成功", token)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// BindingList 列出当前登录用户绑定的第三方身份。
+func (h *Handler) BindingList(c *gin.Context) {
+	bindings, err := logic.NewBinding(c).List(c)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取绑定列表成功", bindings)
+}
+
+// BindingAuthorize 获取绑定第三方身份的授权跳转地址，提供商代码取自路径参数。
+func (h *Handler) BindingAuthorize(c *gin.Context) {
+	authorizeURL, err := logic.NewBinding(c).AuthorizeURL(c, c.Param("provider"))
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取授权地址成功", authorizeURL)
+}
+
+// BindingWechatMini 为当前登录用户绑定微信小程序身份。
+func (h *Handler) BindingWechatMini(c *gin.Context) {
+	var req request.WechatMiniLogin
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	binding, err := logic.NewWechat(c).MiniLink(c, req.Code)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "绑定成功", binding)
+}
+
+// BindingUnlink 解绑当前登录用户的一个第三方身份，绑定记录的 UUID 取自路径参数。
+func (h *Handler) BindingUnlink(c *gin.Context) {
+	bindingUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("绑定记录 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewBinding(c).Unlink(c, bindingUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "解绑成功", nil)
+}
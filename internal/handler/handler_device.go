@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DeviceList 列出当前用户的登录设备。
+func (h *Handler) DeviceList(c *gin.Context) {
+	devices, err := logic.NewDevice(c).List(c)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取登录设备列表成功", devices)
+}
+
+// DeviceRevoke 撤销当前用户的一台登录设备，令牌记录 UUID 取自路径参数。
+func (h *Handler) DeviceRevoke(c *gin.Context) {
+	tokenUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("登录设备 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewDevice(c).Revoke(c, tokenUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "已退出该设备的登录", nil)
+}
+
+// DeviceRevokeOthers 撤销当前用户除当前设备以外的全部登录设备。
+func (h *Handler) DeviceRevokeOthers(c *gin.Context) {
+	if err := logic.NewDevice(c).RevokeOthers(c); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "已退出其他设备的登录", nil)
+}
+
+// UserSignOut 由管理员强制用户在所有设备上退出登录，用户 UUID 取自路径参数。
+func (h *Handler) UserSignOut(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("用户 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewDevice(c).SignOut(c, userUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "已强制该用户在所有设备上退出登录", nil)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// EmailSendVerification 向当前登录用户的邮箱发送验证邮件。
+func (h *Handler) EmailSendVerification(c *gin.Context) {
+	if err := logic.NewEmail(c).SendVerification(c); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "验证邮件已发送，请查收", nil)
+}
+
+// EmailVerify 使用邮件中的令牌完成邮箱验证。
+func (h *Handler) EmailVerify(c *gin.Context) {
+	var req request.EmailToken
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewEmail(c).VerifyEmail(c, req.Token); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "邮箱验证成功", nil)
+}
+
+// PasswordForgot 申请通过邮件重置密码，无论邮箱是否已注册都返回相同的结果。
+func (h *Handler) PasswordForgot(c *gin.Context) {
+	var req request.PasswordForgot
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewEmail(c).ForgotPassword(c, req.Email); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "如果该邮箱已注册，你将收到重置密码的邮件", nil)
+}
+
+// PasswordReset 使用邮件中的令牌重置密码。
+func (h *Handler) PasswordReset(c *gin.Context) {
+	var req request.PasswordReset
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewEmail(c).ResetPassword(c, req.Token, req.Password); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "密码已重置，请使用新密码登录", nil)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// ExternalAuthorize 获取通用 OIDC/OAuth2 提供商的授权跳转地址，提供商代码取自路径参数。
+func (h *Handler) ExternalAuthorize(c *gin.Context) {
+	authorizeURL, err := logic.NewExternal(c).AuthorizeURL(c, c.Param("provider"), nil)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取授权地址成功", authorizeURL)
+}
+
+// ExternalCallback 处理通用 OIDC/OAuth2 提供商的授权回调，根据发起授权时的用途完成登录或绑定。
+func (h *Handler) ExternalCallback(c *gin.Context) {
+	var req request.OAuthCallback
+	if err := c.ShouldBindQuery(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	callback, err := logic.NewExternal(c).Callback(c, c.Param("provider"), req.Code, req.State)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	oauthSuccess(c, callback)
+}
+
+// oauthSuccess 按授权用途输出第三方回调的成功响应。
+func oauthSuccess(c *gin.Context, callback *dto.OAuthCallback) {
+	switch {
+	case callback.Action == constants.OAuthActionLink:
+		result.Success(c, "绑定成功", callback)
+	case callback.MFA != nil:
+		result.Success(c, "请完成两步验证", callback)
+	default:
+		result.Success(c, "登录成功", callback)
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GroupList 列出全部分组及其角色与成员数量。
+func (h *Handler) GroupList(c *gin.Context) {
+	groups, err := logic.NewGroup(c).List()
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取分组列表成功", groups)
+}
+
+// GroupGet 获取指定的分组，分组 UUID 取自路径参数。
+func (h *Handler) GroupGet(c *gin.Context) {
+	groupUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("分组 UUID 格式错误"))
+		return
+	}
+
+	group, err := logic.NewGroup(c).Get(groupUUID)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取分组成功", group)
+}
+
+// GroupCreate 创建一个分组。
+func (h *Handler) GroupCreate(c *gin.Context) {
+	var req request.GroupCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	group, err := logic.NewGroup(c).Create(c, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "创建分组成功", group)
+}
+
+// GroupUpdate 修改分组的显示名称、描述与上级分组。
+func (h *Handler) GroupUpdate(c *gin.Context) {
+	groupUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("分组 UUID 格式错误"))
+		return
+	}
+	var req request.GroupUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	group, err := logic.NewGroup(c).Update(c, groupUUID, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "修改分组成功", group)
+}
+
+// GroupDelete 删除一个没有下级分组的分组。
+func (h *Handler) GroupDelete(c *gin.Context) {
+	groupUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("分组 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewGroup(c).Delete(c, groupUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "删除分组成功", nil)
+}
+
+// GroupMemberList 列出直接加入指定分组的用户。
+func (h *Handler) GroupMemberList(c *gin.Context) {
+	groupUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("分组 UUID 格式错误"))
+		return
+	}
+
+	members, err := logic.NewGroup(c).Members(groupUUID)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取分组成员成功", members)
+}
+
+// GroupMemberAdd 将用户加入分组。
+func (h *Handler) GroupMemberAdd(c *gin.Context) {
+	groupUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("分组 UUID 格式错误"))
+		return
+	}
+	var req request.GroupMemberAdd
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewGroup(c).AddMember(c, groupUUID, &req); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "加入分组成功", nil)
+}
+
+// GroupMemberRemove 将用户移出分组，分组与用户的 UUID 均取自路径参数。
+func (h *Handler) GroupMemberRemove(c *gin.Context) {
+	groupUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("分组 UUID 格式错误"))
+		return
+	}
+	userUUID, err := uuid.Parse(c.Param("user_uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("用户 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewGroup(c).RemoveMember(c, groupUUID, userUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "移出分组成功", nil)
+}
+
+// GroupRoleAssign 为分组分配角色。
+func (h *Handler) GroupRoleAssign(c *gin.Context) {
+	groupUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("分组 UUID 格式错误"))
+		return
+	}
+	var req request.GroupRoleAssign
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewGroup(c).AssignRole(c, groupUUID, &req); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "分配角色成功", nil)
+}
+
+// GroupRoleUnassign 收回分组的角色，分组与角色的 UUID 均取自路径参数。
+func (h *Handler) GroupRoleUnassign(c *gin.Context) {
+	groupUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("分组 UUID 格式错误"))
+		return
+	}
+	roleUUID, err := uuid.Parse(c.Param("role_uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("角色 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewGroup(c).UnassignRole(c, groupUUID, roleUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "收回角色成功", nil)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserImport 上传 CSV 或 JSON 文件批量导入用户，文件取自 multipart 表单的 file 字段，导入在后台进行。
+func (h *Handler) UserImport(c *gin.Context) {
+	var req request.UserImport
+	if err := c.ShouldBind(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("请在 file 字段中上传导入文件"))
+		return
+	}
+
+	job, err := logic.NewImport(c).Import(c, header, req.Format)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "导入任务已创建，正在后台导入", job)
+}
+
+// UserImportList 列出最近的批量导入任务。
+func (h *Handler) UserImportList(c *gin.Context) {
+	jobs, err := logic.NewImport(c).List()
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取导入任务列表成功", jobs)
+}
+
+// UserImportGet 查看批量导入任务的进度与导入失败的行，任务 UUID 取自路径参数。
+func (h *Handler) UserImportGet(c *gin.Context) {
+	jobUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("导入任务 UUID 格式错误"))
+		return
+	}
+
+	detail, err := logic.NewImport(c).Get(jobUUID)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取导入任务成功", detail)
+}
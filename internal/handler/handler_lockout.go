@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LockoutList 列出因登录失败次数过多而被临时锁定的账号。
+func (h *Handler) LockoutList(c *gin.Context) {
+	lockouts, err := logic.NewLockout(c).List(c)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取锁定账号列表成功", lockouts)
+}
+
+// LockoutClear 解除账号的锁定，用户 UUID 取自路径参数。
+func (h *Handler) LockoutClear(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("用户 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewLockout(c).Clear(c, userUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "已解除账号锁定", nil)
+}
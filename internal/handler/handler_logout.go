@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// logoutPage 是结束 SSO 会话后展示的页面。
+//
+// 页面在隐藏的 iframe 中加载各接入应用的前端通道登出地址，全部加载完成或等待 5 秒后跳转到登出后的地址。
+var logoutPage = template.Must(template.New("logout").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>退出登录</title>
+</head>
+<body>
+<p>您已退出登录。</p>
+{{range .FrontchannelLogoutURIs}}<iframe src="{{.}}" hidden></iframe>
+{{end}}{{if .RedirectURI}}<script>
+(function () {
+  var target = {{.RedirectURI}};
+  var frames = document.querySelectorAll("iframe");
+  var pending = frames.length;
+  var leave = function () { location.replace(target); };
+  frames.forEach(function (frame) {
+    frame.addEventListener("load", function () { if (--pending === 0) { leave(); } });
+  });
+  if (pending === 0) { leave(); }
+  setTimeout(leave, 5000);
+})();
+</script>
+{{end}}</body>
+</html>
+`))
+
+// OAuthLogout 处理接入应用发起的登出请求，结束 SSO 会话后展示登出页面或跳转回接入应用。
+func (h *Handler) OAuthLogout(c *gin.Context) {
+	var req request.Logout
+	if err := c.ShouldBind(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	ended, err := logic.NewLogout(c).Logout(c, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	if len(ended.FrontchannelLogoutURIs) == 0 && ended.RedirectURI != "" {
+		c.Redirect(http.StatusFound, ended.RedirectURI)
+		return
+	}
+
+	var page bytes.Buffer
+	if err := logoutPage.Execute(&page, ended); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// Logout 结束 SSO 前端当前的会话，返回需要由前端在隐藏的 iframe 中加载的前端通道登出地址。
+func (h *Handler) Logout(c *gin.Context) {
+	ended, err := logic.NewLogout(c).Logout(c, &request.Logout{})
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "退出登录成功", ended)
+}
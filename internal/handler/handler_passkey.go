@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PasskeyList 列出当前登录用户注册的通行密钥。
+func (h *Handler) PasskeyList(c *gin.Context) {
+	passkeys, err := logic.NewPasskey(c).List(c)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取通行密钥列表成功", passkeys)
+}
+
+// PasskeyRegisterBegin 开始为当前登录用户注册通行密钥。
+func (h *Handler) PasskeyRegisterBegin(c *gin.Context) {
+	ceremony, err := logic.NewPasskey(c).BeginRegistration(c)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "请按浏览器提示创建通行密钥", ceremony)
+}
+
+// PasskeyRegisterFinish 完成通行密钥注册。
+func (h *Handler) PasskeyRegisterFinish(c *gin.Context) {
+	var req request.PasskeyRegister
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	passkey, err := logic.NewPasskey(c).FinishRegistration(c, req.Token, req.Nickname, req.Credential)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "通行密钥注册成功", passkey)
+}
+
+// PasskeyRename 重命名当前登录用户的一个通行密钥，通行密钥的 UUID 取自路径参数。
+func (h *Handler) PasskeyRename(c *gin.Context) {
+	passkeyUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("通行密钥 UUID 格式错误"))
+		return
+	}
+	var req request.PasskeyRename
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	passkey, err := logic.NewPasskey(c).Rename(c, passkeyUUID, req.Nickname)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "修改通行密钥名称成功", passkey)
+}
+
+// PasskeyDelete 删除当前登录用户的一个通行密钥，通行密钥的 UUID 取自路径参数。
+func (h *Handler) PasskeyDelete(c *gin.Context) {
+	passkeyUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("通行密钥 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewPasskey(c).Delete(c, passkeyUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "删除通行密钥成功", nil)
+}
+
+// PasskeyLoginBegin 开始一次通行密钥免密登录。
+func (h *Handler) PasskeyLoginBegin(c *gin.Context) {
+	ceremony, err := logic.NewPasskey(c).BeginLogin(c)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "请按浏览器提示选择通行密钥", ceremony)
+}
+
+// PasskeyLoginFinish 使用通行密钥完成免密登录。
+func (h *Handler) PasskeyLoginFinish(c *gin.Context) {
+	var req request.PasskeyAssertion
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	login, err := logic.NewPasskey(c).FinishLogin(c, req.Token, req.Credential)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	loginSuccess(c, login)
+}
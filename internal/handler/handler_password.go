@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordChange 使用登录时得到的修改密码挑战设置新密码，并继续完成登录。
+func (h *Handler) PasswordChange(c *gin.Context) {
+	var req request.PasswordChange
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	login, err := logic.NewPassword(c).Change(c, req.Token, req.Password)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	loginSuccess(c, login)
+}
+
+// PasswordUpdate 修改当前登录用户的密码，其他设备上的登录随之退出。
+func (h *Handler) PasswordUpdate(c *gin.Context) {
+	var req request.PasswordUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewPassword(c).Update(c, req.CurrentPassword, req.NewPassword); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "密码已修改，其他设备上的登录已退出", nil)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileGet 查看当前登录用户的个人资料。
+func (h *Handler) ProfileGet(c *gin.Context) {
+	profile, err := logic.NewProfile(c).Get(c)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取个人资料成功", profile)
+}
+
+// ProfileUpdate 修改当前登录用户的个人资料。
+func (h *Handler) ProfileUpdate(c *gin.Context) {
+	var req request.ProfileUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	profile, err := logic.NewProfile(c).Update(c, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "修改个人资料成功", profile)
+}
+
+// ProfileAvatarUpload 上传当前登录用户的头像，图片取自 multipart 表单的 avatar 字段。
+func (h *Handler) ProfileAvatarUpload(c *gin.Context) {
+	header, err := c.FormFile("avatar")
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("请在 avatar 字段中上传头像图片"))
+		return
+	}
+
+	profile, err := logic.NewProfile(c).UploadAvatar(c, header)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "上传头像成功", profile)
+}
+
+// ProfileAvatarRemove 移除当前登录用户的头像。
+func (h *Handler) ProfileAvatarRemove(c *gin.Context) {
+	profile, err := logic.NewProfile(c).RemoveAvatar(c)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "已移除头像", profile)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProviderPublicList 列出登录页可用的第三方登录方式。
+func (h *Handler) ProviderPublicList(c *gin.Context) {
+	providers, err := logic.NewProvider(c).PublicList()
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取登录方式成功", providers)
+}
+
+// ProviderList 列出全部第三方提供商配置。
+func (h *Handler) ProviderList(c *gin.Context) {
+	providers, err := logic.NewProvider(c).List()
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取提供商列表成功", providers)
+}
+
+// ProviderGet 获取指定的第三方提供商配置，提供商 UUID 取自路径参数。
+func (h *Handler) ProviderGet(c *gin.Context) {
+	providerUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("提供商 UUID 格式错误"))
+		return
+	}
+
+	provider, err := logic.NewProvider(c).Get(providerUUID)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取提供商成功", provider)
+}
+
+// ProviderCreate 创建一个通用驱动的第三方提供商。
+func (h *Handler) ProviderCreate(c *gin.Context) {
+	var req request.ProviderCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	provider, err := logic.NewProvider(c).Create(c, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "创建提供商成功", provider)
+}
+
+// ProviderUpdate 修改第三方提供商配置，包括启用状态、排序、端点地址与权限范围。
+func (h *Handler) ProviderUpdate(c *gin.Context) {
+	providerUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("提供商 UUID 格式错误"))
+		return
+	}
+	var req request.ProviderUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	provider, err := logic.NewProvider(c).Update(c, providerUUID, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "修改提供商成功", provider)
+}
+
+// ProviderDelete 删除一个通用驱动的第三方提供商。
+func (h *Handler) ProviderDelete(c *gin.Context) {
+	providerUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("提供商 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewProvider(c).Delete(c, providerUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "删除提供商成功", nil)
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PermissionList 列出系统中的全部权限。
+func (h *Handler) PermissionList(c *gin.Context) {
+	permissions, err := logic.NewRole(c).ListPermissions()
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取权限列表成功", permissions)
+}
+
+// RoleList 列出角色及其权限与成员数量，查询参数 application_uuid 用于只列出属于某个接入应用的角色。
+func (h *Handler) RoleList(c *gin.Context) {
+	var applicationUUID *uuid.UUID
+	if value := c.Query("application_uuid"); value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			result.Fail(c, result.ErrParameter.WithMessage("应用 UUID 格式错误"))
+			return
+		}
+		applicationUUID = &parsed
+	}
+
+	roles, err := logic.NewRole(c).List(applicationUUID)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取角色列表成功", roles)
+}
+
+// RoleGet 获取指定的角色，角色 UUID 取自路径参数。
+func (h *Handler) RoleGet(c *gin.Context) {
+	roleUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("角色 UUID 格式错误"))
+		return
+	}
+
+	role, err := logic.NewRole(c).Get(roleUUID)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取角色成功", role)
+}
+
+// RoleCreate 创建一个自定义角色。
+func (h *Handler) RoleCreate(c *gin.Context) {
+	var req request.RoleCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	role, err := logic.NewRole(c).Create(c, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "创建角色成功", role)
+}
+
+// RoleUpdate 修改角色的显示名称、描述与权限。
+func (h *Handler) RoleUpdate(c *gin.Context) {
+	roleUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("角色 UUID 格式错误"))
+		return
+	}
+	var req request.RoleUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	role, err := logic.NewRole(c).Update(c, roleUUID, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "修改角色成功", role)
+}
+
+// RoleDelete 删除一个自定义角色。
+func (h *Handler) RoleDelete(c *gin.Context) {
+	roleUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("角色 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewRole(c).Delete(c, roleUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "删除角色成功", nil)
+}
+
+// RoleMemberList 列出持有指定角色的用户。
+func (h *Handler) RoleMemberList(c *gin.Context) {
+	roleUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("角色 UUID 格式错误"))
+		return
+	}
+
+	members, err := logic.NewRole(c).Members(roleUUID)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取角色成员成功", members)
+}
+
+// RoleMemberAssign 为用户分配角色，可以指定过期时间。
+func (h *Handler) RoleMemberAssign(c *gin.Context) {
+	roleUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("角色 UUID 格式错误"))
+		return
+	}
+	var req request.RoleAssign
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewRole(c).Assign(c, roleUUID, &req); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "分配角色成功", nil)
+}
+
+// RoleMemberUnassign 收回用户持有的角色，角色与用户的 UUID 均取自路径参数。
+func (h *Handler) RoleMemberUnassign(c *gin.Context) {
+	roleUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("角色 UUID 格式错误"))
+		return
+	}
+	userUUID, err := uuid.Parse(c.Param("user_uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("用户 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewRole(c).Unassign(c, roleUUID, userUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "收回角色成功", nil)
+}
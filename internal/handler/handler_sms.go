@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// SMSLoginCode 向手机号发送登录验证码，无论手机号是否已绑定都返回相同的结果。
+func (h *Handler) SMSLoginCode(c *gin.Context) {
+	var req request.SMSCode
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewSMS(c).SendLoginCode(c, req.Phone); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "如果该手机号已绑定账号，你将收到登录验证码", nil)
+}
+
+// SMSLogin 使用手机号与短信验证码登录。
+func (h *Handler) SMSLogin(c *gin.Context) {
+	var req request.SMSLogin
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	login, err := logic.NewSMS(c).Login(c, req.Phone, req.Code)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	loginSuccess(c, login)
+}
+
+// PhoneBindCode 向当前登录用户要绑定的手机号发送验证码。
+func (h *Handler) PhoneBindCode(c *gin.Context) {
+	var req request.SMSCode
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewSMS(c).SendBindCode(c, req.Phone); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "验证码已发送", nil)
+}
+
+// PhoneBind 使用短信验证码为当前登录用户绑定手机号。
+func (h *Handler) PhoneBind(c *gin.Context) {
+	var req request.PhoneBind
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewSMS(c).BindPhone(c, req.Phone, req.Code); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "手机号绑定成功", nil)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TenantCurrent 获取当前请求所属租户的公开信息。
+func (h *Handler) TenantCurrent(c *gin.Context) {
+	result.Success(c, "获取租户信息成功", logic.NewTenant(c).Current())
+}
+
+// TenantList 列出全部租户。
+func (h *Handler) TenantList(c *gin.Context) {
+	tenants, err := logic.NewTenant(c).List()
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取租户列表成功", tenants)
+}
+
+// TenantGet 获取指定的租户，租户 UUID 取自路径参数。
+func (h *Handler) TenantGet(c *gin.Context) {
+	tenantUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("租户 UUID 格式错误"))
+		return
+	}
+
+	tenant, err := logic.NewTenant(c).Get(tenantUUID)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取租户成功", tenant)
+}
+
+// TenantCreate 创建一个租户。
+func (h *Handler) TenantCreate(c *gin.Context) {
+	var req request.TenantCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	tenant, err := logic.NewTenant(c).Create(c, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "创建租户成功", tenant)
+}
+
+// TenantUpdate 修改租户的名称、域名、签发者标识或启用状态。
+func (h *Handler) TenantUpdate(c *gin.Context) {
+	tenantUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("租户 UUID 格式错误"))
+		return
+	}
+	var req request.TenantUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	tenant, err := logic.NewTenant(c).Update(c, tenantUUID, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "修改租户成功", tenant)
+}
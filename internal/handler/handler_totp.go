@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// TOTPStatus 获取当前登录用户的两步验证状态。
+func (h *Handler) TOTPStatus(c *gin.Context) {
+	status, err := logic.NewTOTP(c).Status(c)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取两步验证状态成功", status)
+}
+
+// TOTPEnroll 为当前登录用户登记身份验证器。
+func (h *Handler) TOTPEnroll(c *gin.Context) {
+	enrollment, err := logic.NewTOTP(c).Enroll(c)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "请使用身份验证器扫描二维码", enrollment)
+}
+
+// TOTPConfirm 确认登记的身份验证器并启用两步验证，返回恢复码。
+func (h *Handler) TOTPConfirm(c *gin.Context) {
+	var req request.TOTPCode
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	recoveryCodes, err := logic.NewTOTP(c).Confirm(c, req.Code)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "两步验证已启用，请妥善保存恢复码", recoveryCodes)
+}
+
+// TOTPRecoveryCodes 重新生成恢复码。
+func (h *Handler) TOTPRecoveryCodes(c *gin.Context) {
+	var req request.TOTPCode
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	recoveryCodes, err := logic.NewTOTP(c).RegenerateRecoveryCodes(c, req.Code)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "恢复码已重新生成，请妥善保存", recoveryCodes)
+}
+
+// TOTPDisable 关闭当前登录用户的两步验证。
+func (h *Handler) TOTPDisable(c *gin.Context) {
+	var req request.TOTPCode
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewTOTP(c).Disable(c, req.Code); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "两步验证已关闭", nil)
+}
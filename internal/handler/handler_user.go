@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserSearch 按关键字与状态搜索用户，结果按注册时间倒序分页。
+func (h *Handler) UserSearch(c *gin.Context) {
+	var req request.UserSearch
+	if err := c.ShouldBindQuery(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	page, err := logic.NewUser(c).Search(&req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取用户列表成功", page)
+}
+
+// UserGet 查看用户详情，用户 UUID 取自路径参数。
+func (h *Handler) UserGet(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("用户 UUID 格式错误"))
+		return
+	}
+
+	detail, err := logic.NewUser(c).Get(userUUID)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取用户详情成功", detail)
+}
+
+// UserStatusUpdate 启用或停用账号，用户 UUID 取自路径参数。
+func (h *Handler) UserStatusUpdate(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("用户 UUID 格式错误"))
+		return
+	}
+	var req request.UserStatusUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	user, err := logic.NewUser(c).UpdateStatus(c, userUUID, *req.IsActive)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	if user.IsActive {
+		result.Success(c, "已启用该账号", user)
+		return
+	}
+	result.Success(c, "已停用该账号", user)
+}
+
+// UserPasswordSet 由管理员重置用户密码，用户 UUID 取自路径参数。
+func (h *Handler) UserPasswordSet(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("用户 UUID 格式错误"))
+		return
+	}
+	var req request.UserPasswordSet
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	if err := logic.NewUser(c).SetPassword(c, userUUID, req.Password, req.RequireChange); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "已重置该用户的密码", nil)
+}
+
+// UserDelete 删除账号，用户 UUID 取自路径参数。
+func (h *Handler) UserDelete(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage("用户 UUID 格式错误"))
+		return
+	}
+
+	if err := logic.NewUser(c).Delete(c, userUUID); err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "已删除该账号", nil)
+}
+
+// UserExport 以附件形式下载当前租户的全部用户，文件格式与批量导入相同。
+func (h *Handler) UserExport(c *gin.Context) {
+	var req request.UserExport
+	if err := c.ShouldBindQuery(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	data, err := logic.NewUser(c).Export(c, &req)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	format, contentType := constants.ImportFormatCSV, "text/csv; charset=utf-8"
+	if req.Format == constants.ImportFormatJSON {
+		format, contentType = constants.ImportFormatJSON, "application/json; charset=utf-8"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="users-%s.%s"`, time.Now().Format("20060102"), format))
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// WechatAuthorize 获取微信扫码登录或公众号网页授权的跳转地址。
+func (h *Handler) WechatAuthorize(c *gin.Context) {
+	var req request.WechatAuthorize
+	if err := c.ShouldBindQuery(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	authorizeURL, err := logic.NewWechat(c).AuthorizeURL(c, req.Provider, nil)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	result.Success(c, "获取授权地址成功", authorizeURL)
+}
+
+// WechatCallback 处理微信扫码登录与公众号网页授权的回调，根据发起授权时的用途完成登录或绑定。
+func (h *Handler) WechatCallback(c *gin.Context) {
+	var req request.OAuthCallback
+	if err := c.ShouldBindQuery(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	callback, err := logic.NewWechat(c).Callback(c, req.Code, req.State)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	oauthSuccess(c, callback)
+}
+
+// WechatMiniLogin 处理微信小程序登录。
+func (h *Handler) WechatMiniLogin(c *gin.Context) {
+	var req request.WechatMiniLogin
+	if err := c.ShouldBindJSON(&req); err != nil {
+		result.Fail(c, result.ErrParameter.WithMessage(err.Error()))
+		return
+	}
+
+	login, err := logic.NewWechat(c).MiniLogin(c, req.Code)
+	if err != nil {
+		result.Fail(c, err)
+		return
+	}
+	loginSuccess(c, login)
+}
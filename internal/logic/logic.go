@@ -0,0 +1,79 @@
+package logic
+
+import (
+	xConsts "github.com/bamboo-services/bamboo-base-go/constants"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/logout"
+	"github.com/bamboo-services/bamboo-sso/pkg/mailer"
+	"github.com/bamboo-services/bamboo-sso/pkg/password"
+	"github.com/bamboo-services/bamboo-sso/pkg/sms"
+	"github.com/bamboo-services/bamboo-sso/pkg/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// base 汇集业务逻辑所需的公共依赖，均取自 startup 注入到请求上下文中的实例。
+//
+// 字段说明：
+//   - db: 绑定了请求上下文的数据库连接实例。
+//   - rdb: Redis 客户端实例。
+//   - log: 日志记录器实例。
+//   - sso: SSO 业务配置。
+//   - webAuthn: 通行密钥依赖方实例。
+//   - policy: 密码策略。
+//   - mailer: 邮件发送器。
+//   - sms: 短信发送器。
+//   - logout: 后端通道登出通知发送器。
+//   - storage: 文件存储。
+//   - tenant: 当前请求所属的租户，由租户中间件确定。
+type base struct {
+	db       *gorm.DB
+	rdb      *redis.Client
+	log      *zap.Logger
+	sso      *config.SSO
+	webAuthn *webauthn.WebAuthn
+	policy   *password.Policy
+	mailer   mailer.Mailer
+	sms      sms.Sender
+	logout   *logout.Sender
+	storage  storage.Storage
+	tenant   *entity.Tenant
+}
+
+// newBase 从请求上下文中取出公共依赖。
+func newBase(c *gin.Context) base {
+	return base{
+		db:       c.MustGet(xConsts.ContextDatabase).(*gorm.DB).WithContext(c),
+		rdb:      c.MustGet(xConsts.ContextRedisClient).(*redis.Client),
+		log:      c.MustGet(constants.ContextLogger).(*zap.Logger),
+		sso:      c.MustGet(constants.ContextSSOConfig).(*config.SSO),
+		webAuthn: c.MustGet(constants.ContextWebAuthn).(*webauthn.WebAuthn),
+		policy:   c.MustGet(constants.ContextPasswordPolicy).(*password.Policy),
+		mailer:   c.MustGet(constants.ContextMailer).(mailer.Mailer),
+		sms:      c.MustGet(constants.ContextSMSSender).(sms.Sender),
+		logout:   c.MustGet(constants.ContextLogout).(*logout.Sender),
+		storage:  c.MustGet(constants.ContextStorage).(storage.Storage),
+		tenant:   c.MustGet(constants.ContextTenant).(*entity.Tenant),
+	}
+}
+
+// currentUser 获取认证中间件写入请求上下文的当前登录用户，未登录时返回 nil。
+func currentUser(c *gin.Context) *entity.User {
+	if user, ok := c.Get(constants.ContextUser); ok {
+		return user.(*entity.User)
+	}
+	return nil
+}
+
+// currentToken 获取认证中间件写入请求上下文的当前请求使用的令牌，未登录时返回 nil。
+func currentToken(c *gin.Context) *entity.UserToken {
+	if userToken, ok := c.Get(constants.ContextUserToken); ok {
+		return userToken.(*entity.UserToken)
+	}
+	return nil
+}
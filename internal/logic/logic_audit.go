@@ -0,0 +1,34 @@
+package logic
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"gorm.io/gorm"
+)
+
+// writeAudit 写入一条审计日志，操作者取自当前登录用户。
+//
+// 参数 tx 应与被审计的变更处于同一事务中，保证变更与审计记录同时成功或失败；
+// detail 为可选的操作详情，会被序列化为 JSON 对象。
+func writeAudit(c *gin.Context, tx *gorm.DB, action, resourceType string, resourceUUID *uuid.UUID, detail any) error {
+	auditLog := entity.AuditLog{
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceUUID: resourceUUID,
+		IPAddress:    c.ClientIP(),
+		UserAgent:    c.Request.UserAgent(),
+	}
+	if user := currentUser(c); user != nil {
+		auditLog.ActorUUID = &user.UUID
+	}
+	if detail != nil {
+		value, err := jsoniter.MarshalToString(detail)
+		if err != nil {
+			return err
+		}
+		auditLog.Detail = &value
+	}
+	return tx.Create(&auditLog).Error
+}
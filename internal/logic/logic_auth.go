@@ -0,0 +1,90 @@
+package logic
+
+import (
+	"errors"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// errCredentialInvalid 表示账号不存在或密码错误，两种情况返回相同的提示以免泄露账号是否存在。
+var errCredentialInvalid = result.ErrUnauthorized.WithMessage("账号或密码错误")
+
+// AuthLogic 负责账号密码登录。
+type AuthLogic struct {
+	base
+}
+
+// NewAuth 创建一个新的 AuthLogic 实例。
+func NewAuth(c *gin.Context) *AuthLogic {
+	return &AuthLogic{base: newBase(c)}
+}
+
+// PasswordLogin 使用用户名或邮箱与密码完成登录的第一步验证。
+//
+// 需要两步验证的用户会得到两步验证挑战，其余用户直接得到令牌；每次失败都会写入登录日志，
+// 同一账号的请求次数受限流规则约束，失败次数过多的账号会被临时锁定。
+// 密码哈希的算法或参数与当前配置不同时，验证通过后使用当前配置重新计算。
+func (a *AuthLogic) PasswordLogin(c *gin.Context, account, password string) (*dto.Login, error) {
+	if err := a.limitAccount(c, constants.RateLimitLogin, account); err != nil {
+		return nil, err
+	}
+
+	var user entity.User
+	err := a.inTenant(a.db).Where("(username = ? OR email = ?)", account, account).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		a.policy.VerifyDummy(password)
+		NewLogin(c).Failed(c, nil, constants.LoginTypePassword, nil, constants.FailureUserNotFound)
+		return nil, errCredentialInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := a.checkLockout(c, user.UUID); err != nil {
+		NewLogin(c).Failed(c, &user.UUID, constants.LoginTypePassword, nil, constants.FailureAccountLocked)
+		return nil, err
+	}
+
+	var match, rehash bool
+	if user.HasPassword() {
+		match, rehash = a.policy.Verify(*user.PasswordHash, password)
+	}
+	if !match {
+		NewLogin(c).Failed(c, &user.UUID, constants.LoginTypePassword, nil, constants.FailurePasswordInvalid)
+		return nil, errCredentialInvalid
+	}
+	if !user.IsActive {
+		NewLogin(c).Failed(c, &user.UUID, constants.LoginTypePassword, nil, constants.FailureUserInactive)
+		return nil, result.ErrForbidden.WithMessage("账号已被停用")
+	}
+	if rehash {
+		a.rehashPassword(&user, password)
+	}
+	return NewLogin(c).Succeed(c, &user, constants.LoginTypePassword, nil)
+}
+
+// rehashPassword 使用当前配置的算法与参数重新计算用户的密码哈希，失败时只记录日志，不影响登录。
+//
+// 密码本身没有变化，不更新密码修改时间与历史密码；哈希在此期间已被修改时放弃更新。
+func (a *AuthLogic) rehashPassword(user *entity.User, password string) {
+	passwordHash, err := a.policy.Hash(password)
+	if err != nil {
+		a.log.Named("AUTH").Warn("重新计算密码哈希失败", zap.String("user_uuid", user.UUID.String()), zap.Error(err))
+		return
+	}
+	tx := a.db.Model(&entity.User{}).
+		Where("uuid = ? AND password_hash = ?", user.UUID, *user.PasswordHash).
+		Update("password_hash", passwordHash)
+	if tx.Error != nil {
+		a.log.Named("AUTH").Warn("保存重新计算的密码哈希失败", zap.String("user_uuid", user.UUID.String()), zap.Error(tx.Error))
+		return
+	}
+	if tx.RowsAffected > 0 {
+		user.PasswordHash = &passwordHash
+	}
+}
@@ -0,0 +1,359 @@
+package logic
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// errAuthorizeRequestInvalid 表示暂存的授权请求不存在或已过期。
+	errAuthorizeRequestInvalid = result.ErrParameter.WithMessage("授权请求不存在或已过期，请重新发起授权")
+	// errApplicationInvalid 表示接入应用不存在或已停用。
+	errApplicationInvalid = result.ErrParameter.WithMessage("应用不存在或已停用")
+	// errApplicationCredential 表示接入应用的应用标识符或密钥错误。
+	errApplicationCredential = result.ErrUnauthorized.WithMessage("应用标识符或密钥错误")
+	// errAuthorizationCodeInvalid 表示授权码不存在、已过期、已被使用或与请求不匹配。
+	errAuthorizationCodeInvalid = result.ErrParameter.WithMessage("授权码无效或已过期")
+)
+
+// authorizeRequest 表示暂存在 Redis 中、等待用户登录的授权请求。
+//
+// 字段说明：
+//   - Params: 接入应用发起授权时携带的参数。
+//   - CreatedAt: 授权请求的发起时间，prompt=login 要求用户在此之后重新登录。
+type authorizeRequest struct {
+	Params    request.Authorize `json:"params"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// AuthorizeLogic 负责接入应用的授权：复用 SSO 会话签发授权码，以及接入应用使用授权码换取令牌。
+type AuthorizeLogic struct {
+	base
+}
+
+// NewAuthorize 创建一个新的 AuthorizeLogic 实例。
+func NewAuthorize(c *gin.Context) *AuthorizeLogic {
+	return &AuthorizeLogic{base: newBase(c)}
+}
+
+// Authorize 处理接入应用的授权请求，返回浏览器需要跳转的地址。
+//
+// 浏览器持有满足要求的 SSO 会话时直接签发授权码并跳转回接入应用，用户无需再次输入凭据；
+// 否则暂存授权请求并跳转到前端登录页，prompt=none 时则以 login_required 跳转回接入应用。
+// prompt=login 要求用户在本次授权请求发起后重新登录，max_age 要求最近一次登录距今不超过指定秒数。
+// 用户不在应用访问策略的允许范围内时，写入授权日志并以 access_denied 跳转回接入应用。
+// 应用或回调地址无效时不会跳转，直接返回错误，避免把用户带到未登记的地址。
+func (a *AuthorizeLogic) Authorize(c *gin.Context, req *request.Authorize) (string, error) {
+	pending := &authorizeRequest{Params: *req, CreatedAt: time.Now()}
+	if req.Request != "" {
+		loaded, err := a.loadAuthorizeRequest(c, req.Request)
+		if err != nil {
+			return "", err
+		}
+		pending = loaded
+	}
+	params := &pending.Params
+
+	application, err := a.findApplication(params.ApplicationID)
+	if err != nil {
+		return "", err
+	}
+	if !redirectAllowed(application, params.RedirectURI) {
+		return "", result.ErrParameter.WithMessage("回调地址未在应用中登记")
+	}
+	if params.ResponseType != "code" {
+		return redirectWithQuery(params.RedirectURI, map[string]string{
+			"error": constants.AuthorizeErrorUnsupportedResponseType,
+			"state": params.State,
+		})
+	}
+
+	sessionID, session, err := a.currentSession(c)
+	if err != nil {
+		return "", err
+	}
+	var user *entity.User
+	if session != nil && sessionSatisfies(session, pending) {
+		user, err = findUser(a.db, session.UserUUID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+	}
+	if user == nil || !user.IsActive {
+		if params.Prompt == constants.PromptNone {
+			return redirectWithQuery(params.RedirectURI, map[string]string{
+				"error": constants.AuthorizeErrorLoginRequired,
+				"state": params.State,
+			})
+		}
+		return a.loginRedirect(c, req.Request, pending)
+	}
+
+	allowed, err := a.accessAllowed(application, user.UUID)
+	if err != nil {
+		return "", err
+	}
+	values := map[string]string{"state": params.State}
+	if allowed {
+		code, err := a.issueCode(c, user.UUID, application.UUID, params.RedirectURI, sessionID)
+		if err != nil {
+			return "", err
+		}
+		values["code"] = code
+	} else {
+		a.record(c, nil, application.UUID, &user.UUID, constants.AuthorizeFailureAccessDenied)
+		values["error"] = constants.AuthorizeErrorAccessDenied
+	}
+	if req.Request != "" {
+		if err := a.rdb.Del(c, fmt.Sprintf(constants.RedisAuthorize, req.Request)).Err(); err != nil {
+			return "", err
+		}
+	}
+	return redirectWithQuery(params.RedirectURI, values)
+}
+
+// Exchange 校验接入应用的身份与授权码，为授权码对应的用户签发属于该应用的令牌。
+//
+// 授权码只能使用一次，签发授权码时的 SSO 会话结束后授权码同样失效；每次校验都会写入授权日志。
+// 请求按应用标识符限流以防止穷举应用密钥，应用认证通过后再按应用限流。
+func (a *AuthorizeLogic) Exchange(c *gin.Context, req *request.OAuthToken) (*dto.Token, error) {
+	if err := a.limitAccount(c, constants.RateLimitToken, req.ApplicationID); err != nil {
+		return nil, err
+	}
+	application, err := a.findApplication(req.ApplicationID)
+	if errors.Is(err, errApplicationInvalid) {
+		return nil, errApplicationCredential
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(application.ApplicationSecret), []byte(req.ApplicationSecret)) != 1 {
+		return nil, errApplicationCredential
+	}
+	if err := a.limitApplication(c, constants.RateLimitToken, application.UUID); err != nil {
+		return nil, err
+	}
+
+	var code entity.AuthorizationCode
+	err = a.db.Where("code = ? AND application_uuid = ?", req.Code, application.UUID).First(&code).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		a.record(c, nil, application.UUID, nil, constants.AuthorizeFailureCodeInvalid)
+		return nil, errAuthorizationCodeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !code.IsValid() {
+		a.record(c, &code.UUID, application.UUID, &code.UserUUID, constants.AuthorizeFailureCodeInvalid)
+		return nil, errAuthorizationCodeInvalid
+	}
+	if code.RedirectURI != req.RedirectURI {
+		a.record(c, &code.UUID, application.UUID, &code.UserUUID, constants.AuthorizeFailureRedirectInvalid)
+		return nil, errAuthorizationCodeInvalid
+	}
+	if code.SessionID != nil {
+		alive, err := a.rdb.Exists(c, fmt.Sprintf(constants.RedisSession, *code.SessionID)).Result()
+		if err != nil {
+			return nil, err
+		}
+		if alive == 0 {
+			a.record(c, &code.UUID, application.UUID, &code.UserUUID, constants.AuthorizeFailureCodeInvalid)
+			return nil, errAuthorizationCodeInvalid
+		}
+	}
+	user, err := findUser(a.db, code.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		a.record(c, &code.UUID, application.UUID, &code.UserUUID, constants.AuthorizeFailureUserInactive)
+		return nil, result.ErrForbidden.WithMessage("账号已被停用")
+	}
+
+	// 并发提交同一授权码时只有一次能够成功作废授权码
+	claimed := a.db.Model(&entity.AuthorizationCode{}).Where("uuid = ? AND is_active = ?", code.UUID, true).Updates(map[string]any{
+		"is_active":    false,
+		"usage_count":  gorm.Expr("usage_count + 1"),
+		"last_used_at": time.Now(),
+	})
+	if claimed.Error != nil {
+		return nil, claimed.Error
+	}
+	if claimed.RowsAffected == 0 {
+		a.record(c, &code.UUID, application.UUID, &code.UserUUID, constants.AuthorizeFailureCodeInvalid)
+		return nil, errAuthorizationCodeInvalid
+	}
+
+	var sessionID string
+	if code.SessionID != nil {
+		sessionID = *code.SessionID
+	}
+	token, err := NewToken(c).Issue(c, user.UUID, sessionID, &application.UUID)
+	if err != nil {
+		return nil, err
+	}
+	a.record(c, &code.UUID, application.UUID, &code.UserUUID, "")
+	return token, nil
+}
+
+// findApplication 根据应用标识符查询当前租户中已启用的接入应用。
+func (b *base) findApplication(applicationID string) (*entity.Application, error) {
+	var application entity.Application
+	err := b.inTenant(b.db).Where("application_id = ?", applicationID).First(&application).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errApplicationInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !application.IsActive {
+		return nil, errApplicationInvalid
+	}
+	return &application, nil
+}
+
+// loginRedirect 暂存授权请求，返回携带授权请求编号的前端登录页地址。
+//
+// 已暂存的授权请求沿用原编号，用户未完成登录就返回时不会重复创建。
+func (a *AuthorizeLogic) loginRedirect(c *gin.Context, requestID string, pending *authorizeRequest) (string, error) {
+	if a.sso.Session.LoginURL == "" {
+		return "", result.ErrServer.WithMessage("未配置登录页地址")
+	}
+	if requestID == "" {
+		value, err := jsoniter.MarshalToString(pending)
+		if err != nil {
+			return "", err
+		}
+		requestID = utility.RandomToken(24)
+		if err := a.rdb.Set(c, fmt.Sprintf(constants.RedisAuthorize, requestID), value, a.sso.OAuth.StateTTL).Err(); err != nil {
+			return "", err
+		}
+	}
+	return redirectWithQuery(a.sso.Session.LoginURL, map[string]string{"authorize_request": requestID})
+}
+
+// loadAuthorizeRequest 读取暂存的授权请求。
+func (a *AuthorizeLogic) loadAuthorizeRequest(c *gin.Context, requestID string) (*authorizeRequest, error) {
+	value, err := a.rdb.Get(c, fmt.Sprintf(constants.RedisAuthorize, requestID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, errAuthorizeRequestInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pending authorizeRequest
+	if err := jsoniter.UnmarshalFromString(value, &pending); err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
+
+// issueCode 为用户签发一枚属于接入应用的授权码，并记录签发时的浏览器信息。
+func (a *AuthorizeLogic) issueCode(c *gin.Context, userUUID, applicationUUID uuid.UUID, redirectURI, sessionID string) (string, error) {
+	code := entity.AuthorizationCode{
+		Code:            utility.RandomToken(32),
+		UserUUID:        userUUID,
+		ApplicationUUID: applicationUUID,
+		RedirectURI:     redirectURI,
+		SessionID:       utility.NilIfBlank(sessionID),
+		UserAgent:       c.Request.UserAgent(),
+		IPAddress:       c.ClientIP(),
+		ExpiresAt:       time.Now().Add(a.sso.OAuth.CodeTTL),
+	}
+	if err := a.db.Create(&code).Error; err != nil {
+		return "", err
+	}
+	return code.Code, nil
+}
+
+// record 写入授权日志，reason 为空表示校验成功；写入失败只记录日志而不影响校验结果。
+func (a *AuthorizeLogic) record(c *gin.Context, codeUUID *uuid.UUID, applicationUUID uuid.UUID, userUUID *uuid.UUID, reason string) {
+	authorizationLog := entity.AuthorizationLog{
+		AuthorizationCodeUUID: codeUUID,
+		ApplicationUUID:       applicationUUID,
+		UserUUID:              userUUID,
+		RequestIPAddress:      c.ClientIP(),
+		RequestUserAgent:      c.Request.UserAgent(),
+		IsSuccess:             reason == "",
+		FailureReason:         utility.NilIfBlank(reason),
+	}
+	if err := a.db.Create(&authorizationLog).Error; err != nil {
+		a.log.Named("AUTHORIZE").Warn("写入授权日志失败", zap.Error(err))
+	}
+}
+
+// sessionSatisfies 检查 SSO 会话是否满足授权请求对登录时间的要求。
+//
+// 用户在授权请求发起后完成的登录总是满足要求，否则 prompt=login 与 max_age=0 要求重新登录，
+// max_age 大于 0 时要求最近一次登录距今不超过指定秒数。
+func sessionSatisfies(session *ssoSession, pending *authorizeRequest) bool {
+	if session.AuthTime.After(pending.CreatedAt) {
+		return true
+	}
+	if pending.Params.Prompt == constants.PromptLogin {
+		return false
+	}
+	if maxAge := pending.Params.MaxAge; maxAge != nil {
+		return *maxAge > 0 && time.Since(session.AuthTime) <= time.Duration(*maxAge)*time.Second
+	}
+	return true
+}
+
+// redirectAllowed 检查回调地址是否已在接入应用中登记。
+//
+// 登记项为 "*" 时允许任意地址；登记项只包含协议与主机时允许该来源下的任意路径，否则要求完全一致。
+func redirectAllowed(application *entity.Application, redirectURI string) bool {
+	target, err := url.Parse(redirectURI)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" || target.Fragment != "" {
+		return false
+	}
+	var allowed []string
+	if application.RedirectURIs != nil {
+		if err := jsoniter.UnmarshalFromString(*application.RedirectURIs, &allowed); err != nil {
+			return false
+		}
+	}
+	return slices.ContainsFunc(allowed, func(entry string) bool {
+		if entry == "*" || entry == redirectURI {
+			return true
+		}
+		origin, err := url.Parse(entry)
+		if err != nil || (origin.Path != "" && origin.Path != "/") || origin.RawQuery != "" {
+			return false
+		}
+		return origin.Scheme == target.Scheme && origin.Host == target.Host
+	})
+}
+
+// redirectWithQuery 在地址原有的查询参数上追加参数，值为空的参数会被忽略。
+func redirectWithQuery(rawURL string, values map[string]string) (string, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := target.Query()
+	for key, value := range values {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	target.RawQuery = query.Encode()
+	return target.String(), nil
+}
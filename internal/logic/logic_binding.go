@@ -0,0 +1,273 @@
+package logic
+
+import (
+	"errors"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BindingLogic 负责当前登录用户对第三方身份的查询、绑定与解绑。
+type BindingLogic struct {
+	base
+}
+
+// NewBinding 创建一个新的 BindingLogic 实例。
+func NewBinding(c *gin.Context) *BindingLogic {
+	return &BindingLogic{base: newBase(c)}
+}
+
+// errLastLoginMethod 表示要解绑的身份是用户唯一的登录方式。
+var errLastLoginMethod = result.ErrConflict.WithMessage("这是账号唯一的登录方式，请先设置密码或绑定其他登录方式")
+
+// List 列出当前登录用户绑定的全部第三方身份。
+func (b *BindingLogic) List(c *gin.Context) ([]*dto.Binding, error) {
+	return userBindings(b.db, currentUser(c).UUID)
+}
+
+// AuthorizeURL 为当前登录用户生成绑定第三方身份的授权地址，授权完成后由对应的登录回调完成绑定。
+func (b *BindingLogic) AuthorizeURL(c *gin.Context, providerCode string) (*dto.AuthorizeURL, error) {
+	userUUID := currentUser(c).UUID
+	switch providerCode {
+	case constants.ProviderWechat, constants.ProviderWechatMP:
+		return NewWechat(c).AuthorizeURL(c, providerCode, &userUUID)
+	case constants.ProviderWechatMini:
+		return nil, result.ErrParameter.WithMessage("小程序请使用 wx.login 获取的 code 直接绑定")
+	default:
+		return NewExternal(c).AuthorizeURL(c, providerCode, &userUUID)
+	}
+}
+
+// Unlink 解绑当前登录用户的一个第三方身份并写入审计日志。
+//
+// 用户未设置密码时，不允许解绑最后一个有效的第三方身份，以免账号无法再登录。
+// 解绑前会锁定用户记录，避免并发解绑绕过该检查。
+func (b *BindingLogic) Unlink(c *gin.Context, bindingUUID uuid.UUID) error {
+	userUUID := currentUser(c).UUID
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "uuid = ?", userUUID).Error; err != nil {
+			return err
+		}
+
+		resourceType, found, err := findBinding(tx, userUUID, bindingUUID)
+		if err != nil {
+			return err
+		}
+
+		if found.isActive && !user.HasPassword() {
+			count, err := countLoginMethods(tx, &user)
+			if err != nil {
+				return err
+			}
+			if count <= 1 {
+				return errLastLoginMethod
+			}
+		}
+
+		if err := tx.Delete(found.model, "uuid = ?", bindingUUID).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditBindingUnlink, resourceType, &bindingUUID, map[string]any{
+			"user_uuid": userUUID,
+			"provider":  found.binding.ProviderCode,
+			"account":   found.binding.Account,
+		})
+	})
+}
+
+// foundBinding 表示按 UUID 查找到的绑定记录。
+type foundBinding struct {
+	model    any          // 绑定记录所属的实体，用于删除
+	binding  *dto.Binding // 绑定记录的展示信息，用于写入审计日志
+	isActive bool         // 绑定是否激活
+}
+
+// findBinding 在各绑定表中查找属于指定用户的绑定记录，返回其资源类型与记录信息。
+func findBinding(tx *gorm.DB, userUUID, bindingUUID uuid.UUID) (string, *foundBinding, error) {
+	var wechatAccount entity.UserThirdPartyWechat
+	if err := firstBinding(tx, &wechatAccount, userUUID, bindingUUID); err == nil {
+		return constants.ResourceWechat, &foundBinding{&wechatAccount, wechatBinding(wechatAccount.Provider, &wechatAccount), wechatAccount.IsActive}, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil, err
+	}
+
+	var githubAccount entity.UserThirdPartyGithub
+	if err := firstBinding(tx, &githubAccount, userUUID, bindingUUID); err == nil {
+		return constants.ResourceGithub, &foundBinding{&githubAccount, githubBinding(githubAccount.Provider, &githubAccount), githubAccount.IsActive}, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil, err
+	}
+
+	var qqAccount entity.UserThirdPartyQQ
+	if err := firstBinding(tx, &qqAccount, userUUID, bindingUUID); err == nil {
+		return constants.ResourceQQ, &foundBinding{&qqAccount, qqBinding(qqAccount.Provider, &qqAccount), qqAccount.IsActive}, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil, err
+	}
+
+	var identity entity.UserExternalIdentity
+	if err := firstBinding(tx, &identity, userUUID, bindingUUID); err == nil {
+		return constants.ResourceExternalIdentity, &foundBinding{&identity, externalBinding(identity.Provider, &identity), identity.IsActive}, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil, err
+	}
+
+	return "", nil, result.ErrNotFound.WithMessage("绑定记录不存在")
+}
+
+// firstBinding 查询属于指定用户的一条绑定记录，并预加载其提供商。
+func firstBinding(tx *gorm.DB, model any, userUUID, bindingUUID uuid.UUID) error {
+	return tx.Preload("Provider").Where("uuid = ? AND user_uuid = ?", bindingUUID, userUUID).First(model).Error
+}
+
+// countLoginMethods 统计用户当前可用的登录方式数量：已设置的密码、已验证的手机号、各有效的第三方绑定与已注册的通行密钥。
+func countLoginMethods(tx *gorm.DB, user *entity.User) (int64, error) {
+	var total int64
+	if user.HasPassword() {
+		total++
+	}
+	if user.IsPhoneVerified() {
+		total++
+	}
+	for _, model := range []any{
+		&entity.UserThirdPartyWechat{},
+		&entity.UserThirdPartyGithub{},
+		&entity.UserThirdPartyQQ{},
+		&entity.UserExternalIdentity{},
+	} {
+		var count int64
+		if err := tx.Model(model).Where("user_uuid = ? AND is_active = ?", user.UUID, true).Count(&count).Error; err != nil {
+			return 0, err
+		}
+		total += count
+	}
+
+	var passkeys int64
+	if err := tx.Model(&entity.UserWebAuthnCredential{}).Where("user_uuid = ?", user.UUID).Count(&passkeys).Error; err != nil {
+		return 0, err
+	}
+	return total + passkeys, nil
+}
+
+// providerInfo 提取提供商的代码与名称，提供商记录缺失时返回空字符串。
+func providerInfo(provider *entity.ThirdPartyProvider) (string, string) {
+	if provider == nil {
+		return "", ""
+	}
+	return provider.Code, provider.Name
+}
+
+// wechatBinding 将微信绑定记录转换为展示信息。
+func wechatBinding(provider *entity.ThirdPartyProvider, account *entity.UserThirdPartyWechat) *dto.Binding {
+	code, name := providerInfo(provider)
+	return &dto.Binding{
+		UUID:         account.UUID,
+		ProviderUUID: account.ProviderUUID,
+		ProviderCode: code,
+		ProviderName: name,
+		Account:      account.OpenID,
+		Nickname:     account.Nickname,
+		Avatar:       account.Avatar,
+		IsActive:     account.IsActive,
+		FirstBindAt:  account.FirstBindAt,
+		LastLoginAt:  account.LastLoginAt,
+	}
+}
+
+// githubBinding 将 Github 绑定记录转换为展示信息。
+func githubBinding(provider *entity.ThirdPartyProvider, account *entity.UserThirdPartyGithub) *dto.Binding {
+	code, name := providerInfo(provider)
+	return &dto.Binding{
+		UUID:         account.UUID,
+		ProviderUUID: account.ProviderUUID,
+		ProviderCode: code,
+		ProviderName: name,
+		Account:      account.Login,
+		Nickname:     account.Name,
+		Avatar:       account.Avatar,
+		IsActive:     account.IsActive,
+		FirstBindAt:  account.FirstBindAt,
+		LastLoginAt:  account.LastLoginAt,
+	}
+}
+
+// qqBinding 将 QQ 绑定记录转换为展示信息。
+func qqBinding(provider *entity.ThirdPartyProvider, account *entity.UserThirdPartyQQ) *dto.Binding {
+	code, name := providerInfo(provider)
+	return &dto.Binding{
+		UUID:         account.UUID,
+		ProviderUUID: account.ProviderUUID,
+		ProviderCode: code,
+		ProviderName: name,
+		Account:      account.OpenID,
+		Nickname:     account.Nickname,
+		Avatar:       account.FigureurlQQ2,
+		IsActive:     account.IsActive,
+		FirstBindAt:  account.FirstBindAt,
+		LastLoginAt:  account.LastLoginAt,
+	}
+}
+
+// externalBinding 将通用外部身份转换为展示信息，上游返回了邮箱时以邮箱作为账号标识。
+func externalBinding(provider *entity.ThirdPartyProvider, identity *entity.UserExternalIdentity) *dto.Binding {
+	code, name := providerInfo(provider)
+	account := identity.Subject
+	if identity.Email != nil {
+		account = *identity.Email
+	}
+	return &dto.Binding{
+		UUID:         identity.UUID,
+		ProviderUUID: identity.ProviderUUID,
+		ProviderCode: code,
+		ProviderName: name,
+		Account:      account,
+		IsActive:     identity.IsActive,
+		FirstBindAt:  identity.FirstBindAt,
+		LastLoginAt:  identity.LastLoginAt,
+	}
+}
+
+// userBindings 列出指定用户绑定的全部第三方身份。
+func userBindings(tx *gorm.DB, userUUID uuid.UUID) ([]*dto.Binding, error) {
+	bindings := make([]*dto.Binding, 0)
+
+	var wechatAccounts []*entity.UserThirdPartyWechat
+	if err := tx.Preload("Provider").Where(&entity.UserThirdPartyWechat{UserUUID: userUUID}).Find(&wechatAccounts).Error; err != nil {
+		return nil, err
+	}
+	for _, account := range wechatAccounts {
+		bindings = append(bindings, wechatBinding(account.Provider, account))
+	}
+
+	var githubAccounts []*entity.UserThirdPartyGithub
+	if err := tx.Preload("Provider").Where(&entity.UserThirdPartyGithub{UserUUID: userUUID}).Find(&githubAccounts).Error; err != nil {
+		return nil, err
+	}
+	for _, account := range githubAccounts {
+		bindings = append(bindings, githubBinding(account.Provider, account))
+	}
+
+	var qqAccounts []*entity.UserThirdPartyQQ
+	if err := tx.Preload("Provider").Where(&entity.UserThirdPartyQQ{UserUUID: userUUID}).Find(&qqAccounts).Error; err != nil {
+		return nil, err
+	}
+	for _, account := range qqAccounts {
+		bindings = append(bindings, qqBinding(account.Provider, account))
+	}
+
+	var identities []*entity.UserExternalIdentity
+	if err := tx.Preload("Provider").Where(&entity.UserExternalIdentity{UserUUID: userUUID}).Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	for _, identity := range identities {
+		bindings = append(bindings, externalBinding(identity.Provider, identity))
+	}
+	return bindings, nil
+}
@@ -0,0 +1,192 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// errDeviceNotFound 表示登录设备不存在、不属于当前用户或已退出登录。
+var errDeviceNotFound = result.ErrNotFound.WithMessage("登录设备不存在或已退出登录")
+
+// DeviceLogic 负责用户的登录设备管理：查看与撤销自己的登录设备，以及管理员强制用户在所有设备上退出登录。
+//
+// 每枚未撤销且未过期的用户令牌即为一台登录设备；令牌属于某个 SSO 会话时，撤销它会结束整个会话，
+// 会话中签发给接入应用的令牌一并撤销，并通知这些接入应用。
+type DeviceLogic struct {
+	base
+}
+
+// NewDevice 创建一个新的 DeviceLogic 实例。
+func NewDevice(c *gin.Context) *DeviceLogic {
+	return &DeviceLogic{base: newBase(c)}
+}
+
+// List 列出当前用户的登录设备，按最近使用时间倒序排列。
+func (d *DeviceLogic) List(c *gin.Context) ([]*dto.Device, error) {
+	user, current := currentUser(c), currentToken(c)
+	var userTokens []entity.UserToken
+	err := d.db.Preload("Application").
+		Where("user_uuid = ? AND is_revoked = ? AND refresh_token_expires_at > ?", user.UUID, false, time.Now()).
+		Order("last_used_at DESC NULLS LAST").Order("created_at DESC").
+		Find(&userTokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]*dto.Device, 0, len(userTokens))
+	for _, userToken := range userTokens {
+		userAgent := ""
+		if userToken.UserAgent != nil {
+			userAgent = *userToken.UserAgent
+		}
+		device := &dto.Device{
+			UUID:       userToken.UUID,
+			Client:     utility.ParseUserAgent(userAgent),
+			DeviceInfo: userToken.DeviceInfo,
+			IPAddress:  userToken.IPAddress,
+			LastUsedAt: userToken.LastUsedAt,
+			CreatedAt:  userToken.CreatedAt,
+			IsCurrent:  current != nil && userToken.UUID == current.UUID,
+		}
+		if userToken.Application != nil {
+			device.Application = &userToken.Application.Name
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// Revoke 撤销当前用户的一台登录设备，撤销当前请求使用的令牌等同于退出登录。
+func (d *DeviceLogic) Revoke(c *gin.Context, tokenUUID uuid.UUID) error {
+	user := currentUser(c)
+	var userToken entity.UserToken
+	err := d.db.Where("uuid = ? AND user_uuid = ?", tokenUUID, user.UUID).First(&userToken).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errDeviceNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !userToken.IsValid() {
+		return errDeviceNotFound
+	}
+
+	if userToken.SessionID != nil {
+		if _, err := d.endSession(c, *userToken.SessionID, user.UUID); err != nil {
+			return err
+		}
+	} else {
+		if err := d.db.Model(&userToken).Update("is_revoked", true).Error; err != nil {
+			return err
+		}
+		if err := d.invalidateAuth(c, user.UUID); err != nil {
+			return err
+		}
+	}
+	return writeAudit(c, d.db, constants.AuditDeviceRevoke, constants.ResourceUserToken, &userToken.UUID, map[string]any{
+		"ip_address": userToken.IPAddress,
+		"user_agent": userToken.UserAgent,
+	})
+}
+
+// RevokeOthers 撤销当前用户除当前设备以外的全部登录设备。
+//
+// 当前请求所属的 SSO 会话保持不变，会话中签发给接入应用的令牌同样保留。
+func (d *DeviceLogic) RevokeOthers(c *gin.Context) error {
+	user := currentUser(c)
+	ended, err := d.revokeOtherDevices(c, user.UUID, currentToken(c))
+	if err != nil {
+		return err
+	}
+	return writeAudit(c, d.db, constants.AuditDeviceRevokeOthers, constants.ResourceUser, &user.UUID, map[string]any{
+		"sessions": ended,
+	})
+}
+
+// SignOut 由管理员强制用户在所有设备上退出登录，结束其全部 SSO 会话并撤销全部令牌。
+//
+// 与其他账号管理操作相同，操作者需要能够管理该用户，超级管理员只能由超级管理员强制退出登录。
+func (d *DeviceLogic) SignOut(c *gin.Context, userUUID uuid.UUID) error {
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		_, err := d.lockManageable(c, tx, userUUID, false)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	ended, err := d.signOutUser(c, userUUID)
+	if err != nil {
+		return err
+	}
+	return writeAudit(c, d.db, constants.AuditUserSignOut, constants.ResourceUser, &userUUID, map[string]any{
+		"sessions": ended,
+	})
+}
+
+// revokeOtherDevices 撤销用户除 current 所在设备以外的全部登录设备：结束其他 SSO 会话并通知接入应用，
+// 撤销其他令牌并清除认证缓存，返回结束的会话数。
+func (b *base) revokeOtherDevices(c *gin.Context, userUUID uuid.UUID, current *entity.UserToken) (int, error) {
+	keep := ""
+	if current.SessionID != nil {
+		keep = *current.SessionID
+	}
+	ended, err := b.endUserSessions(c, userUUID, keep)
+	if err != nil {
+		return ended, err
+	}
+
+	query := b.db.Model(&entity.UserToken{}).Where("user_uuid = ? AND uuid <> ? AND is_revoked = ?", userUUID, current.UUID, false)
+	if keep != "" {
+		query = query.Where("session_id IS NULL OR session_id <> ?", keep)
+	}
+	if err := query.Update("is_revoked", true).Error; err != nil {
+		return ended, err
+	}
+	return ended, b.invalidateAuth(c, userUUID)
+}
+
+// signOutUser 使用户在所有设备上退出登录：结束其全部 SSO 会话并通知接入应用，撤销全部令牌并清除认证缓存，返回结束的会话数。
+func (b *base) signOutUser(c *gin.Context, userUUID uuid.UUID) (int, error) {
+	ended, err := b.endUserSessions(c, userUUID, "")
+	if err != nil {
+		return ended, err
+	}
+	if err := revokeUserTokens(b.db, userUUID); err != nil {
+		return ended, err
+	}
+	if err := b.destroyUserSessions(c, userUUID); err != nil {
+		return ended, err
+	}
+	return ended, b.invalidateAuth(c, userUUID)
+}
+
+// endUserSessions 结束用户除 keep 以外的全部 SSO 会话并通知接入应用，返回结束的会话数。
+//
+// 接入应用的前端通道登出需要在被结束会话的浏览器中完成，这里无法触发，只投递后端通道通知。
+func (b *base) endUserSessions(c *gin.Context, userUUID uuid.UUID, keep string) (int, error) {
+	sessionIDs, err := b.rdb.SMembers(c, fmt.Sprintf(constants.RedisUserSessions, userUUID)).Result()
+	if err != nil {
+		return 0, err
+	}
+	ended := 0
+	for _, sessionID := range sessionIDs {
+		if sessionID == keep {
+			continue
+		}
+		if _, err := b.endSession(c, sessionID, userUUID); err != nil {
+			return ended, err
+		}
+		ended++
+	}
+	return ended, nil
+}
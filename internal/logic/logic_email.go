@@ -0,0 +1,328 @@
+package logic
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/mailer"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 邮件令牌的用途，签名与 Redis 键名中均包含用途，不同用途的令牌不能混用。
+const (
+	emailPurposeVerify = "verify" // 验证邮箱
+	emailPurposeReset  = "reset"  // 重置密码
+)
+
+// mailSendTimeout 是异步发送邮件的超时时间。
+const mailSendTimeout = 30 * time.Second
+
+// errEmailTokenInvalid 表示邮件令牌签名错误、已过期或已被使用。
+var errEmailTokenInvalid = result.ErrParameter.WithMessage("链接无效或已过期，请重新获取")
+
+// emailToken 表示一枚邮件令牌在 Redis 中保存的上下文。
+//
+// 字段说明：
+//   - UserUUID: 令牌所属的用户UUID。
+//   - Email: 签发时用户的邮箱，用户修改邮箱后令牌失效。
+//   - Stamp: 签发时密码哈希的摘要，仅用于重置密码；密码被修改后令牌失效。
+type emailToken struct {
+	UserUUID uuid.UUID `json:"user_uuid"`
+	Email    string    `json:"email"`
+	Stamp    string    `json:"stamp,omitempty"`
+}
+
+// EmailLogic 负责邮箱验证与通过邮件找回密码。
+type EmailLogic struct {
+	base
+}
+
+// NewEmail 创建一个新的 EmailLogic 实例。
+func NewEmail(c *gin.Context) *EmailLogic {
+	return &EmailLogic{base: newBase(c)}
+}
+
+// SendVerification 向当前登录用户的邮箱发送验证邮件。
+func (e *EmailLogic) SendVerification(c *gin.Context) error {
+	user := currentUser(c)
+	if user.Email == nil {
+		return result.ErrParameter.WithMessage("尚未设置邮箱")
+	}
+	if user.IsEmailVerified() {
+		return result.ErrConflict.WithMessage("邮箱已验证")
+	}
+	if err := e.limitAccount(c, constants.RateLimitCode, user.UUID.String()); err != nil {
+		return err
+	}
+
+	token, err := e.newEmailToken(c, emailPurposeVerify, &emailToken{UserUUID: user.UUID, Email: *user.Email}, e.sso.Email.VerifyTTL)
+	if err != nil {
+		return err
+	}
+	message, err := e.emailMessage(mailer.TemplateVerifyEmail, user, e.sso.Email.VerifyURL, token, e.sso.Email.VerifyTTL)
+	if err != nil {
+		return err
+	}
+	if err := e.mailer.Send(c, message); err != nil {
+		e.log.Named("MAIL").Error("发送验证邮件失败", zap.Stringer("user", user.UUID), zap.Error(err))
+		return result.ErrServer.WithMessage("邮件发送失败，请稍后重试")
+	}
+	return nil
+}
+
+// VerifyEmail 使用邮件中的令牌完成邮箱验证。
+func (e *EmailLogic) VerifyEmail(c *gin.Context, token string) error {
+	value, err := e.consumeEmailToken(c, emailPurposeVerify, token)
+	if err != nil {
+		return err
+	}
+
+	err = e.db.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "uuid = ?", value.UserUUID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errEmailTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if user.Email == nil || *user.Email != value.Email {
+			return errEmailTokenInvalid
+		}
+		if user.EmailVerifiedAt != nil {
+			return nil
+		}
+
+		if err := tx.Model(&user).Update("email_verified_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditEmailVerify, constants.ResourceUser, &user.UUID, map[string]any{
+			"email": value.Email,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return e.invalidateAuth(c, value.UserUUID)
+}
+
+// ForgotPassword 向邮箱对应的用户发送重置密码邮件。
+//
+// 为避免泄露邮箱是否已注册，邮箱不存在或账号已停用时同样返回成功，且邮件在后台异步发送，
+// 使两种情况的响应耗时一致。
+func (e *EmailLogic) ForgotPassword(c *gin.Context, email string) error {
+	if err := e.limitAccount(c, constants.RateLimitCode, email); err != nil {
+		return err
+	}
+
+	var user entity.User
+	err := e.inTenant(e.db).Where("email = ?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	token, err := e.newEmailToken(c, emailPurposeReset, &emailToken{
+		UserUUID: user.UUID,
+		Email:    *user.Email,
+		Stamp:    passwordStamp(&user),
+	}, e.sso.Email.ResetTTL)
+	if err != nil {
+		return err
+	}
+	message, err := e.emailMessage(mailer.TemplateResetPassword, &user, e.sso.Email.ResetURL, token, e.sso.Email.ResetTTL)
+	if err != nil {
+		return err
+	}
+
+	send, log := e.mailer.Send, e.log.Named("MAIL")
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailSendTimeout)
+		defer cancel()
+		if err := send(ctx, message); err != nil {
+			log.Error("发送重置密码邮件失败", zap.Stringer("user", user.UUID), zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// ResetPassword 使用邮件中的令牌设置新密码，并撤销用户的全部令牌、结束全部 SSO 会话，使其在所有设备上退出登录。
+//
+// 能够收到邮件说明用户持有该邮箱，因此尚未验证的邮箱会同时被标记为已验证。
+// 令牌只在新密码设置成功后作废，新密码不满足密码策略时用户可以使用同一封邮件重试。
+func (e *EmailLogic) ResetPassword(c *gin.Context, token, password string) error {
+	value, key, err := e.peekEmailToken(c, emailPurposeReset, token)
+	if err != nil {
+		return err
+	}
+	err = e.db.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "uuid = ?", value.UserUUID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errEmailTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if !user.IsActive || user.Email == nil || *user.Email != value.Email || passwordStamp(&user) != value.Stamp {
+			return errEmailTokenInvalid
+		}
+
+		if err := e.setPassword(tx, &user, password, false); err != nil {
+			return err
+		}
+		deleted, err := e.rdb.Del(c, key).Result()
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return errEmailTokenInvalid
+		}
+		if user.EmailVerifiedAt == nil {
+			if err := tx.Model(&user).Update("email_verified_at", time.Now()).Error; err != nil {
+				return err
+			}
+		}
+		if err := revokeUserTokens(tx, user.UUID); err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditPasswordReset, constants.ResourceUser, &user.UUID, map[string]any{
+			"email": value.Email,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if err := e.destroyUserSessions(c, value.UserUUID); err != nil {
+		return err
+	}
+	return e.invalidateAuth(c, value.UserUUID)
+}
+
+// emailMessage 渲染一封附带令牌链接的邮件。
+func (e *EmailLogic) emailMessage(template string, user *entity.User, pageURL, token string, ttl time.Duration) (*mailer.Message, error) {
+	link, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return mailer.Render(template, *user.Email, map[string]any{
+		"Username":  user.Username,
+		"Link":      link.String(),
+		"ExpiresIn": formatTTL(ttl),
+	})
+}
+
+// newEmailToken 签发一枚邮件令牌，令牌格式为 "<随机值>.<签名>"，上下文保存在 Redis 中。
+func (b *base) newEmailToken(c *gin.Context, purpose string, value *emailToken, ttl time.Duration) (string, error) {
+	content, err := jsoniter.MarshalToString(value)
+	if err != nil {
+		return "", err
+	}
+	nonce := utility.RandomToken(24)
+	if err := b.rdb.Set(c, fmt.Sprintf(constants.RedisEmailToken, purpose, nonce), content, ttl).Err(); err != nil {
+		return "", err
+	}
+	return nonce + "." + b.signEmailToken(purpose, nonce), nil
+}
+
+// consumeEmailToken 校验邮件令牌的签名，取出并作废其上下文；每枚令牌只能使用一次。
+//
+// 签名错误的令牌直接拒绝，不会访问 Redis。
+func (b *base) consumeEmailToken(c *gin.Context, purpose, token string) (*emailToken, error) {
+	key, err := b.emailTokenKey(purpose, token)
+	if err != nil {
+		return nil, err
+	}
+	return loadEmailToken(b.rdb.GetDel(c, key))
+}
+
+// peekEmailToken 校验邮件令牌的签名并读取其上下文，但不作废令牌，同时返回令牌在 Redis 中的键。
+//
+// 用于令牌使用成功与否取决于后续校验的场景，调用方在操作成功后删除该键，校验失败时令牌仍可再次使用。
+func (b *base) peekEmailToken(c *gin.Context, purpose, token string) (*emailToken, string, error) {
+	key, err := b.emailTokenKey(purpose, token)
+	if err != nil {
+		return nil, "", err
+	}
+	value, err := loadEmailToken(b.rdb.Get(c, key))
+	if err != nil {
+		return nil, "", err
+	}
+	return value, key, nil
+}
+
+// emailTokenKey 校验邮件令牌的签名，返回其上下文在 Redis 中的键。
+func (b *base) emailTokenKey(purpose, token string) (string, error) {
+	nonce, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(b.signEmailToken(purpose, nonce))) {
+		return "", errEmailTokenInvalid
+	}
+	return fmt.Sprintf(constants.RedisEmailToken, purpose, nonce), nil
+}
+
+// loadEmailToken 解析从 Redis 中读取的邮件令牌上下文，令牌不存在或已过期时返回 errEmailTokenInvalid。
+func loadEmailToken(cmd *redis.StringCmd) (*emailToken, error) {
+	content, err := cmd.Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, errEmailTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	var value emailToken
+	if err := jsoniter.UnmarshalFromString(content, &value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// signEmailToken 计算邮件令牌的签名。
+func (b *base) signEmailToken(purpose, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(b.sso.Email.SigningKey))
+	mac.Write([]byte(purpose + ":" + nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// passwordStamp 返回用户当前密码哈希的摘要，用户未设置密码时返回空字符串。
+func passwordStamp(user *entity.User) string {
+	if !user.HasPassword() {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(*user.PasswordHash))
+	return hex.EncodeToString(sum[:16])
+}
+
+// formatTTL 将有效期格式化为邮件中显示的文字，如 "24 小时"、"30 分钟"。
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		return fmt.Sprintf("%d 小时", ttl/time.Hour)
+	}
+	return fmt.Sprintf("%d 分钟", ttl/time.Minute)
+}
@@ -0,0 +1,345 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/thirdparty/oidc"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ExternalLogic 负责通用 OIDC/OAuth2 上游身份提供商的登录。
+//
+// 管理员只需新增一条 Type 为 oidc 或 oauth2 的 ThirdPartyProvider，即可接入 Google、GitLab、
+// Keycloak 或企业自建的身份提供商，登录后的身份统一保存在 UserExternalIdentity 中。
+type ExternalLogic struct {
+	base
+}
+
+// NewExternal 创建一个新的 ExternalLogic 实例。
+func NewExternal(c *gin.Context) *ExternalLogic {
+	return &ExternalLogic{base: newBase(c)}
+}
+
+// claimMapping 表示从上游声明中提取用户字段时使用的声明名称。
+//
+// 通用 OIDC 提供商使用标准声明，通用 OAuth2 提供商（如 Github 的 id、avatar_url）
+// 可通过 ThirdPartyProvider.ClaimMapping 覆盖其中的部分字段。
+type claimMapping struct {
+	Subject string `json:"subject"`
+	Email   string `json:"email"`
+	Name    string `json:"name"`
+	Picture string `json:"picture"`
+}
+
+// AuthorizeURL 生成跳转到上游身份提供商的授权地址。
+//
+// 参数 linkUser 不为空时，本次授权用于为该用户绑定外部身份而非登录。
+func (e *ExternalLogic) AuthorizeURL(c *gin.Context, providerCode string, linkUser *uuid.UUID) (*dto.AuthorizeURL, error) {
+	provider, err := e.genericProvider(providerCode)
+	if err != nil {
+		return nil, err
+	}
+	client, err := e.client(c, provider)
+	if err != nil {
+		return nil, e.thirdPartyFailed(c, provider, err)
+	}
+
+	stateValue := &oauthState{Provider: provider.Code, CodeVerifier: utility.RandomToken(32), LinkUser: linkUser}
+	if provider.Type == constants.ProviderTypeOIDC {
+		stateValue.Nonce = utility.RandomToken(16)
+	}
+	state, err := e.newOAuthState(c, stateValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AuthorizeURL{
+		URL:   client.AuthCodeURL(state, stateValue.Nonce, stateValue.CodeVerifier),
+		State: state,
+	}, nil
+}
+
+// Callback 处理上游身份提供商的授权回调。
+//
+// 由登录发起的授权会完成绑定或注册后签发令牌；由已登录用户发起的授权只为其绑定外部身份。
+func (e *ExternalLogic) Callback(c *gin.Context, providerCode, code, state string) (*dto.OAuthCallback, error) {
+	stateValue, err := e.consumeOAuthState(c, state)
+	if err != nil {
+		return nil, err
+	}
+	if stateValue.Provider != providerCode {
+		return nil, result.ErrParameter.WithMessage("授权状态与登录方式不匹配")
+	}
+	provider, err := e.genericProvider(providerCode)
+	if err != nil {
+		return nil, err
+	}
+	client, err := e.client(c, provider)
+	if err != nil {
+		return nil, e.thirdPartyFailed(c, provider, err)
+	}
+
+	token, err := client.Exchange(c, code, stateValue.CodeVerifier)
+	if err != nil {
+		return nil, e.thirdPartyFailed(c, provider, err)
+	}
+	claims, err := e.claims(c, provider, client, token, stateValue.Nonce)
+	if err != nil {
+		return nil, e.thirdPartyFailed(c, provider, err)
+	}
+
+	mapping := e.mapping(provider)
+	subject := claimString(claims, mapping.Subject)
+	if subject == "" {
+		return nil, e.thirdPartyFailed(c, provider, fmt.Errorf("上游声明中缺少 %s", mapping.Subject))
+	}
+	rawClaims, err := jsoniter.MarshalToString(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &entity.UserExternalIdentity{
+		ProviderUUID: provider.UUID,
+		Subject:      subject,
+		Email:        utility.NilIfBlank(claimString(claims, mapping.Email)),
+		RawClaims:    &rawClaims,
+		AccessToken:  utility.NilIfBlank(token.AccessToken),
+		RefreshToken: utility.NilIfBlank(token.RefreshToken),
+		IDToken:      utility.NilIfBlank(token.IDToken),
+	}
+	if token.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+		identity.TokenExpiresAt = &expiresAt
+	}
+	profile := &entity.UserProfile{
+		Nickname: utility.NilIfBlank(claimString(claims, mapping.Name)),
+		Avatar:   utility.NilIfBlank(claimString(claims, mapping.Picture)),
+	}
+
+	if stateValue.LinkUser != nil {
+		binding, err := e.link(c, provider, *stateValue.LinkUser, identity)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.OAuthCallback{Action: constants.OAuthActionLink, Binding: binding}, nil
+	}
+	login, err := e.thirdPartyLogin(c, provider, func(tx *gorm.DB) (*entity.User, error) {
+		return e.bind(tx, provider, identity, profile)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.OAuthCallback{Action: constants.OAuthActionLogin, Login: login}, nil
+}
+
+// claims 汇总上游返回的用户声明。
+//
+// OIDC 提供商会先校验 ID Token，再用 userinfo 端点补充声明，两者的 sub 必须一致；
+// OAuth2 提供商只使用 userinfo 端点返回的内容。
+func (e *ExternalLogic) claims(c *gin.Context, provider *entity.ThirdPartyProvider, client *oidc.Client, token *oidc.Token, nonce string) (map[string]any, error) {
+	claims := map[string]any{}
+	if provider.Type == constants.ProviderTypeOIDC {
+		idClaims, err := client.VerifyIDToken(c, token.IDToken, nonce)
+		if err != nil {
+			return nil, err
+		}
+		claims = idClaims
+	}
+
+	userInfo, err := client.UserInfo(c, token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	if sub, ok := claims["sub"]; ok && userInfo["sub"] != nil && fmt.Sprint(userInfo["sub"]) != fmt.Sprint(sub) {
+		return nil, errors.New("userinfo 与 ID Token 的 sub 不一致")
+	}
+	for key, value := range userInfo {
+		claims[key] = value
+	}
+	return claims, nil
+}
+
+// bind 查找或创建外部身份对应的用户。
+//
+// 已绑定的身份会刷新其声明与令牌；未绑定的身份总是创建新用户，不会按邮箱自动关联已有账号，
+// 以免上游未验证的邮箱被用于接管本地账号，关联已有账号需由用户登录后主动操作。
+func (e *ExternalLogic) bind(tx *gorm.DB, provider *entity.ThirdPartyProvider, identity *entity.UserExternalIdentity, profile *entity.UserProfile) (*entity.User, error) {
+	now := time.Now()
+	identity.LastLoginAt = &now
+
+	var exist entity.UserExternalIdentity
+	err := tx.Where(&entity.UserExternalIdentity{ProviderUUID: identity.ProviderUUID, Subject: identity.Subject}).First(&exist).Error
+	switch {
+	case err == nil:
+		user, err := findUser(tx, exist.UserUUID)
+		if err != nil {
+			return nil, err
+		}
+		if !exist.IsActive {
+			return user, errBindingInactive
+		}
+		identity.UUID = exist.UUID
+		identity.UserUUID = exist.UserUUID
+		return user, tx.Model(&exist).Updates(identity).Error
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	user, err := createThirdPartyUser(tx, provider.TenantUUID, provider.Code+"_", profile)
+	if err != nil {
+		return nil, err
+	}
+	e.log.Named("OAUTH").Info("通过外部身份提供商创建新用户", zap.String("provider", provider.Code), zap.String("user", user.UUID.String()))
+
+	identity.UserUUID = user.UUID
+	return user, tx.Create(identity).Error
+}
+
+// link 为指定用户绑定外部身份并写入审计日志。
+//
+// 该身份已绑定到其他用户，或该用户在同一提供商下已绑定了其他身份时拒绝绑定。
+func (e *ExternalLogic) link(c *gin.Context, provider *entity.ThirdPartyProvider, userUUID uuid.UUID, identity *entity.UserExternalIdentity) (*dto.Binding, error) {
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		var exist entity.UserExternalIdentity
+		err := tx.Where(&entity.UserExternalIdentity{ProviderUUID: identity.ProviderUUID, Subject: identity.Subject}).First(&exist).Error
+		if err == nil {
+			if exist.UserUUID != userUUID {
+				return errBindingOccupied
+			}
+			identity.UUID = exist.UUID
+			identity.UserUUID = userUUID
+			identity.FirstBindAt = exist.FirstBindAt
+			return tx.Model(&exist).Updates(identity).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var bound int64
+		if err := tx.Model(&entity.UserExternalIdentity{}).
+			Where(&entity.UserExternalIdentity{UserUUID: userUUID, ProviderUUID: identity.ProviderUUID}).
+			Count(&bound).Error; err != nil {
+			return err
+		}
+		if bound > 0 {
+			return errProviderBound
+		}
+
+		identity.UserUUID = userUUID
+		if err := tx.Create(identity).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditBindingLink, constants.ResourceExternalIdentity, &identity.UUID, map[string]any{
+			"user_uuid": userUUID,
+			"provider":  provider.Code,
+			"subject":   identity.Subject,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return externalBinding(provider, identity), nil
+}
+
+// genericProvider 获取已启用且使用通用驱动的提供商。
+func (e *ExternalLogic) genericProvider(code string) (*entity.ThirdPartyProvider, error) {
+	provider, err := e.enabledProvider(code)
+	if err != nil {
+		return nil, err
+	}
+	if !isGenericProvider(provider.Type) {
+		return nil, result.ErrNotFound.WithMessage("第三方登录方式不存在或未启用")
+	}
+	return provider, nil
+}
+
+// client 根据提供商配置构造上游客户端。
+//
+// OIDC 提供商通过 Issuer 进行服务发现，管理员在提供商中填写的端点地址优先于发现结果。
+func (e *ExternalLogic) client(c *gin.Context, provider *entity.ThirdPartyProvider) (*oidc.Client, error) {
+	endpoints := oidc.Endpoints{
+		AuthURL:     provider.AuthURL,
+		TokenURL:    provider.TokenURL,
+		UserInfoURL: provider.UserInfoURL,
+	}
+	scope := provider.Scope
+
+	if provider.Type == constants.ProviderTypeOIDC {
+		if provider.Issuer == nil || *provider.Issuer == "" {
+			return nil, errors.New("OIDC 提供商未配置 Issuer")
+		}
+		discovered, err := oidc.Discover(c, *provider.Issuer)
+		if err != nil {
+			return nil, err
+		}
+		endpoints.Issuer = discovered.Issuer
+		endpoints.JWKSURL = discovered.JWKSURL
+		if endpoints.AuthURL == "" {
+			endpoints.AuthURL = discovered.AuthURL
+		}
+		if endpoints.TokenURL == "" {
+			endpoints.TokenURL = discovered.TokenURL
+		}
+		if endpoints.UserInfoURL == "" {
+			endpoints.UserInfoURL = discovered.UserInfoURL
+		}
+		if scope == "" {
+			scope = "openid profile email"
+		}
+	}
+	if endpoints.AuthURL == "" || endpoints.TokenURL == "" {
+		return nil, errors.New("提供商未配置授权或令牌端点")
+	}
+
+	return oidc.New(provider.ClientID, provider.ClientSecret, provider.RedirectURL, scope, endpoints), nil
+}
+
+// mapping 读取提供商的声明映射，未配置的字段使用 OIDC 标准声明名称。
+func (e *ExternalLogic) mapping(provider *entity.ThirdPartyProvider) claimMapping {
+	mapping := claimMapping{Subject: "sub", Email: "email", Name: "name", Picture: "picture"}
+	if provider.ClaimMapping == nil {
+		return mapping
+	}
+
+	var custom claimMapping
+	if err := jsoniter.UnmarshalFromString(*provider.ClaimMapping, &custom); err != nil {
+		e.log.Named("OAUTH").Warn("提供商声明映射格式错误，使用默认映射", zap.String("provider", provider.Code), zap.Error(err))
+		return mapping
+	}
+	if custom.Subject != "" {
+		mapping.Subject = custom.Subject
+	}
+	if custom.Email != "" {
+		mapping.Email = custom.Email
+	}
+	if custom.Name != "" {
+		mapping.Name = custom.Name
+	}
+	if custom.Picture != "" {
+		mapping.Picture = custom.Picture
+	}
+	return mapping
+}
+
+// claimString 以字符串形式读取声明值，数字类型的声明（如 Github 的 id）会被转换为十进制字符串。
+func claimString(claims map[string]any, name string) string {
+	value, ok := claims[name]
+	if !ok || value == nil {
+		return ""
+	}
+	if str, ok := value.(string); ok {
+		return str
+	}
+	return fmt.Sprint(value)
+}
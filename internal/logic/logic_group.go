@@ -0,0 +1,564 @@
+package logic
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// groupNamePattern 限定分组名称的字符集，名称会作为 groups 声明返回给接入应用。
+var groupNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// GroupLogic 负责用户分组的管理：分组的层级、成员与分组角色。
+//
+// 用户属于直接加入的分组及其全部上级分组，持有这些分组被分配的角色；
+// 分组的变更会清除受影响用户的认证缓存，使角色与权限立即生效。
+type GroupLogic struct {
+	base
+}
+
+// NewGroup 创建一个新的 GroupLogic 实例。
+func NewGroup(c *gin.Context) *GroupLogic {
+	return &GroupLogic{base: newBase(c)}
+}
+
+// List 列出当前租户的全部分组及其角色与成员数量，按名称排序。
+func (g *GroupLogic) List() ([]*dto.Group, error) {
+	var groups []*entity.Group
+	if err := g.inTenant(g.db).Order("name").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groupDTOs(g.db, groups)
+}
+
+// Get 获取指定的分组及其角色与成员数量。
+func (g *GroupLogic) Get(groupUUID uuid.UUID) (*dto.Group, error) {
+	group, err := findGroup(g.inTenant(g.db), groupUUID)
+	if err != nil {
+		return nil, err
+	}
+	return groupDTO(g.db, group)
+}
+
+// Create 创建一个分组并写入审计日志。
+//
+// 指定上级分组时，上级分组及其全部上级分组的角色均须是操作者可以分配的角色。
+func (g *GroupLogic) Create(c *gin.Context, req *request.GroupCreate) (*dto.Group, error) {
+	if !groupNamePattern.MatchString(req.Name) {
+		return nil, result.ErrParameter.WithMessage("分组名称只能包含小写字母、数字、下划线与短横线，且必须以字母或数字开头")
+	}
+
+	group := &entity.Group{TenantUUID: g.tenant.UUID, ParentUUID: req.ParentUUID, Name: req.Name, DisplayName: req.DisplayName}
+	if req.Description != nil {
+		group.Description = utility.NilIfBlank(*req.Description)
+	}
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		if group.ParentUUID != nil {
+			parent, err := findGroup(g.inTenant(tx), *group.ParentUUID)
+			if err != nil {
+				return err
+			}
+			if err := checkGroupAssignable(c, tx, parent); err != nil {
+				return err
+			}
+		}
+		var count int64
+		if err := tx.Model(&entity.Group{}).Where(&entity.Group{TenantUUID: group.TenantUUID, Name: group.Name}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return result.ErrConflict.WithMessage("分组名称已存在")
+		}
+		if err := tx.Create(group).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditGroupCreate, constants.ResourceGroup, &group.UUID, map[string]any{
+			"name":        group.Name,
+			"parent_uuid": group.ParentUUID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groupDTO(g.db, group)
+}
+
+// Update 修改分组的显示名称、描述与上级分组，只更新请求中传入的字段，并在审计日志中记录被修改的字段名。
+//
+// 分组不能移动到自身或自身的下级分组之下；上级分组变化后，分组及其下级分组成员的有效角色随之变化，
+// 因此新的上级分组及其全部上级分组的角色均须是操作者可以分配的角色。
+func (g *GroupLogic) Update(c *gin.Context, groupUUID uuid.UUID, req *request.GroupUpdate) (*dto.Group, error) {
+	if req.ParentUUID != nil && req.MoveToRoot {
+		return nil, result.ErrParameter.WithMessage("不能同时指定上级分组与移动为顶级分组")
+	}
+
+	var group *entity.Group
+	var members []uuid.UUID
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		group, err = findGroup(g.inTenant(tx.Clauses(clause.Locking{Strength: "UPDATE"})), groupUUID)
+		if err != nil {
+			return err
+		}
+
+		changed := make([]string, 0)
+		if req.DisplayName != nil && group.DisplayName != *req.DisplayName {
+			group.DisplayName = *req.DisplayName
+			changed = append(changed, "display_name")
+		}
+		if req.Description != nil {
+			description := utility.NilIfBlank(*req.Description)
+			if (description == nil) != (group.Description == nil) || (description != nil && *description != *group.Description) {
+				group.Description = description
+				changed = append(changed, "description")
+			}
+		}
+
+		parent := group.ParentUUID
+		switch {
+		case req.MoveToRoot:
+			parent = nil
+		case req.ParentUUID != nil:
+			parent = req.ParentUUID
+		}
+		if (parent == nil) != (group.ParentUUID == nil) || (parent != nil && *parent != *group.ParentUUID) {
+			if parent != nil {
+				parentGroup, err := findGroup(g.inTenant(tx), *parent)
+				if err != nil {
+					return err
+				}
+				if err := checkGroupAssignable(c, tx, parentGroup); err != nil {
+					return err
+				}
+				descendants, err := groupDescendants(tx, []uuid.UUID{group.UUID})
+				if err != nil {
+					return err
+				}
+				if slices.Contains(descendants, *parent) {
+					return result.ErrParameter.WithMessage("不能将分组移动到自身或其下级分组之下")
+				}
+			}
+			group.ParentUUID = parent
+			changed = append(changed, "parent_uuid")
+			if members, err = groupMembers(tx, []uuid.UUID{group.UUID}); err != nil {
+				return err
+			}
+		}
+
+		if len(changed) == 0 {
+			return nil
+		}
+		if err := tx.Save(group).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditGroupUpdate, constants.ResourceGroup, &group.UUID, map[string]any{
+			"name":   group.Name,
+			"fields": changed,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := g.invalidateAuths(c, members); err != nil {
+		return nil, err
+	}
+	return groupDTO(g.db, group)
+}
+
+// Delete 删除一个没有下级分组的分组，分组的成员、角色以及引用该分组的访问规则一并删除，并清除原成员的认证缓存。
+//
+// 删除分组会使成员失去分组及其上级分组的角色，因此这些角色均须是操作者可以分配的角色。
+func (g *GroupLogic) Delete(c *gin.Context, groupUUID uuid.UUID) error {
+	var members []uuid.UUID
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		group, err := findGroup(g.inTenant(tx.Clauses(clause.Locking{Strength: "UPDATE"})), groupUUID)
+		if err != nil {
+			return err
+		}
+		if err := checkGroupAssignable(c, tx, group); err != nil {
+			return err
+		}
+		var children int64
+		if err := tx.Model(&entity.Group{}).Where("parent_uuid = ?", group.UUID).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return result.ErrConflict.WithMessage("分组下仍有下级分组，请先删除或移动下级分组")
+		}
+		if members, err = groupMembers(tx, []uuid.UUID{group.UUID}); err != nil {
+			return err
+		}
+
+		err = tx.Where(&entity.ApplicationAccess{SubjectType: constants.AccessSubjectGroup, SubjectUUID: group.UUID}).
+			Delete(&entity.ApplicationAccess{}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(group).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditGroupDelete, constants.ResourceGroup, &group.UUID, map[string]any{
+			"name":    group.Name,
+			"members": len(members),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return g.invalidateAuths(c, members)
+}
+
+// Members 列出直接加入指定分组的用户，按加入时间倒序排列；下级分组的成员不在其中。
+func (g *GroupLogic) Members(groupUUID uuid.UUID) ([]*dto.GroupMember, error) {
+	if _, err := findGroup(g.inTenant(g.db), groupUUID); err != nil {
+		return nil, err
+	}
+	var groupMembers []entity.GroupMember
+	err := g.db.Preload("User").Where(&entity.GroupMember{GroupUUID: groupUUID}).Order("created_at DESC").Find(&groupMembers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*dto.GroupMember, 0, len(groupMembers))
+	for _, member := range groupMembers {
+		if member.User == nil {
+			continue
+		}
+		members = append(members, &dto.GroupMember{
+			UserUUID:  member.UserUUID,
+			Username:  member.User.Username,
+			AddedBy:   member.AddedBy,
+			CreatedAt: member.CreatedAt,
+		})
+	}
+	return members, nil
+}
+
+// AddMember 将用户加入分组，用户随即持有分组及其上级分组的角色。
+func (g *GroupLogic) AddMember(c *gin.Context, groupUUID uuid.UUID, req *request.GroupMemberAdd) error {
+	operator := currentUser(c)
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		group, err := findGroup(g.inTenant(tx), groupUUID)
+		if err != nil {
+			return err
+		}
+		if err := checkGroupAssignable(c, tx, group); err != nil {
+			return err
+		}
+		_, err = findUser(g.inTenant(tx), req.UserUUID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return result.ErrNotFound.WithMessage("用户不存在")
+		}
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&entity.GroupMember{}).Where(&entity.GroupMember{GroupUUID: group.UUID, UserUUID: req.UserUUID}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return result.ErrConflict.WithMessage("用户已在该分组中")
+		}
+		if err := tx.Create(&entity.GroupMember{GroupUUID: group.UUID, UserUUID: req.UserUUID, AddedBy: &operator.UUID}).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditGroupMemberAdd, constants.ResourceGroup, &group.UUID, map[string]any{
+			"name":      group.Name,
+			"user_uuid": req.UserUUID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return g.invalidateAuth(c, req.UserUUID)
+}
+
+// RemoveMember 将用户移出分组。
+func (g *GroupLogic) RemoveMember(c *gin.Context, groupUUID, userUUID uuid.UUID) error {
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		group, err := findGroup(g.inTenant(tx), groupUUID)
+		if err != nil {
+			return err
+		}
+		if err := checkGroupAssignable(c, tx, group); err != nil {
+			return err
+		}
+		removed := tx.Where(&entity.GroupMember{GroupUUID: group.UUID, UserUUID: userUUID}).Delete(&entity.GroupMember{})
+		if removed.Error != nil {
+			return removed.Error
+		}
+		if removed.RowsAffected == 0 {
+			return result.ErrNotFound.WithMessage("用户不在该分组中")
+		}
+		return writeAudit(c, tx, constants.AuditGroupMemberRemove, constants.ResourceGroup, &group.UUID, map[string]any{
+			"name":      group.Name,
+			"user_uuid": userUUID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return g.invalidateAuth(c, userUUID)
+}
+
+// AssignRole 为分组分配角色，分组及其下级分组的全部成员随即持有该角色。
+//
+// 与直接分配相同，只有超级管理员可以分配超级管理员角色，其他角色的权限不能超出操作者持有的权限。
+func (g *GroupLogic) AssignRole(c *gin.Context, groupUUID uuid.UUID, req *request.GroupRoleAssign) error {
+	operator := currentUser(c)
+	var members []uuid.UUID
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		group, err := findGroup(g.inTenant(tx), groupUUID)
+		if err != nil {
+			return err
+		}
+		role, err := findRole(g.inTenantRoles(tx), req.RoleUUID)
+		if err != nil {
+			return err
+		}
+		if err := checkAssignable(c, tx, role); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&entity.GroupRole{}).Where(&entity.GroupRole{GroupUUID: group.UUID, RoleUUID: role.UUID}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return result.ErrConflict.WithMessage("分组已持有该角色")
+		}
+		if err := tx.Create(&entity.GroupRole{GroupUUID: group.UUID, RoleUUID: role.UUID, AssignedBy: &operator.UUID}).Error; err != nil {
+			return err
+		}
+		if members, err = groupMembers(tx, []uuid.UUID{group.UUID}); err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditGroupRoleAssign, constants.ResourceGroup, &group.UUID, map[string]any{
+			"name": group.Name,
+			"role": role.Name,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return g.invalidateAuths(c, members)
+}
+
+// UnassignRole 收回分组的角色。
+func (g *GroupLogic) UnassignRole(c *gin.Context, groupUUID, roleUUID uuid.UUID) error {
+	var members []uuid.UUID
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		group, err := findGroup(g.inTenant(tx), groupUUID)
+		if err != nil {
+			return err
+		}
+		role, err := findRole(g.inTenantRoles(tx), roleUUID)
+		if err != nil {
+			return err
+		}
+		if err := checkAssignable(c, tx, role); err != nil {
+			return err
+		}
+
+		removed := tx.Where(&entity.GroupRole{GroupUUID: group.UUID, RoleUUID: role.UUID}).Delete(&entity.GroupRole{})
+		if removed.Error != nil {
+			return removed.Error
+		}
+		if removed.RowsAffected == 0 {
+			return result.ErrNotFound.WithMessage("分组未持有该角色")
+		}
+		if members, err = groupMembers(tx, []uuid.UUID{group.UUID}); err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditGroupRoleUnassign, constants.ResourceGroup, &group.UUID, map[string]any{
+			"name": group.Name,
+			"role": role.Name,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return g.invalidateAuths(c, members)
+}
+
+// findGroup 根据 UUID 查找分组。
+func findGroup(db *gorm.DB, groupUUID uuid.UUID) (*entity.Group, error) {
+	var group entity.Group
+	err := db.First(&group, "uuid = ?", groupUUID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, result.ErrNotFound.WithMessage("分组不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// checkGroupAssignable 校验当前登录用户可以调整分组的成员：加入分组等同于获得分组及其上级分组的全部角色，
+// 这些角色均须是操作者可以分配的角色。
+func checkGroupAssignable(c *gin.Context, tx *gorm.DB, group *entity.Group) error {
+	ancestors, err := groupAncestors(tx, []uuid.UUID{group.UUID})
+	if err != nil {
+		return err
+	}
+	groupUUIDs := make([]uuid.UUID, 0, len(ancestors))
+	for _, ancestor := range ancestors {
+		groupUUIDs = append(groupUUIDs, ancestor.UUID)
+	}
+	var roles []*entity.Role
+	if err := tx.Where("uuid IN (?)", tx.Model(&entity.GroupRole{}).Select("role_uuid").Where("group_uuid IN ?", groupUUIDs)).Find(&roles).Error; err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if err := checkAssignable(c, tx, role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// userGroups 查询用户所属的全部分组，包括直接加入的分组及其全部上级分组。
+func userGroups(tx *gorm.DB, userUUID uuid.UUID) ([]*entity.Group, error) {
+	var groupUUIDs []uuid.UUID
+	if err := tx.Model(&entity.GroupMember{}).Where("user_uuid = ?", userUUID).Pluck("group_uuid", &groupUUIDs).Error; err != nil {
+		return nil, err
+	}
+	return groupAncestors(tx, groupUUIDs)
+}
+
+// groupAncestors 查询指定的分组及其全部上级分组，逐层向上查找，已查到的分组不会重复查询。
+func groupAncestors(tx *gorm.DB, groupUUIDs []uuid.UUID) ([]*entity.Group, error) {
+	seen := make(map[uuid.UUID]bool)
+	groups := make([]*entity.Group, 0)
+	for pending := groupUUIDs; len(pending) > 0; {
+		var batch []*entity.Group
+		if err := tx.Where("uuid IN ?", pending).Find(&batch).Error; err != nil {
+			return nil, err
+		}
+		pending = nil
+		for _, group := range batch {
+			if seen[group.UUID] {
+				continue
+			}
+			seen[group.UUID] = true
+			groups = append(groups, group)
+			if group.ParentUUID != nil && !seen[*group.ParentUUID] {
+				pending = append(pending, *group.ParentUUID)
+			}
+		}
+	}
+	return groups, nil
+}
+
+// groupDescendants 查询指定的分组及其全部下级分组的 UUID，逐层向下查找。
+func groupDescendants(tx *gorm.DB, groupUUIDs []uuid.UUID) ([]uuid.UUID, error) {
+	descendants := uniqueUUIDs(groupUUIDs)
+	for pending := descendants; len(pending) > 0; {
+		var children []uuid.UUID
+		if err := tx.Model(&entity.Group{}).Where("parent_uuid IN ?", pending).Pluck("uuid", &children).Error; err != nil {
+			return nil, err
+		}
+		pending = nil
+		for _, child := range children {
+			if !slices.Contains(descendants, child) {
+				descendants = append(descendants, child)
+				pending = append(pending, child)
+			}
+		}
+	}
+	return descendants, nil
+}
+
+// groupMembers 查询指定分组及其全部下级分组的成员，即持有这些分组角色的全部用户。
+func groupMembers(tx *gorm.DB, groupUUIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(groupUUIDs) == 0 {
+		return nil, nil
+	}
+	descendants, err := groupDescendants(tx, groupUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	var userUUIDs []uuid.UUID
+	err = tx.Model(&entity.GroupMember{}).Where("group_uuid IN ?", descendants).Distinct().Pluck("user_uuid", &userUUIDs).Error
+	return userUUIDs, err
+}
+
+// groupDTO 将分组实体转换为管理后台的展示信息。
+func groupDTO(tx *gorm.DB, group *entity.Group) (*dto.Group, error) {
+	list, err := groupDTOs(tx, []*entity.Group{group})
+	if err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// groupDTOs 批量查询分组的角色与成员数量，并转换为管理后台的展示信息。
+func groupDTOs(tx *gorm.DB, groups []*entity.Group) ([]*dto.Group, error) {
+	groupUUIDs := make([]uuid.UUID, 0, len(groups))
+	for _, group := range groups {
+		groupUUIDs = append(groupUUIDs, group.UUID)
+	}
+
+	roles := make(map[uuid.UUID][]string, len(groups))
+	counts := make(map[uuid.UUID]int64, len(groups))
+	if len(groupUUIDs) > 0 {
+		var groupRoles []entity.GroupRole
+		if err := tx.Preload("Role").Where("group_uuid IN ?", groupUUIDs).Find(&groupRoles).Error; err != nil {
+			return nil, err
+		}
+		for _, groupRole := range groupRoles {
+			if groupRole.Role != nil {
+				roles[groupRole.GroupUUID] = append(roles[groupRole.GroupUUID], groupRole.Role.Name)
+			}
+		}
+
+		var memberCounts []struct {
+			GroupUUID uuid.UUID
+			Count     int64
+		}
+		err := tx.Model(&entity.GroupMember{}).
+			Select("group_uuid, COUNT(*) AS count").
+			Where("group_uuid IN ?", groupUUIDs).
+			Group("group_uuid").
+			Scan(&memberCounts).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, memberCount := range memberCounts {
+			counts[memberCount.GroupUUID] = memberCount.Count
+		}
+	}
+
+	list := make([]*dto.Group, 0, len(groups))
+	for _, group := range groups {
+		names := roles[group.UUID]
+		if names == nil {
+			names = make([]string, 0)
+		}
+		slices.Sort(names)
+		list = append(list, &dto.Group{
+			UUID:        group.UUID,
+			ParentUUID:  group.ParentUUID,
+			Name:        group.Name,
+			DisplayName: group.DisplayName,
+			Description: group.Description,
+			Roles:       names,
+			MemberCount: counts[group.UUID],
+			CreatedAt:   group.CreatedAt,
+			UpdatedAt:   group.UpdatedAt,
+		})
+	}
+	return list, nil
+}
@@ -0,0 +1,687 @@
+package logic
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/password"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/secret"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// importJobListSize 是导入任务列表返回的最近任务数量。
+	importJobListSize = 20
+	// importProgressRows 是后台任务每处理多少行保存一次进度。
+	importProgressRows = 50
+	// importProgressInterval 是后台任务保存进度的最长间隔，也作为任务仍在运行的心跳。
+	importProgressInterval = 5 * time.Second
+	// importStaleAfter 是运行中的任务超过多久没有更新进度即视为已中断，如处理任务的实例已停止。
+	importStaleAfter = 5 * time.Minute
+	// importRoleSeparator 是 CSV 文件中分隔多个角色名称的字符。
+	importRoleSeparator = ";"
+	// csvFormulaPrefixes 是表格软件打开 CSV 文件时会将单元格当作公式执行的首字符。
+	csvFormulaPrefixes = "=+-@\t\r"
+	// csvEscapePrefix 是导出 CSV 文件时加在上述单元格之前的字符，使表格软件将单元格视为文本。
+	csvEscapePrefix = '\''
+)
+
+// utf8BOM 是部分表格软件在导出的 CSV 文件开头写入的字节顺序标记，读取导入文件时忽略。
+var utf8BOM = []byte("\ufeff")
+
+// userImportColumns 是导入与导出的 CSV 文件的列名，顺序即导出时的列顺序。
+var userImportColumns = []string{
+	"username", "email", "email_verified", "phone", "phone_verified", "is_active",
+	"nickname", "gender", "birthday", "country", "province", "city", "bio", "roles", "password_hash",
+}
+
+// importRow 表示导入文件中的一行，读取或校验失败时 err 不为空。
+type importRow struct {
+	row      int
+	username string
+	data     *request.UserImportRow
+	err      error
+}
+
+// importRole 表示导入文件引用的一个全局角色，操作者无权分配时 err 不为空。
+type importRole struct {
+	uuid uuid.UUID
+	err  error
+}
+
+// ImportLogic 负责管理后台批量导入用户：校验导入文件并创建后台任务，以及查看任务的进度与失败的行。
+//
+// 导入文件在请求中完整读取，格式、列名与行数不符合要求时直接拒绝；文件引用的角色同样在请求中解析，
+// 操作者只能分配其有权分配的角色。随后在后台逐行创建用户，每一行独立提交，失败的行连同原因记入任务。
+// 已存在的用户名、邮箱或手机号不会被覆盖。
+type ImportLogic struct {
+	base
+}
+
+// NewImport 创建一个新的 ImportLogic 实例。
+func NewImport(c *gin.Context) *ImportLogic {
+	return &ImportLogic{base: newBase(c)}
+}
+
+// Import 读取并校验上传的导入文件，创建导入任务并在后台开始导入，返回新创建的任务。
+//
+// format 为空时按文件扩展名判断文件格式。
+func (i *ImportLogic) Import(c *gin.Context, header *multipart.FileHeader, format string) (*dto.UserImportJob, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+	if format != constants.ImportFormatCSV && format != constants.ImportFormatJSON {
+		return nil, result.ErrParameter.WithMessage("无法识别导入文件的格式，请上传 CSV 或 JSON 文件")
+	}
+
+	limit := i.sso.Import.MaxBytes
+	tooLarge := result.ErrParameter.WithMessage(fmt.Sprintf("导入文件不能超过 %d KiB", limit>>10))
+	if header.Size > limit {
+		return nil, tooLarge
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, tooLarge
+	}
+
+	var rows []*importRow
+	if format == constants.ImportFormatCSV {
+		rows, err = parseImportCSV(data, i.sso.Import.MaxRows)
+	} else {
+		rows, err = parseImportJSON(data, i.sso.Import.MaxRows)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, result.ErrParameter.WithMessage("导入文件中没有用户")
+	}
+	roles, err := i.importRoles(c, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	operator := currentUser(c).UUID
+	job := &entity.UserImportJob{
+		TenantUUID: i.tenant.UUID,
+		CreatedBy:  &operator,
+		FileName:   utility.Truncate(filepath.Base(header.Filename), 255),
+		Format:     format,
+		Status:     constants.ImportStatusPending,
+		Total:      len(rows),
+	}
+	err = i.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditUserImport, constants.ResourceUserImportJob, &job.UUID, map[string]any{
+			"file_name": job.FileName,
+			"format":    job.Format,
+			"total":     job.Total,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 请求结束后上下文随之取消，后台任务使用独立的数据库会话
+	worker := &importWorker{
+		db:       i.db.WithContext(context.Background()),
+		log:      i.log.Named("IMPORT"),
+		policy:   i.policy,
+		tenant:   i.tenant.UUID,
+		operator: operator,
+		job:      job.UUID,
+		rows:     rows,
+		roles:    roles,
+	}
+	go worker.run()
+	return importJobDTO(job), nil
+}
+
+// List 列出当前租户最近的导入任务，按创建时间倒序排列。
+func (i *ImportLogic) List() ([]*dto.UserImportJob, error) {
+	if err := i.expireStaleJobs(); err != nil {
+		return nil, err
+	}
+	var jobs []*entity.UserImportJob
+	if err := i.db.Where("tenant_uuid = ?", i.tenant.UUID).Order("uuid DESC").Limit(importJobListSize).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	items := make([]*dto.UserImportJob, 0, len(jobs))
+	for _, job := range jobs {
+		items = append(items, importJobDTO(job))
+	}
+	return items, nil
+}
+
+// Get 查看当前租户中导入任务的进度，以及已处理的行中导入失败的行与原因。
+func (i *ImportLogic) Get(jobUUID uuid.UUID) (*dto.UserImportJobDetail, error) {
+	if err := i.expireStaleJobs(); err != nil {
+		return nil, err
+	}
+	var job entity.UserImportJob
+	err := i.db.Where("tenant_uuid = ?", i.tenant.UUID).First(&job, "uuid = ?", jobUUID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, result.ErrNotFound.WithMessage("导入任务不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []*entity.UserImportError
+	if err := i.db.Where("job_uuid = ?", job.UUID).Order("row_number").Find(&failures).Error; err != nil {
+		return nil, err
+	}
+	detail := &dto.UserImportJobDetail{Job: importJobDTO(&job), Errors: make([]*dto.UserImportError, 0, len(failures))}
+	for _, failure := range failures {
+		detail.Errors = append(detail.Errors, &dto.UserImportError{Row: failure.RowNumber, Username: failure.Username, Message: failure.Message})
+	}
+	return detail, nil
+}
+
+// importRoles 解析导入文件引用的全局角色，并校验当前登录用户可以分配这些角色。
+//
+// 不存在的角色不在结果中；操作者无权分配的角色记录原因，引用它们的行导入失败。
+func (i *ImportLogic) importRoles(c *gin.Context, rows []*importRow) (map[string]*importRole, error) {
+	var names []string
+	for _, row := range rows {
+		if row.err == nil {
+			names = append(names, row.data.Roles...)
+		}
+	}
+	roles := make(map[string]*importRole)
+	if len(names) == 0 {
+		return roles, nil
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	var found []*entity.Role
+	if err := i.inTenantRoles(i.db).Where("application_uuid IS NULL AND name IN ?", names).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	for _, role := range found {
+		err := checkAssignable(c, i.db, role)
+		var resultErr *result.Error
+		if err != nil && !errors.As(err, &resultErr) {
+			return nil, err
+		}
+		roles[role.Name] = &importRole{uuid: role.UUID, err: err}
+	}
+	return roles, nil
+}
+
+// expireStaleJobs 将当前租户中长时间没有更新进度的任务标记为已中断，如处理任务的实例在导入期间停止。
+func (i *ImportLogic) expireStaleJobs() error {
+	return i.db.Model(&entity.UserImportJob{}).
+		Where("tenant_uuid = ? AND status IN ?", i.tenant.UUID, []string{constants.ImportStatusPending, constants.ImportStatusRunning}).
+		Where("updated_at < ?", time.Now().Add(-importStaleAfter)).
+		Updates(map[string]any{
+			"status":      constants.ImportStatusFailed,
+			"message":     "导入任务已中断，已处理的行保持不变",
+			"finished_at": time.Now(),
+		}).Error
+}
+
+// importWorker 在后台逐行导入用户，只持有与请求无关的依赖。
+type importWorker struct {
+	db       *gorm.DB
+	log      *zap.Logger
+	policy   *password.Policy
+	tenant   uuid.UUID
+	operator uuid.UUID
+	job      uuid.UUID
+	rows     []*importRow
+	roles    map[string]*importRole
+}
+
+// run 逐行导入用户并定期保存进度，结束时记录任务结果。
+func (w *importWorker) run() {
+	var processed, succeeded, failed int
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			w.log.Error("导入任务异常中断", zap.Stringer("job", w.job), zap.Any("panic", recovered))
+			w.finish(processed, succeeded, failed, constants.ImportStatusFailed, "导入任务异常中断")
+		}
+	}()
+
+	now := time.Now()
+	err := w.db.Model(&entity.UserImportJob{UUID: w.job}).Updates(map[string]any{
+		"status":     constants.ImportStatusRunning,
+		"started_at": now,
+	}).Error
+	if err != nil {
+		w.log.Error("开始导入任务失败", zap.Stringer("job", w.job), zap.Error(err))
+		w.finish(0, 0, 0, constants.ImportStatusFailed, "开始导入任务失败")
+		return
+	}
+
+	saved := now
+	for _, row := range w.rows {
+		err := row.err
+		if err == nil {
+			err = w.db.Transaction(func(tx *gorm.DB) error {
+				return w.importUser(tx, row.data)
+			})
+		}
+		processed++
+		if err == nil {
+			succeeded++
+		} else {
+			failed++
+			w.recordError(row, err)
+		}
+		if processed%importProgressRows == 0 || time.Since(saved) >= importProgressInterval {
+			w.progress(processed, succeeded, failed)
+			saved = time.Now()
+		}
+	}
+	w.finish(processed, succeeded, failed, constants.ImportStatusCompleted, "")
+	w.log.Info("导入任务完成", zap.Stringer("job", w.job), zap.Int("succeeded", succeeded), zap.Int("failed", failed))
+}
+
+// importUser 创建一行对应的用户及其资料与角色，应在事务中调用；返回的业务错误为导入失败的原因。
+func (w *importWorker) importUser(tx *gorm.DB, data *request.UserImportRow) error {
+	username := strings.TrimSpace(data.Username)
+	email := trimmed(data.Email)
+	phone := trimmed(data.Phone)
+	passwordHash := trimmed(data.PasswordHash)
+
+	var birthday *time.Time
+	if value := trimmed(data.Birthday); value != nil {
+		parsed, err := time.Parse(time.DateOnly, *value)
+		if err != nil {
+			return result.ErrParameter.WithMessage("生日的格式应为 YYYY-MM-DD")
+		}
+		if parsed.Before(earliestBirthday) || parsed.After(time.Now()) {
+			return result.ErrParameter.WithMessage("生日不在有效范围内")
+		}
+		birthday = &parsed
+	}
+	if passwordHash != nil && !w.policy.Recognize(*passwordHash) {
+		return result.ErrParameter.WithMessage("不支持的密码哈希格式")
+	}
+	roleUUIDs := make([]uuid.UUID, 0, len(data.Roles))
+	for _, name := range data.Roles {
+		role, ok := w.roles[name]
+		if !ok {
+			return result.ErrNotFound.WithMessage("角色不存在：" + name)
+		}
+		if role.err != nil {
+			return role.err
+		}
+		if !slices.Contains(roleUUIDs, role.uuid) {
+			roleUUIDs = append(roleUUIDs, role.uuid)
+		}
+	}
+
+	users := tx.Model(&entity.User{}).Where("tenant_uuid = ?", w.tenant).Session(&gorm.Session{})
+	var count int64
+	if err := users.Where("username = ?", username).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return result.ErrConflict.WithMessage("用户名已存在")
+	}
+	if email != nil {
+		if err := users.Where("email = ?", *email).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return result.ErrConflict.WithMessage("邮箱已被其他账号使用")
+		}
+	}
+	var phoneHash *string
+	if phone != nil {
+		index, err := secret.BlindIndex(*phone)
+		if err != nil {
+			return err
+		}
+		if err := users.Where("phone_hash = ?", index).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errPhoneTaken
+		}
+		phoneHash = &index
+	}
+
+	now := time.Now()
+	user := &entity.User{
+		TenantUUID:   w.tenant,
+		Username:     username,
+		Email:        email,
+		Phone:        phone,
+		PhoneHash:    phoneHash,
+		PasswordHash: passwordHash,
+		IsActive:     true,
+	}
+	if email != nil && data.EmailVerified {
+		user.EmailVerifiedAt = &now
+	}
+	if phone != nil && data.PhoneVerified {
+		user.PhoneVerifiedAt = &now
+	}
+	if err := tx.Create(user).Error; err != nil {
+		return err
+	}
+	// is_active 的默认值为 true，创建时不会写入 false
+	if data.IsActive != nil && !*data.IsActive {
+		if err := tx.Model(user).Update("is_active", false).Error; err != nil {
+			return err
+		}
+	}
+
+	profile := &entity.UserProfile{
+		UserUUID: user.UUID,
+		Nickname: trimmed(data.Nickname),
+		Gender:   data.Gender,
+		Birthday: birthday,
+		Country:  trimmed(data.Country),
+		Province: trimmed(data.Province),
+		City:     trimmed(data.City),
+		Bio:      trimmed(data.Bio),
+	}
+	if profile.Nickname != nil || profile.Gender != 0 || profile.Birthday != nil || profile.Country != nil ||
+		profile.Province != nil || profile.City != nil || profile.Bio != nil {
+		if err := tx.Create(profile).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, roleUUID := range roleUUIDs {
+		userRole := &entity.UserRole{UserUUID: user.UUID, RoleUUID: roleUUID, AssignedBy: &w.operator, IsActive: true}
+		if err := tx.Create(userRole).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordError 记录导入失败的行；业务错误记录其提示，其余错误另外写入日志。
+func (w *importWorker) recordError(row *importRow, err error) {
+	message := "导入失败，请稍后重试"
+	var resultErr *result.Error
+	if errors.As(err, &resultErr) {
+		message = resultErr.Message
+	} else {
+		w.log.Warn("导入用户失败", zap.Stringer("job", w.job), zap.Int("row", row.row), zap.Error(err))
+	}
+	record := &entity.UserImportError{
+		JobUUID:   w.job,
+		RowNumber: row.row,
+		Username:  utility.NilIfBlank(utility.Truncate(row.username, 255)),
+		Message:   message,
+	}
+	if err := w.db.Create(record).Error; err != nil {
+		w.log.Error("保存导入失败的行失败", zap.Stringer("job", w.job), zap.Int("row", row.row), zap.Error(err))
+	}
+}
+
+// progress 保存任务的进度。
+func (w *importWorker) progress(processed, succeeded, failed int) {
+	err := w.db.Model(&entity.UserImportJob{UUID: w.job}).Updates(map[string]any{
+		"processed": processed,
+		"succeeded": succeeded,
+		"failed":    failed,
+	}).Error
+	if err != nil {
+		w.log.Warn("保存导入进度失败", zap.Stringer("job", w.job), zap.Error(err))
+	}
+}
+
+// finish 保存任务的最终进度与结果，message 为任务中断的原因，任务正常结束时为空字符串。
+func (w *importWorker) finish(processed, succeeded, failed int, status, message string) {
+	err := w.db.Model(&entity.UserImportJob{UUID: w.job}).Updates(map[string]any{
+		"status":      status,
+		"processed":   processed,
+		"succeeded":   succeeded,
+		"failed":      failed,
+		"message":     utility.NilIfBlank(message),
+		"finished_at": time.Now(),
+	}).Error
+	if err != nil {
+		w.log.Error("保存导入任务结果失败", zap.Stringer("job", w.job), zap.Error(err))
+	}
+}
+
+// importRowError 返回导入文件中某一行读取或校验失败的错误，message 为记入任务的失败原因。
+func importRowError(message string) error {
+	return result.ErrParameter.WithMessage(message)
+}
+
+// trimmed 去除可选字段的首尾空白，未填写或为空白时返回 nil。
+func trimmed(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	return utility.NilIfBlank(strings.TrimSpace(*value))
+}
+
+// newImportRow 去除各字段的首尾空白后校验一行的内容，校验失败时记录原因；只含空白的可选字段按未填写处理。
+func newImportRow(row int, data *request.UserImportRow) *importRow {
+	data.Username = strings.TrimSpace(data.Username)
+	for _, field := range []**string{
+		&data.Email, &data.Phone, &data.Nickname, &data.Birthday, &data.Country,
+		&data.Province, &data.City, &data.Bio, &data.PasswordHash,
+	} {
+		*field = trimmed(*field)
+	}
+	for index, name := range data.Roles {
+		data.Roles[index] = strings.TrimSpace(name)
+	}
+	item := &importRow{row: row, username: data.Username, data: data}
+	if err := binding.Validator.ValidateStruct(data); err != nil {
+		item.err = importRowError(err.Error())
+	}
+	return item
+}
+
+// parseImportCSV 读取 CSV 格式的导入文件，首行为列名，列名必须包含 username 且不能有未知的列。
+//
+// 文件本身无法解析或行数超出 maxRows 时返回参数错误；某一行的值无效时只有该行导入失败。
+func parseImportCSV(data []byte, maxRows int) ([]*importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, result.ErrParameter.WithMessage("导入文件中没有用户")
+	}
+	if err != nil {
+		return nil, result.ErrParameter.WithMessage("无法解析 CSV 文件：" + err.Error())
+	}
+	columns := make(map[string]int, len(header))
+	for index, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(userImportColumns, name) {
+			return nil, result.ErrParameter.WithMessage("未知的列：" + name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, result.ErrParameter.WithMessage("重复的列：" + name)
+		}
+		columns[name] = index
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, result.ErrParameter.WithMessage("缺少 username 列")
+	}
+
+	var rows []*importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+			rows = append(rows, &importRow{row: parseErr.StartLine, err: importRowError("列数与列名行不一致")})
+		} else if err != nil {
+			return nil, result.ErrParameter.WithMessage("无法解析 CSV 文件：" + err.Error())
+		} else {
+			line, _ := reader.FieldPos(0)
+			rows = append(rows, csvImportRow(line, columns, record))
+		}
+		if len(rows) > maxRows {
+			return nil, result.ErrParameter.WithMessage(fmt.Sprintf("导入文件不能超过 %d 个用户", maxRows))
+		}
+	}
+	return rows, nil
+}
+
+// csvImportRow 将 CSV 文件的一行转换为导入的用户，值为空的列按未填写处理，导出时为防止公式注入而加的前缀会被去掉。
+func csvImportRow(line int, columns map[string]int, record []string) *importRow {
+	value := func(name string) string {
+		if index, ok := columns[name]; ok {
+			return unescapeCSVCell(strings.TrimSpace(record[index]))
+		}
+		return ""
+	}
+	optional := func(name string) *string {
+		return utility.NilIfBlank(value(name))
+	}
+	data := &request.UserImportRow{
+		Username:     value("username"),
+		Email:        optional("email"),
+		Phone:        optional("phone"),
+		Nickname:     optional("nickname"),
+		Birthday:     optional("birthday"),
+		Country:      optional("country"),
+		Province:     optional("province"),
+		City:         optional("city"),
+		Bio:          optional("bio"),
+		PasswordHash: optional("password_hash"),
+	}
+	invalid := func(message string) *importRow {
+		return &importRow{row: line, username: data.Username, err: importRowError(message)}
+	}
+
+	var err error
+	if raw := value("email_verified"); raw != "" {
+		if data.EmailVerified, err = strconv.ParseBool(raw); err != nil {
+			return invalid("email_verified 应为 true 或 false")
+		}
+	}
+	if raw := value("phone_verified"); raw != "" {
+		if data.PhoneVerified, err = strconv.ParseBool(raw); err != nil {
+			return invalid("phone_verified 应为 true 或 false")
+		}
+	}
+	if raw := value("is_active"); raw != "" {
+		isActive, err := strconv.ParseBool(raw)
+		if err != nil {
+			return invalid("is_active 应为 true 或 false")
+		}
+		data.IsActive = &isActive
+	}
+	if raw := value("gender"); raw != "" {
+		if data.Gender, err = strconv.Atoi(raw); err != nil {
+			return invalid("gender 应为 0、1 或 2")
+		}
+	}
+	if raw := value("roles"); raw != "" {
+		for _, name := range strings.Split(raw, importRoleSeparator) {
+			if name = strings.TrimSpace(name); name != "" {
+				data.Roles = append(data.Roles, name)
+			}
+		}
+	}
+	return newImportRow(line, data)
+}
+
+// escapeCSVCell 在可能被表格软件当作公式执行的单元格前加上单引号，unescapeCSVCell 可以还原。
+//
+// 以单引号开头、去掉单引号后仍需转义的值同样会加上单引号，使转义可以无损还原。
+func escapeCSVCell(value string) string {
+	if csvCellNeedsEscape(value) {
+		return string(csvEscapePrefix) + value
+	}
+	return value
+}
+
+// unescapeCSVCell 去掉 escapeCSVCell 加上的单引号，其余值原样返回。
+func unescapeCSVCell(value string) string {
+	if len(value) > 0 && value[0] == csvEscapePrefix && csvCellNeedsEscape(value[1:]) {
+		return value[1:]
+	}
+	return value
+}
+
+// csvCellNeedsEscape 判断单元格的值导出时是否需要加上单引号。
+func csvCellNeedsEscape(value string) bool {
+	if value == "" {
+		return false
+	}
+	if strings.IndexByte(csvFormulaPrefixes, value[0]) >= 0 {
+		return true
+	}
+	return value[0] == csvEscapePrefix && csvCellNeedsEscape(value[1:])
+}
+
+// parseImportJSON 读取 JSON 格式的导入文件，文件内容为用户对象的数组。
+//
+// 文件不是 JSON 数组或元素数量超出 maxRows 时返回参数错误；某个元素无法解析或值无效时只有该行导入失败。
+func parseImportJSON(data []byte, maxRows int) ([]*importRow, error) {
+	var elements []jsoniter.RawMessage
+	if err := jsoniter.Unmarshal(bytes.TrimPrefix(data, utf8BOM), &elements); err != nil {
+		return nil, result.ErrParameter.WithMessage("导入文件应为用户对象组成的 JSON 数组")
+	}
+	if len(elements) > maxRows {
+		return nil, result.ErrParameter.WithMessage(fmt.Sprintf("导入文件不能超过 %d 个用户", maxRows))
+	}
+	rows := make([]*importRow, 0, len(elements))
+	for index, element := range elements {
+		var item request.UserImportRow
+		if err := jsoniter.Unmarshal(element, &item); err != nil {
+			rows = append(rows, &importRow{row: index + 1, username: jsoniter.Get(element, "username").ToString(), err: importRowError("无法解析该用户：" + err.Error())})
+			continue
+		}
+		rows = append(rows, newImportRow(index+1, &item))
+	}
+	return rows, nil
+}
+
+// importJobDTO 将导入任务实体转换为展示信息。
+func importJobDTO(job *entity.UserImportJob) *dto.UserImportJob {
+	return &dto.UserImportJob{
+		UUID:       job.UUID,
+		FileName:   job.FileName,
+		Format:     job.Format,
+		Status:     job.Status,
+		Total:      job.Total,
+		Processed:  job.Processed,
+		Succeeded:  job.Succeeded,
+		Failed:     job.Failed,
+		Message:    job.Message,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+		CreatedAt:  job.CreatedAt,
+	}
+}
@@ -0,0 +1,162 @@
+package logic
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEscapeCSVCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"alice", "alice"},
+		{"=cmd|' /C calc'!A0", "'=cmd|' /C calc'!A0"},
+		{"+8613800000000", "'+8613800000000"},
+		{"-1", "'-1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tvalue", "'\tvalue"},
+		{"\rvalue", "'\rvalue"},
+		{"'quoted", "'quoted"},
+		{"'=cmd", "''=cmd"},
+		{"''+1", "'''+1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			escaped := escapeCSVCell(tt.value)
+			if escaped != tt.want {
+				t.Errorf("escapeCSVCell(%q) = %q, want %q", tt.value, escaped, tt.want)
+			}
+			// 转义必须可以无损还原
+			if got := unescapeCSVCell(escaped); got != tt.value {
+				t.Errorf("unescapeCSVCell(%q) = %q, want %q", escaped, got, tt.value)
+			}
+		})
+	}
+}
+
+func TestParseImportCSV(t *testing.T) {
+	data := "\xEF\xBB\xBFusername, Email ,phone,roles,is_active,nickname\n" +
+		"alice,alice@example.com,'+8613800000000, admin ; editor ;,true,'=cmd\n" +
+		"  ,,,,,\n" +
+		"bob,bob@example.com\n" +
+		"carol,,,,maybe,\n" +
+		"dave,not-an-email,,,,\n"
+	rows, err := parseImportCSV([]byte(data), 10)
+	if err != nil {
+		t.Fatalf("parseImportCSV: %v", err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("len(rows) = %d, want 5", len(rows))
+	}
+
+	alice := rows[0]
+	if alice.err != nil {
+		t.Fatalf("row alice: %v", alice.err)
+	}
+	if alice.row != 2 || alice.username != "alice" {
+		t.Errorf("row alice = (%d, %q), want (2, %q)", alice.row, alice.username, "alice")
+	}
+	if alice.data.Phone == nil || *alice.data.Phone != "+8613800000000" {
+		t.Errorf("phone = %v, want +8613800000000", alice.data.Phone)
+	}
+	if alice.data.Nickname == nil || *alice.data.Nickname != "=cmd" {
+		t.Errorf("nickname = %v, want =cmd", alice.data.Nickname)
+	}
+	if !slices.Equal(alice.data.Roles, []string{"admin", "editor"}) {
+		t.Errorf("roles = %v, want [admin editor]", alice.data.Roles)
+	}
+	if alice.data.IsActive == nil || !*alice.data.IsActive {
+		t.Errorf("is_active = %v, want true", alice.data.IsActive)
+	}
+
+	for index, name := range []string{"blank username", "field count", "invalid bool", "invalid email"} {
+		if row := rows[index+1]; row.err == nil {
+			t.Errorf("row %d (%s): err = nil, want error", row.row, name)
+		}
+	}
+	if rows[2].row != 4 {
+		t.Errorf("field count row = %d, want 4", rows[2].row)
+	}
+}
+
+func TestParseImportCSVRejectsFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		maxRows int
+	}{
+		{"empty file", "", 10},
+		{"unknown column", "username,salary\nalice,1\n", 10},
+		{"duplicate column", "username,Username\nalice,alice\n", 10},
+		{"missing username column", "email\nalice@example.com\n", 10},
+		{"too many rows", "username\nalice\nbob\ncarol\n", 2},
+		{"unterminated quote", "username\n\"alice\n", 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseImportCSV([]byte(tt.data), tt.maxRows); err == nil {
+				t.Error("parseImportCSV = nil error, want error")
+			}
+		})
+	}
+}
+
+func TestParseImportJSON(t *testing.T) {
+	data := `[
+		{"username": "  alice  ", "email": " alice@example.com ", "nickname": "   ", "roles": [" admin "]},
+		{"username": "   "},
+		{"username": "bob", "gender": "male"},
+		{"username": "carol", "phone": "13800000000"}
+	]`
+	rows, err := parseImportJSON([]byte(data), 10)
+	if err != nil {
+		t.Fatalf("parseImportJSON: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("len(rows) = %d, want 4", len(rows))
+	}
+
+	alice := rows[0]
+	if alice.err != nil {
+		t.Fatalf("row alice: %v", alice.err)
+	}
+	if alice.username != "alice" || *alice.data.Email != "alice@example.com" {
+		t.Errorf("row alice = (%q, %q), want trimmed values", alice.username, *alice.data.Email)
+	}
+	if alice.data.Nickname != nil {
+		t.Errorf("nickname = %q, want nil", *alice.data.Nickname)
+	}
+	if !slices.Equal(alice.data.Roles, []string{"admin"}) {
+		t.Errorf("roles = %v, want [admin]", alice.data.Roles)
+	}
+
+	for _, row := range rows[1:] {
+		if row.err == nil {
+			t.Errorf("row %d: err = nil, want error", row.row)
+		}
+	}
+	if rows[2].username != "bob" {
+		t.Errorf("malformed row username = %q, want bob", rows[2].username)
+	}
+}
+
+func TestParseImportJSONRejectsFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		maxRows int
+	}{
+		{"not an array", `{"username": "alice"}`, 10},
+		{"invalid json", `[{"username": "alice"`, 10},
+		{"too many rows", `[{"username": "alice"}, {"username": "bob"}]`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseImportJSON([]byte(tt.data), tt.maxRows); err == nil {
+				t.Error("parseImportJSON = nil error, want error")
+			}
+		})
+	}
+}
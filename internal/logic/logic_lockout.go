@@ -0,0 +1,239 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// lockoutReasons 是计入账号锁定的登录失败原因，均表示调用方提交了错误的凭据。
+var lockoutReasons = []string{
+	constants.FailurePasswordInvalid,
+	constants.FailureSMSCodeInvalid,
+	constants.FailureTOTPInvalid,
+	constants.FailureRecoveryInvalid,
+	constants.FailurePasskeyInvalid,
+}
+
+// lockoutState 表示一个账号在 Redis 中保存的锁定状态。
+//
+// 字段说明：
+//   - Level: 近期连续被锁定的次数，每次锁定时长为首次锁定时长的 2^(Level-1) 倍。
+//   - Since: 统计失败次数的起点，账号被锁定或解除锁定时更新，使此前的失败不再重复计入。
+//   - LockedUntil: 锁定的截止时间，为空表示当前未被锁定。
+type lockoutState struct {
+	Level       int        `json:"level"`
+	Since       time.Time  `json:"since"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
+
+// locked 检查账号当前是否处于锁定中。
+func (s *lockoutState) locked(now time.Time) bool {
+	return s.LockedUntil != nil && now.Before(*s.LockedUntil)
+}
+
+// LockoutLogic 负责管理后台查看与解除因登录失败次数过多而被锁定的账号。
+//
+// 锁定依据登录日志中的失败记录计算，由 LoginLogic.Failed 在每次登录失败后触发。
+type LockoutLogic struct {
+	base
+}
+
+// NewLockout 创建一个新的 LockoutLogic 实例。
+func NewLockout(c *gin.Context) *LockoutLogic {
+	return &LockoutLogic{base: newBase(c)}
+}
+
+// List 列出当前租户中处于锁定中的账号，按锁定截止时间升序排列。
+func (l *LockoutLogic) List(c *gin.Context) ([]*dto.Lockout, error) {
+	now := time.Now()
+	states := make(map[uuid.UUID]*lockoutState)
+	iter := l.rdb.Scan(c, 0, fmt.Sprintf(constants.RedisLockout, "*"), 100).Iterator()
+	for iter.Next(c) {
+		userUUID, err := uuid.Parse(strings.TrimPrefix(iter.Val(), fmt.Sprintf(constants.RedisLockout, "")))
+		if err != nil {
+			continue
+		}
+		state, err := l.loadLockout(c, userUUID)
+		if err != nil {
+			return nil, err
+		}
+		if state != nil && state.locked(now) {
+			states[userUUID] = state
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	lockouts := make([]*dto.Lockout, 0, len(states))
+	if len(states) == 0 {
+		return lockouts, nil
+	}
+	var users []entity.User
+	if err := l.inTenant(l.db).Where("uuid IN ?", slices.Collect(maps.Keys(states))).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		state := states[user.UUID]
+		lockouts = append(lockouts, &dto.Lockout{
+			UserUUID:    user.UUID,
+			Username:    user.Username,
+			Level:       state.Level,
+			LockedUntil: *state.LockedUntil,
+		})
+	}
+	slices.SortFunc(lockouts, func(a, b *dto.Lockout) int {
+		return a.LockedUntil.Compare(b.LockedUntil)
+	})
+	return lockouts, nil
+}
+
+// Clear 解除账号的锁定。锁定等级一并清零，此前的失败记录不再计入下一次锁定。
+func (l *LockoutLogic) Clear(c *gin.Context, userUUID uuid.UUID) error {
+	_, err := findUser(l.inTenant(l.db), userUUID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return result.ErrNotFound.WithMessage("用户不存在")
+	}
+	if err != nil {
+		return err
+	}
+	state, err := l.loadLockout(c, userUUID)
+	if err != nil {
+		return err
+	}
+	if state == nil || !state.locked(time.Now()) {
+		return result.ErrNotFound.WithMessage("该账号未被锁定")
+	}
+
+	return l.db.Transaction(func(tx *gorm.DB) error {
+		err := writeAudit(c, tx, constants.AuditLockoutClear, constants.ResourceUser, &userUUID, map[string]any{
+			"level":        state.Level,
+			"locked_until": state.LockedUntil,
+		})
+		if err != nil {
+			return err
+		}
+		return l.saveLockout(c, userUUID, &lockoutState{Since: time.Now()}, l.sso.Lockout.Window)
+	})
+}
+
+// checkLockout 检查账号是否处于锁定中，锁定时返回错误并设置 Retry-After 响应头。
+//
+// 应在校验凭据之前调用，锁定期间即使提交了正确的凭据也不能登录。
+func (b *base) checkLockout(c *gin.Context, userUUID uuid.UUID) error {
+	state, err := b.loadLockout(c, userUUID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if state == nil || !state.locked(now) {
+		return nil
+	}
+	c.Header("Retry-After", strconv.Itoa(max(1, int(state.LockedUntil.Sub(now).Seconds()))))
+	return result.ErrTooMany.WithMessage("登录失败次数过多，账号已被临时锁定，请稍后再试").WithData(map[string]any{
+		"locked_until": state.LockedUntil,
+	})
+}
+
+// registerFailure 在账号登录失败后统计近期的失败次数，达到阈值时锁定账号。
+//
+// 失败次数取自登录日志，只统计时间窗口内、上次成功登录与上次锁定之后的失败。
+func (b *base) registerFailure(c *gin.Context, userUUID uuid.UUID, reason string) error {
+	cfg := b.sso.Lockout
+	if cfg.Threshold <= 0 || !slices.Contains(lockoutReasons, reason) {
+		return nil
+	}
+
+	state, err := b.loadLockout(c, userUUID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if state == nil {
+		state = &lockoutState{}
+	}
+	if state.locked(now) {
+		return nil
+	}
+
+	user, err := findUser(b.db, userUUID)
+	if err != nil {
+		return err
+	}
+	since := now.Add(-cfg.Window)
+	if state.Since.After(since) {
+		since = state.Since
+	}
+	if user.LastLoginAt != nil && user.LastLoginAt.After(since) {
+		since = *user.LastLoginAt
+	}
+
+	var failures int64
+	err = b.db.Model(&entity.LoginLog{}).
+		Where("user_uuid = ? AND is_success = ? AND failure_reason IN ? AND login_at > ?", userUUID, false, lockoutReasons, since).
+		Count(&failures).Error
+	if err != nil {
+		return err
+	}
+	if failures < int64(cfg.Threshold) {
+		return nil
+	}
+
+	duration := cfg.Duration
+	for range state.Level {
+		if duration >= cfg.MaxDuration {
+			break
+		}
+		duration *= 2
+	}
+	duration = min(duration, cfg.MaxDuration)
+	until := now.Add(duration)
+	next := &lockoutState{Level: state.Level + 1, Since: now, LockedUntil: &until}
+	if err := b.saveLockout(c, userUUID, next, duration+cfg.ResetAfter); err != nil {
+		return err
+	}
+	b.log.Named("LOCKOUT").Warn("登录失败次数过多，锁定账号",
+		zap.Stringer("user", userUUID), zap.Int64("failures", failures), zap.Int("level", next.Level), zap.Duration("duration", duration))
+	return nil
+}
+
+// loadLockout 读取账号的锁定状态，不存在时返回 nil。
+func (b *base) loadLockout(c *gin.Context, userUUID uuid.UUID) (*lockoutState, error) {
+	value, err := b.rdb.Get(c, fmt.Sprintf(constants.RedisLockout, userUUID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state lockoutState
+	if err := jsoniter.UnmarshalFromString(value, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// saveLockout 保存账号的锁定状态。
+func (b *base) saveLockout(c *gin.Context, userUUID uuid.UUID, state *lockoutState, ttl time.Duration) error {
+	value, err := jsoniter.MarshalToString(state)
+	if err != nil {
+		return err
+	}
+	return b.rdb.Set(c, fmt.Sprintf(constants.RedisLockout, userUUID), value, ttl).Err()
+}
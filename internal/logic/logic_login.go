@@ -0,0 +1,120 @@
+package logic
+
+import (
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LoginLogic 负责登录流程的收尾工作：判断是否需要两步验证或修改密码、写入登录日志、更新最后登录时间与签发令牌。
+type LoginLogic struct {
+	base
+}
+
+// NewLogin 创建一个新的 LoginLogic 实例。
+func NewLogin(c *gin.Context) *LoginLogic {
+	return &LoginLogic{base: newBase(c)}
+}
+
+// Succeed 在用户通过第一步身份验证后继续登录流程。
+//
+// 用户已启用两步验证，或持有 SUPER_ADMIN、ADMIN 角色时返回两步验证挑战，否则直接完成登录并签发令牌。
+// 参数 providerUUID 仅在第三方登录时传入，其余登录方式传入 nil。
+func (l *LoginLogic) Succeed(c *gin.Context, user *entity.User, loginType string, providerUUID *uuid.UUID) (*dto.Login, error) {
+	if err := l.checkTenant(user); err != nil {
+		return nil, err
+	}
+	required, methods, err := l.mfaStatus(user.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		challenge, err := l.newMFAChallenge(c, &mfaChallenge{UserUUID: user.UUID, LoginType: loginType, ProviderUUID: providerUUID}, methods)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.Login{MFA: challenge}, nil
+	}
+
+	return l.complete(c, user, loginType, providerUUID)
+}
+
+// complete 在全部验证通过后完成登录，建立 SSO 会话并返回签发的令牌。
+//
+// 使用密码登录且密码必须修改时不签发令牌，而是返回修改密码的挑战，设置新密码后再次调用本方法完成登录。
+func (l *LoginLogic) complete(c *gin.Context, user *entity.User, loginType string, providerUUID *uuid.UUID) (*dto.Login, error) {
+	if err := l.checkTenant(user); err != nil {
+		return nil, err
+	}
+	if loginType == constants.LoginTypePassword {
+		if reason := l.passwordChangeReason(user); reason != "" {
+			challenge, err := l.newPasswordChallenge(c, user, &passwordChallenge{LoginType: loginType, ProviderUUID: providerUUID, Reason: reason})
+			if err != nil {
+				return nil, err
+			}
+			return &dto.Login{PasswordChange: challenge}, nil
+		}
+	}
+	now := time.Now()
+	if err := l.db.Model(user).Update("last_login_at", now).Error; err != nil {
+		return nil, err
+	}
+
+	sessionID, err := l.newSession(c, user.UUID, loginType)
+	if err != nil {
+		return nil, err
+	}
+	token, err := NewToken(c).Issue(c, user.UUID, sessionID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	l.record(c, &user.UUID, loginType, providerUUID, true, nil)
+	return &dto.Login{Token: token}, nil
+}
+
+// checkTenant 检查用户是否属于当前请求的租户。
+//
+// 账号查询均限定在当前租户内，这里拦截通过第三方账号、通行密钥或两步验证挑战等以用户UUID关联的方式
+// 跨租户登录的情况。
+func (l *LoginLogic) checkTenant(user *entity.User) error {
+	if user.TenantUUID != l.tenant.UUID {
+		return result.ErrForbidden.WithMessage("该账号不属于当前租户")
+	}
+	return nil
+}
+
+// Failed 记录一次失败的登录尝试，凭据错误导致的失败次数过多时锁定账号。
+//
+// 参数 userUUID 在无法识别用户时可为 nil，reason 为 constants 中定义的失败原因。
+func (l *LoginLogic) Failed(c *gin.Context, userUUID *uuid.UUID, loginType string, providerUUID *uuid.UUID, reason string) {
+	l.record(c, userUUID, loginType, providerUUID, false, &reason)
+	if userUUID == nil {
+		return
+	}
+	if err := l.registerFailure(c, *userUUID, reason); err != nil {
+		l.log.Named("LOCKOUT").Warn("统计登录失败次数失败", zap.Error(err))
+	}
+}
+
+// record 写入登录日志，写入失败只记录日志而不影响登录结果。
+func (l *LoginLogic) record(c *gin.Context, userUUID *uuid.UUID, loginType string, providerUUID *uuid.UUID, success bool, reason *string) {
+	loginLog := entity.LoginLog{
+		UserUUID:      userUUID,
+		LoginType:     loginType,
+		ProviderUUID:  providerUUID,
+		IPAddress:     c.ClientIP(),
+		UserAgent:     c.Request.UserAgent(),
+		IsSuccess:     success,
+		FailureReason: reason,
+	}
+	if err := l.db.Create(&loginLog).Error; err != nil {
+		l.log.Named("LOGIN").Warn("写入登录日志失败", zap.Error(err))
+	}
+}
@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/logout"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LogoutLogic 负责单点登出：结束 SSO 会话，并按 OpenID Connect 前端通道与后端通道登出规范通知接入应用。
+type LogoutLogic struct {
+	base
+}
+
+// NewLogout 创建一个新的 LogoutLogic 实例。
+func NewLogout(c *gin.Context) *LogoutLogic {
+	return &LogoutLogic{base: newBase(c)}
+}
+
+// Logout 结束浏览器当前的 SSO 会话并删除会话 Cookie，返回需要由浏览器加载的前端通道登出地址与登出后的跳转地址。
+//
+// 浏览器没有可用的会话时同样返回成功，重复登出不会报错。
+func (l *LogoutLogic) Logout(c *gin.Context, req *request.Logout) (*dto.Logout, error) {
+	ended := &dto.Logout{FrontchannelLogoutURIs: []string{}}
+	if req.PostLogoutRedirectURI != "" {
+		application, err := l.findApplication(req.ApplicationID)
+		if err != nil {
+			return nil, err
+		}
+		if !redirectAllowed(application, req.PostLogoutRedirectURI) {
+			return nil, result.ErrParameter.WithMessage("登出后的跳转地址未在应用中登记")
+		}
+		location, err := redirectWithQuery(req.PostLogoutRedirectURI, map[string]string{"state": req.State})
+		if err != nil {
+			return nil, err
+		}
+		ended.RedirectURI = location
+	}
+
+	sessionID, session, err := l.currentSession(c)
+	if err != nil {
+		return nil, err
+	}
+	l.setSessionCookie(c, "", -1)
+	if session == nil {
+		return ended, nil
+	}
+	uris, err := l.endSession(c, sessionID, session.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+	ended.FrontchannelLogoutURIs = uris
+	return ended, nil
+}
+
+// endSession 结束一个 SSO 会话，撤销会话中签发的全部令牌，并通知会话中仍持有有效令牌的接入应用。
+//
+// 返回接入应用的前端通道登出地址；后端通道通知交由后台发送器投递，加入队列失败只记录日志，不影响会话的结束。
+func (b *base) endSession(c *gin.Context, sessionID string, userUUID uuid.UUID) ([]string, error) {
+	var applications []entity.Application
+	tokens := b.db.Model(&entity.UserToken{}).Select("application_uuid").Where("session_id = ? AND is_revoked = ?", sessionID, false)
+	if err := b.db.Where("uuid IN (?)", tokens).Find(&applications).Error; err != nil {
+		return nil, err
+	}
+	err := b.db.Model(&entity.UserToken{}).
+		Where("session_id = ? AND is_revoked = ?", sessionID, false).
+		Update("is_revoked", true).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := b.destroySession(c, sessionID); err != nil {
+		return nil, err
+	}
+	if err := b.invalidateAuth(c, userUUID); err != nil {
+		return nil, err
+	}
+
+	log := b.log.Named("LOGOUT")
+	uris := make([]string, 0, len(applications))
+	for _, application := range applications {
+		if application.BackchannelLogoutURI != nil && *application.BackchannelLogoutURI != "" {
+			if err := b.notifyBackchannel(c, &application, sessionID, userUUID); err != nil {
+				log.Error("登出通知加入队列失败", zap.String("application", application.ApplicationID), zap.Error(err))
+			}
+		}
+		if application.FrontchannelLogoutURI != nil && *application.FrontchannelLogoutURI != "" {
+			uri, err := redirectWithQuery(*application.FrontchannelLogoutURI, map[string]string{
+				"iss": b.issuer(),
+				"sid": sessionID,
+			})
+			if err != nil {
+				log.Warn("应用的前端通道登出地址无效", zap.String("application", application.ApplicationID), zap.Error(err))
+				continue
+			}
+			uris = append(uris, uri)
+		}
+	}
+	log.Info("结束 SSO 会话", zap.Stringer("user", userUUID), zap.Int("applications", len(applications)))
+	return uris, nil
+}
+
+// notifyBackchannel 使用接入应用的应用密钥签名登出令牌，并交由后台发送器投递到应用的后端通道登出地址。
+func (b *base) notifyBackchannel(c *gin.Context, application *entity.Application, sessionID string, userUUID uuid.UUID) error {
+	now := time.Now()
+	token, err := logout.Sign(application.ApplicationSecret, &logout.Claims{
+		Issuer:    b.issuer(),
+		Audience:  application.ApplicationID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(b.sso.Logout.TokenTTL).Unix(),
+		ID:        utility.RandomToken(16),
+		Subject:   userUUID.String(),
+		SessionID: sessionID,
+		Events:    map[string]struct{}{logout.EventBackchannelLogout: {}},
+	})
+	if err != nil {
+		return err
+	}
+	return b.logout.Enqueue(c, *application.BackchannelLogoutURI, token)
+}
@@ -0,0 +1,351 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// errMFAChallengeInvalid 表示两步验证挑战不存在、已过期或已被使用。
+	errMFAChallengeInvalid = result.ErrUnauthorized.WithMessage("两步验证已失效，请重新登录")
+	// errMFAAttemptsExceeded 表示两步验证失败次数过多，挑战已作废。
+	errMFAAttemptsExceeded = result.ErrUnauthorized.WithMessage("两步验证失败次数过多，请重新登录")
+)
+
+// mfaChallenge 表示一次两步验证挑战在 Redis 中保存的登录上下文。
+//
+// 字段说明：
+//   - UserUUID: 通过第一步验证的用户UUID。
+//   - LoginType: 第一步使用的登录方式，完成登录时写入登录日志。
+//   - ProviderUUID: 第三方登录时的提供商UUID。
+//   - Methods: 本次挑战可用的验证方式。
+//   - EnrollRequired: 用户必须启用两步验证但尚未登记，本次挑战允许登记身份验证器。
+type mfaChallenge struct {
+	UserUUID       uuid.UUID  `json:"user_uuid"`
+	LoginType      string     `json:"login_type"`
+	ProviderUUID   *uuid.UUID `json:"provider_uuid,omitempty"`
+	Methods        []string   `json:"methods"`
+	EnrollRequired bool       `json:"enroll_required,omitempty"`
+}
+
+// MFALogic 负责登录流程中的两步验证，包括管理员首次登录时登记身份验证器。
+type MFALogic struct {
+	base
+}
+
+// NewMFA 创建一个新的 MFALogic 实例。
+func NewMFA(c *gin.Context) *MFALogic {
+	return &MFALogic{base: newBase(c)}
+}
+
+// mfaStatus 判断用户登录时是否需要两步验证，并返回用户可用的验证方式。
+//
+// 已启用 TOTP 的用户总是需要两步验证；持有 SUPER_ADMIN 或 ADMIN 角色的用户即使尚未启用也必须验证。
+// 已注册通行密钥的用户可以用通行密钥完成第二步，但仅注册通行密钥不会使其他登录方式需要两步验证。
+func (b *base) mfaStatus(userUUID uuid.UUID) (required bool, methods []string, err error) {
+	var totpCount, passkeyCount int64
+	err = b.db.Model(&entity.UserTOTP{}).Where("user_uuid = ? AND is_enabled = ?", userUUID, true).Count(&totpCount).Error
+	if err != nil {
+		return false, nil, err
+	}
+	if totpCount > 0 {
+		methods = append(methods, constants.MFAMethodTOTP, constants.MFAMethodRecovery)
+	}
+	err = b.db.Model(&entity.UserWebAuthnCredential{}).Where("user_uuid = ?", userUUID).Count(&passkeyCount).Error
+	if err != nil {
+		return false, nil, err
+	}
+	if passkeyCount > 0 {
+		methods = append(methods, constants.MFAMethodPasskey)
+	}
+	if totpCount > 0 {
+		return true, methods, nil
+	}
+
+	required, err = b.mfaMandatory(userUUID)
+	return required, methods, err
+}
+
+// mfaMandatory 判断用户是否因持有管理角色而必须启用两步验证。
+func (b *base) mfaMandatory(userUUID uuid.UUID) (bool, error) {
+	return (&RoleLogic{base: *b}).HasAnyRole(userUUID, constants.RoleSuperAdmin, constants.RoleAdmin)
+}
+
+// newMFAChallenge 为通过第一步验证的登录创建两步验证挑战，methods 为用户可用的验证方式。
+//
+// 用户没有任何可用的验证方式时，挑战只允许登记身份验证器后使用动态口令完成验证。
+// 挑战以 Redis 哈希保存，context 字段为登录上下文，attempts 字段为失败次数，passkey 字段为通行密钥验证的流程数据。
+func (b *base) newMFAChallenge(c *gin.Context, challenge *mfaChallenge, methods []string) (*dto.MFAChallenge, error) {
+	challenge.EnrollRequired = len(methods) == 0
+	if challenge.EnrollRequired {
+		methods = []string{constants.MFAMethodTOTP}
+	}
+	challenge.Methods = methods
+	value, err := jsoniter.MarshalToString(challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	token := utility.RandomToken(32)
+	key := fmt.Sprintf(constants.RedisMFAChallenge, token)
+	_, err = b.rdb.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.HSet(c, key, "context", value, "attempts", 0)
+		pipe.Expire(c, key, b.sso.MFA.ChallengeTTL)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MFAChallenge{
+		Token:          token,
+		Methods:        methods,
+		EnrollRequired: challenge.EnrollRequired,
+		ExpiresIn:      int64(b.sso.MFA.ChallengeTTL.Seconds()),
+	}, nil
+}
+
+// Enroll 在登录过程中为必须启用两步验证的用户登记身份验证器，随后使用 VerifyTOTP 确认。
+func (m *MFALogic) Enroll(c *gin.Context, challengeToken string) (*dto.TOTPEnrollment, error) {
+	challenge, user, err := m.loadChallenge(c, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.EnrollRequired {
+		return nil, result.ErrConflict.WithMessage("已启用两步验证，请直接输入动态口令")
+	}
+	return m.enrollTOTP(user)
+}
+
+// VerifyTOTP 使用动态口令完成两步验证。
+//
+// 对于登录过程中登记的身份验证器，通过校验即视为确认启用，并在结果中返回新生成的恢复码。
+func (m *MFALogic) VerifyTOTP(c *gin.Context, challengeToken, code string) (*dto.Login, error) {
+	challenge, user, err := m.loadChallenge(c, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(challenge.Methods, constants.MFAMethodTOTP) {
+		return nil, errTOTPNotEnrolled
+	}
+
+	var recoveryCodes []string
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		userTOTP, err := verifyTOTP(tx, user.UUID, code)
+		if err != nil {
+			return err
+		}
+		if !userTOTP.IsEnabled {
+			if recoveryCodes, err = confirmTOTP(c, tx, userTOTP); err != nil {
+				return err
+			}
+		}
+		return m.claimChallenge(c, challengeToken)
+	})
+	if errors.Is(err, errTOTPInvalid) {
+		return nil, m.challengeFailed(c, challengeToken, challenge, constants.FailureTOTPInvalid, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	login, err := NewLogin(c).complete(c, user, challenge.LoginType, challenge.ProviderUUID)
+	if err != nil {
+		return nil, err
+	}
+	login.RecoveryCodes = recoveryCodes
+	return login, nil
+}
+
+// VerifyRecovery 使用一次性恢复码完成两步验证，使用过的恢复码立即作废。
+func (m *MFALogic) VerifyRecovery(c *gin.Context, challengeToken, code string) (*dto.Login, error) {
+	challenge, user, err := m.loadChallenge(c, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(challenge.Methods, constants.MFAMethodRecovery) {
+		return nil, result.ErrParameter.WithMessage("尚未启用动态口令两步验证，无法使用恢复码")
+	}
+
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		if err := useRecoveryCode(tx, user.UUID, code); err != nil {
+			return err
+		}
+		return m.claimChallenge(c, challengeToken)
+	})
+	if errors.Is(err, errRecoveryInvalid) {
+		return nil, m.challengeFailed(c, challengeToken, challenge, constants.FailureRecoveryInvalid, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLogin(c).complete(c, user, challenge.LoginType, challenge.ProviderUUID)
+}
+
+// BeginPasskey 开始使用通行密钥完成两步验证，流程数据保存在挑战中。
+func (m *MFALogic) BeginPasskey(c *gin.Context, challengeToken string) (*dto.PasskeyCeremony, error) {
+	challenge, user, err := m.loadChallenge(c, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(challenge.Methods, constants.MFAMethodPasskey) {
+		return nil, result.ErrParameter.WithMessage("尚未注册通行密钥")
+	}
+
+	owner, err := loadPasskeyUser(m.db, user)
+	if err != nil {
+		return nil, err
+	}
+	assertion, session, err := m.webAuthn.BeginLogin(owner)
+	if err != nil {
+		return nil, err
+	}
+	value, err := jsoniter.MarshalToString(session)
+	if err != nil {
+		return nil, err
+	}
+
+	// 与 challengeFailed 相同，ExpireNX 保证挑战恰好过期时写入重新创建的键不会永久残留
+	key := fmt.Sprintf(constants.RedisMFAChallenge, challengeToken)
+	_, err = m.rdb.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.HSet(c, key, "passkey", value)
+		pipe.ExpireNX(c, key, m.sso.MFA.ChallengeTTL)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PasskeyCeremony{Token: challengeToken, Options: assertion}, nil
+}
+
+// VerifyPasskey 使用通行密钥完成两步验证。
+func (m *MFALogic) VerifyPasskey(c *gin.Context, challengeToken string, credentialJSON []byte) (*dto.Login, error) {
+	challenge, user, err := m.loadChallenge(c, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	value, err := m.rdb.HGet(c, fmt.Sprintf(constants.RedisMFAChallenge, challengeToken), "passkey").Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, errPasskeyCeremonyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	var session webauthn.SessionData
+	if err := jsoniter.UnmarshalFromString(value, &session); err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(credentialJSON)
+	if err != nil {
+		return nil, errPasskeyMalformed
+	}
+
+	owner, err := loadPasskeyUser(m.db, user)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := m.webAuthn.ValidateLogin(owner, session, parsed)
+	if err != nil {
+		m.log.Named("PASSKEY").Info("两步验证通行密钥校验失败", zap.Stringer("user", user.UUID), zap.Error(err))
+		return nil, m.challengeFailed(c, challengeToken, challenge, constants.FailurePasskeyInvalid, errPasskeyInvalid)
+	}
+
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		if err := passkeyUsed(tx, user.UUID, credential); err != nil {
+			return err
+		}
+		return m.claimChallenge(c, challengeToken)
+	})
+	if errors.Is(err, errPasskeyCloned) {
+		m.log.Named("PASSKEY").Warn("两步验证通行密钥校验失败", zap.Stringer("user", user.UUID), zap.Error(err))
+		return nil, m.challengeFailed(c, challengeToken, challenge, constants.FailurePasskeyInvalid, errPasskeyInvalid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLogin(c).complete(c, user, challenge.LoginType, challenge.ProviderUUID)
+}
+
+// loadChallenge 读取两步验证挑战及其对应的用户，用户已被停用或账号被临时锁定时拒绝继续验证。
+func (m *MFALogic) loadChallenge(c *gin.Context, challengeToken string) (*mfaChallenge, *entity.User, error) {
+	value, err := m.rdb.HGet(c, fmt.Sprintf(constants.RedisMFAChallenge, challengeToken), "context").Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil, errMFAChallengeInvalid
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var challenge mfaChallenge
+	if err := jsoniter.UnmarshalFromString(value, &challenge); err != nil {
+		return nil, nil, err
+	}
+	user, err := findUser(m.db, challenge.UserUUID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, errMFAChallengeInvalid
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.IsActive {
+		return nil, nil, result.ErrForbidden.WithMessage("账号已被停用")
+	}
+	if err := m.checkLockout(c, user.UUID); err != nil {
+		NewLogin(c).Failed(c, &user.UUID, challenge.LoginType, challenge.ProviderUUID, constants.FailureAccountLocked)
+		return nil, nil, err
+	}
+	return &challenge, user, nil
+}
+
+// challengeFailed 记录一次失败的两步验证，失败次数达到上限时作废挑战。
+func (m *MFALogic) challengeFailed(c *gin.Context, challengeToken string, challenge *mfaChallenge, reason string, cause error) error {
+	NewLogin(c).Failed(c, &challenge.UserUUID, challenge.LoginType, challenge.ProviderUUID, reason)
+
+	// 挑战可能恰好在此期间过期，ExpireNX 保证自增重新创建的键不会永久残留
+	key := fmt.Sprintf(constants.RedisMFAChallenge, challengeToken)
+	var attempts *redis.IntCmd
+	_, err := m.rdb.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		attempts = pipe.HIncrBy(c, key, "attempts", 1)
+		pipe.ExpireNX(c, key, m.sso.MFA.ChallengeTTL)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if attempts.Val() >= int64(m.sso.MFA.MaxAttempts) {
+		if err := m.rdb.Del(c, key).Err(); err != nil {
+			return err
+		}
+		return errMFAAttemptsExceeded
+	}
+	return cause
+}
+
+// claimChallenge 作废挑战，应在第二步验证的事务提交前调用；挑战只能使用一次，并发提交时只有一次能够成功。
+func (m *MFALogic) claimChallenge(c *gin.Context, challengeToken string) error {
+	deleted, err := m.rdb.Del(c, fmt.Sprintf(constants.RedisMFAChallenge, challengeToken)).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errMFAChallengeInvalid
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// errBindingInactive 表示第三方账号的绑定记录已被停用。
+	errBindingInactive = result.ErrForbidden.WithMessage("该第三方账号的绑定已被停用")
+	// errBindingOccupied 表示第三方账号已绑定到其他用户。
+	errBindingOccupied = result.ErrConflict.WithMessage("该第三方账号已绑定其他用户")
+	// errProviderBound 表示用户在同一提供商下已绑定了其他账号。
+	errProviderBound = result.ErrConflict.WithMessage("已绑定该平台的其他账号，请先解绑")
+)
+
+// enabledProvider 根据提供商代码获取当前租户中已启用的第三方提供商配置。
+func (b *base) enabledProvider(code string) (*entity.ThirdPartyProvider, error) {
+	var provider entity.ThirdPartyProvider
+	err := b.inTenant(b.db).Where(&entity.ThirdPartyProvider{Code: code, IsEnabled: true}).First(&provider).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, result.ErrNotFound.WithMessage("第三方登录方式不存在或未启用")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
+
+// oauthState 表示一次第三方授权在 Redis 中保存的上下文。
+//
+// 字段说明：
+//   - Provider: 发起授权的提供商代码。
+//   - Nonce: OIDC 的 nonce，用于校验 ID Token，非 OIDC 提供商为空。
+//   - CodeVerifier: PKCE 的 code_verifier，不支持 PKCE 的提供商为空。
+//   - LinkUser: 发起绑定的用户UUID，为空表示本次授权用于登录。
+type oauthState struct {
+	Provider     string     `json:"provider"`
+	Nonce        string     `json:"nonce,omitempty"`
+	CodeVerifier string     `json:"code_verifier,omitempty"`
+	LinkUser     *uuid.UUID `json:"link_user,omitempty"`
+}
+
+// newOAuthState 为一次第三方授权生成 state，并在 Redis 中记录其对应的授权上下文。
+func (b *base) newOAuthState(c *gin.Context, stateValue *oauthState) (string, error) {
+	value, err := jsoniter.MarshalToString(stateValue)
+	if err != nil {
+		return "", err
+	}
+	state := utility.RandomToken(24)
+	key := fmt.Sprintf(constants.RedisOAuthState, state)
+	if err := b.rdb.Set(c, key, value, b.sso.OAuth.StateTTL).Err(); err != nil {
+		return "", err
+	}
+	return state, nil
+}
+
+// consumeOAuthState 校验并消费 state，返回其对应的授权上下文；state 只能使用一次。
+func (b *base) consumeOAuthState(c *gin.Context, state string) (*oauthState, error) {
+	key := fmt.Sprintf(constants.RedisOAuthState, state)
+	value, err := b.rdb.GetDel(c, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, result.ErrParameter.WithMessage("授权状态无效或已过期，请重新发起登录")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stateValue oauthState
+	if err := jsoniter.UnmarshalFromString(value, &stateValue); err != nil {
+		return nil, err
+	}
+	return &stateValue, nil
+}
+
+// thirdPartyLogin 在事务中通过 bind 找到或创建第三方身份对应的用户，随后完成登录的第一步验证。
+//
+// bind 在绑定记录被停用时应返回 errBindingInactive 与其绑定的用户，以便写入对应的失败原因。
+func (b *base) thirdPartyLogin(c *gin.Context, provider *entity.ThirdPartyProvider, bind func(tx *gorm.DB) (*entity.User, error)) (*dto.Login, error) {
+	var user *entity.User
+	err := b.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		user, err = bind(tx)
+		return err
+	})
+	if errors.Is(err, errBindingInactive) {
+		NewLogin(c).Failed(c, &user.UUID, constants.LoginTypeThirdParty, &provider.UUID, constants.FailureBindingInactive)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		NewLogin(c).Failed(c, &user.UUID, constants.LoginTypeThirdParty, &provider.UUID, constants.FailureUserInactive)
+		return nil, result.ErrForbidden.WithMessage("账号已被停用")
+	}
+	return NewLogin(c).Succeed(c, user, constants.LoginTypeThirdParty, &provider.UUID)
+}
+
+// thirdPartyFailed 记录第三方接口调用失败并返回统一的错误，具体原因只写入日志。
+func (b *base) thirdPartyFailed(c *gin.Context, provider *entity.ThirdPartyProvider, err error) error {
+	b.log.Named("OAUTH").Warn("第三方接口调用失败", zap.String("provider", provider.Code), zap.Error(err))
+	NewLogin(c).Failed(c, nil, constants.LoginTypeThirdParty, &provider.UUID, constants.FailureThirdPartyError)
+	return result.ErrThirdParty.WithMessage(provider.Name + "登录失败，请稍后重试")
+}
+
+// createThirdPartyUser 为首次通过第三方登录的用户创建账号、资料并分配默认角色。
+//
+// 账号归属 tenantUUID 对应的租户；用户名由 usernamePrefix 加随机串组成，邮箱与密码留空，用户可在之后自行补充。
+func createThirdPartyUser(tx *gorm.DB, tenantUUID uuid.UUID, usernamePrefix string, profile *entity.UserProfile) (*entity.User, error) {
+	user := entity.User{
+		TenantUUID: tenantUUID,
+		Username:   usernamePrefix + utility.RandomToken(9),
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return nil, err
+	}
+
+	profile.UserUUID = user.UUID
+	if err := tx.Create(profile).Error; err != nil {
+		return nil, err
+	}
+
+	var role entity.Role
+	if err := tx.Where(&entity.Role{Name: constants.RoleUser}).First(&role).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Create(&entity.UserRole{UserUUID: user.UUID, RoleUUID: role.UUID}).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// findUser 根据 UUID 查询用户。
+func findUser(tx *gorm.DB, userUUID uuid.UUID) (*entity.User, error) {
+	var user entity.User
+	if err := tx.First(&user, "uuid = ?", userUUID).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
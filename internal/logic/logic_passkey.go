@@ -0,0 +1,432 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	xUtil "github.com/bamboo-services/bamboo-base-go/utility"
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultPasskeyNickname 是注册通行密钥时未填写名称所使用的默认名称。
+const defaultPasskeyNickname = "通行密钥"
+
+var (
+	// errPasskeyInvalid 表示通行密钥验证失败，具体原因只写入日志。
+	errPasskeyInvalid = result.ErrUnauthorized.WithMessage("通行密钥验证失败")
+	// errPasskeyCeremonyInvalid 表示通行密钥流程不存在、已过期或已被使用。
+	errPasskeyCeremonyInvalid = result.ErrParameter.WithMessage("通行密钥流程已失效，请重试")
+	// errPasskeyMalformed 表示浏览器返回的凭据无法解析。
+	errPasskeyMalformed = result.ErrParameter.WithMessage("通行密钥数据格式错误")
+	// errPasskeyNotFound 表示通行密钥不存在或不属于当前用户。
+	errPasskeyNotFound = result.ErrNotFound.WithMessage("通行密钥不存在")
+	// errPasskeyCloned 表示签名计数器没有递增，身份验证器可能被克隆。
+	errPasskeyCloned = errors.New("签名计数器没有递增，身份验证器可能被克隆")
+)
+
+// passkeyCeremony 表示一次通行密钥注册或免密登录流程在 Redis 中保存的上下文。
+//
+// 字段说明：
+//   - UserUUID: 注册流程中发起注册的用户UUID；免密登录流程开始时尚不知道用户，为空。
+//   - Session: 依赖方生成的流程数据，包含挑战值与验证要求。
+type passkeyCeremony struct {
+	UserUUID *uuid.UUID           `json:"user_uuid,omitempty"`
+	Session  webauthn.SessionData `json:"session"`
+}
+
+// passkeyUser 将用户及其已注册的通行密钥适配为 webauthn.User，用户句柄为用户 UUID 的 16 字节原始值。
+type passkeyUser struct {
+	user        *entity.User
+	credentials []*entity.UserWebAuthnCredential
+}
+
+// WebAuthnID 返回用户句柄。
+func (p *passkeyUser) WebAuthnID() []byte {
+	return p.user.UUID[:]
+}
+
+// WebAuthnName 返回在身份验证器中区分账号的名称，优先使用邮箱。
+func (p *passkeyUser) WebAuthnName() string {
+	if p.user.Email != nil {
+		return *p.user.Email
+	}
+	return p.user.Username
+}
+
+// WebAuthnDisplayName 返回在身份验证器中显示的用户名称。
+func (p *passkeyUser) WebAuthnDisplayName() string {
+	return p.user.Username
+}
+
+// WebAuthnCredentials 返回用户已注册的通行密钥。
+func (p *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(p.credentials))
+	for _, record := range p.credentials {
+		var transports []protocol.AuthenticatorTransport
+		for _, transport := range splitTransports(record.Transports) {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+		credentials = append(credentials, webauthn.Credential{
+			ID:              record.CredentialID,
+			PublicKey:       record.PublicKey,
+			AttestationType: record.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: record.BackupEligible,
+				BackupState:    record.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    record.AAGUID[:],
+				SignCount: uint32(record.SignCount),
+			},
+		})
+	}
+	return credentials
+}
+
+// PasskeyLogic 负责通行密钥的注册、管理与免密登录。
+type PasskeyLogic struct {
+	base
+}
+
+// NewPasskey 创建一个新的 PasskeyLogic 实例。
+func NewPasskey(c *gin.Context) *PasskeyLogic {
+	return &PasskeyLogic{base: newBase(c)}
+}
+
+// List 列出当前登录用户注册的通行密钥。
+func (p *PasskeyLogic) List(c *gin.Context) ([]*dto.Passkey, error) {
+	var records []*entity.UserWebAuthnCredential
+	if err := p.db.Where("user_uuid = ?", currentUser(c).UUID).Order("created_at").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	passkeys := make([]*dto.Passkey, 0, len(records))
+	for _, record := range records {
+		passkeys = append(passkeys, passkeyDTO(record))
+	}
+	return passkeys, nil
+}
+
+// BeginRegistration 为当前登录用户开始注册通行密钥。
+//
+// 要求身份验证器创建可发现凭据并验证用户身份，使注册的通行密钥可以直接用于免密登录；
+// 用户已注册的通行密钥会被排除，避免同一身份验证器重复注册。
+func (p *PasskeyLogic) BeginRegistration(c *gin.Context) (*dto.PasskeyCeremony, error) {
+	user := currentUser(c)
+	owner, err := loadPasskeyUser(p.db, user)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, session, err := p.webAuthn.BeginRegistration(owner,
+		webauthn.WithExclusions(webauthn.Credentials(owner.WebAuthnCredentials()).CredentialDescriptors()),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{UserVerification: protocol.VerificationRequired}),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.newPasskeyCeremony(c, &passkeyCeremony{UserUUID: &user.UUID, Session: *session})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PasskeyCeremony{Token: token, Options: creation}, nil
+}
+
+// FinishRegistration 校验浏览器返回的凭据并保存通行密钥。
+func (p *PasskeyLogic) FinishRegistration(c *gin.Context, token, nickname string, credentialJSON []byte) (*dto.Passkey, error) {
+	user := currentUser(c)
+	ceremony, err := p.consumePasskeyCeremony(c, token)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.UserUUID == nil || *ceremony.UserUUID != user.UUID {
+		return nil, errPasskeyCeremonyInvalid
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(credentialJSON)
+	if err != nil {
+		return nil, errPasskeyMalformed
+	}
+	owner, err := loadPasskeyUser(p.db, user)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := p.webAuthn.CreateCredential(owner, ceremony.Session, parsed)
+	if err != nil {
+		p.log.Named("PASSKEY").Info("通行密钥注册校验失败", zap.Stringer("user", user.UUID), zap.Error(err))
+		return nil, result.ErrParameter.WithMessage("通行密钥注册失败")
+	}
+
+	aaguid, err := uuid.FromBytes(credential.Authenticator.AAGUID)
+	if err != nil {
+		aaguid = uuid.Nil
+	}
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+	record := &entity.UserWebAuthnCredential{
+		UserUUID:        user.UUID,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		AAGUID:          aaguid,
+		SignCount:       int64(credential.Authenticator.SignCount),
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+		Nickname:        xUtil.DefaultIfBlank(strings.TrimSpace(nickname), defaultPasskeyNickname),
+	}
+
+	err = p.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entity.UserWebAuthnCredential{}).Where("credential_id = ?", record.CredentialID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return result.ErrConflict.WithMessage("该通行密钥已被注册")
+		}
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditPasskeyRegister, constants.ResourcePasskey, &record.UUID, map[string]any{
+			"user_uuid": user.UUID,
+			"nickname":  record.Nickname,
+			"aaguid":    record.AAGUID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return passkeyDTO(record), nil
+}
+
+// Rename 修改当前登录用户的一个通行密钥的名称。
+func (p *PasskeyLogic) Rename(c *gin.Context, passkeyUUID uuid.UUID, nickname string) (*dto.Passkey, error) {
+	userUUID := currentUser(c).UUID
+	var record entity.UserWebAuthnCredential
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("uuid = ? AND user_uuid = ?", passkeyUUID, userUUID).First(&record).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errPasskeyNotFound
+		}
+		if err != nil {
+			return err
+		}
+		previous := record.Nickname
+		if err := tx.Model(&record).Update("nickname", nickname).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditPasskeyRename, constants.ResourcePasskey, &record.UUID, map[string]any{
+			"user_uuid": userUUID,
+			"from":      previous,
+			"to":        nickname,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return passkeyDTO(&record), nil
+}
+
+// Delete 删除当前登录用户的一个通行密钥；不能删除账号唯一的登录方式。
+func (p *PasskeyLogic) Delete(c *gin.Context, passkeyUUID uuid.UUID) error {
+	userUUID := currentUser(c).UUID
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "uuid = ?", userUUID).Error; err != nil {
+			return err
+		}
+
+		var record entity.UserWebAuthnCredential
+		err := tx.Where("uuid = ? AND user_uuid = ?", passkeyUUID, userUUID).First(&record).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errPasskeyNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if !user.HasPassword() {
+			count, err := countLoginMethods(tx, &user)
+			if err != nil {
+				return err
+			}
+			if count <= 1 {
+				return errLastLoginMethod
+			}
+		}
+
+		if err := tx.Delete(&record).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditPasskeyDelete, constants.ResourcePasskey, &record.UUID, map[string]any{
+			"user_uuid": userUUID,
+			"nickname":  record.Nickname,
+		})
+	})
+}
+
+// BeginLogin 开始一次免密登录，由浏览器让用户选择本站的通行密钥。
+func (p *PasskeyLogic) BeginLogin(c *gin.Context) (*dto.PasskeyCeremony, error) {
+	assertion, session, err := p.webAuthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.newPasskeyCeremony(c, &passkeyCeremony{Session: *session})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PasskeyCeremony{Token: token, Options: assertion}, nil
+}
+
+// FinishLogin 校验通行密钥并完成免密登录。
+//
+// 免密登录要求身份验证器验证用户身份（生物识别或 PIN），通行密钥本身即满足两步验证，因此直接签发令牌。
+func (p *PasskeyLogic) FinishLogin(c *gin.Context, token string, credentialJSON []byte) (*dto.Login, error) {
+	ceremony, err := p.consumePasskeyCeremony(c, token)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony.UserUUID != nil {
+		return nil, errPasskeyCeremonyInvalid
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(credentialJSON)
+	if err != nil {
+		return nil, errPasskeyMalformed
+	}
+
+	var owner *passkeyUser
+	credential, err := p.webAuthn.ValidateDiscoverableLogin(func(_, userHandle []byte) (webauthn.User, error) {
+		userUUID, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return nil, err
+		}
+		user, err := findUser(p.db, userUUID)
+		if err != nil {
+			return nil, err
+		}
+		owner, err = loadPasskeyUser(p.db, user)
+		return owner, err
+	}, ceremony.Session, parsed)
+	if err == nil {
+		if err = passkeyUsed(p.db, owner.user.UUID, credential); err != nil && !errors.Is(err, errPasskeyCloned) {
+			return nil, err
+		}
+	}
+	if err != nil {
+		var userUUID *uuid.UUID
+		if owner != nil {
+			userUUID = &owner.user.UUID
+		}
+		p.log.Named("PASSKEY").Info("通行密钥登录校验失败", zap.Error(err))
+		NewLogin(c).Failed(c, userUUID, constants.LoginTypePasskey, nil, constants.FailurePasskeyInvalid)
+		return nil, errPasskeyInvalid
+	}
+
+	if err := p.checkLockout(c, owner.user.UUID); err != nil {
+		NewLogin(c).Failed(c, &owner.user.UUID, constants.LoginTypePasskey, nil, constants.FailureAccountLocked)
+		return nil, err
+	}
+	if !owner.user.IsActive {
+		NewLogin(c).Failed(c, &owner.user.UUID, constants.LoginTypePasskey, nil, constants.FailureUserInactive)
+		return nil, result.ErrForbidden.WithMessage("账号已被停用")
+	}
+	return NewLogin(c).complete(c, owner.user, constants.LoginTypePasskey, nil)
+}
+
+// newPasskeyCeremony 在 Redis 中保存通行密钥流程的上下文，返回流程令牌。
+func (b *base) newPasskeyCeremony(c *gin.Context, ceremony *passkeyCeremony) (string, error) {
+	value, err := jsoniter.MarshalToString(ceremony)
+	if err != nil {
+		return "", err
+	}
+	token := utility.RandomToken(32)
+	if err := b.rdb.Set(c, fmt.Sprintf(constants.RedisWebAuthn, token), value, b.sso.WebAuthn.CeremonyTTL).Err(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// consumePasskeyCeremony 取出并作废通行密钥流程的上下文，每个流程只能完成一次。
+func (b *base) consumePasskeyCeremony(c *gin.Context, token string) (*passkeyCeremony, error) {
+	value, err := b.rdb.GetDel(c, fmt.Sprintf(constants.RedisWebAuthn, token)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, errPasskeyCeremonyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ceremony passkeyCeremony
+	if err := jsoniter.UnmarshalFromString(value, &ceremony); err != nil {
+		return nil, err
+	}
+	return &ceremony, nil
+}
+
+// loadPasskeyUser 读取用户已注册的通行密钥。
+func loadPasskeyUser(tx *gorm.DB, user *entity.User) (*passkeyUser, error) {
+	var credentials []*entity.UserWebAuthnCredential
+	if err := tx.Where("user_uuid = ?", user.UUID).Find(&credentials).Error; err != nil {
+		return nil, err
+	}
+	return &passkeyUser{user: user, credentials: credentials}, nil
+}
+
+// passkeyUsed 记录通行密钥的一次成功使用，更新签名计数器与备份状态。
+//
+// 签名计数器没有递增说明身份验证器可能被克隆，此时返回 errPasskeyCloned 以拒绝本次验证。
+func passkeyUsed(tx *gorm.DB, userUUID uuid.UUID, credential *webauthn.Credential) error {
+	if credential.Authenticator.CloneWarning {
+		return errPasskeyCloned
+	}
+	return tx.Model(&entity.UserWebAuthnCredential{}).
+		Where("user_uuid = ? AND credential_id = ?", userUUID, credential.ID).
+		Updates(map[string]any{
+			"sign_count":   int64(credential.Authenticator.SignCount),
+			"backup_state": credential.Flags.BackupState,
+			"last_used_at": time.Now(),
+		}).Error
+}
+
+// splitTransports 拆分以逗号分隔的传输方式。
+func splitTransports(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+// passkeyDTO 将通行密钥实体转换为对外输出的结构。
+func passkeyDTO(record *entity.UserWebAuthnCredential) *dto.Passkey {
+	return &dto.Passkey{
+		UUID:           record.UUID,
+		Nickname:       record.Nickname,
+		AAGUID:         record.AAGUID,
+		Transports:     splitTransports(record.Transports),
+		BackupEligible: record.BackupEligible,
+		BackupState:    record.BackupState,
+		LastUsedAt:     record.LastUsedAt,
+		CreatedAt:      record.CreatedAt,
+	}
+}
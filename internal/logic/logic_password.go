@@ -0,0 +1,262 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// errPasswordChallengeInvalid 表示修改密码挑战不存在、已过期或已被使用。
+	errPasswordChallengeInvalid = result.ErrUnauthorized.WithMessage("修改密码已超时，请重新登录")
+	// errPasswordReused 表示新密码与当前密码或最近使用过的密码相同。
+	errPasswordReused = result.ErrParameter.WithMessage("不能使用最近使用过的密码")
+)
+
+// passwordChallenge 表示登录时修改密码的挑战在 Redis 中保存的登录上下文。
+//
+// 字段说明：
+//   - UserUUID: 通过验证的用户UUID。
+//   - LoginType: 登录方式，完成登录时写入登录日志。
+//   - ProviderUUID: 第三方登录时的提供商UUID。
+//   - Reason: 必须修改密码的原因。
+//   - Stamp: 创建挑战时密码哈希的摘要，密码已被其他方式修改时挑战作废。
+type passwordChallenge struct {
+	UserUUID     uuid.UUID  `json:"user_uuid"`
+	LoginType    string     `json:"login_type"`
+	ProviderUUID *uuid.UUID `json:"provider_uuid,omitempty"`
+	Reason       string     `json:"reason"`
+	Stamp        string     `json:"stamp"`
+}
+
+// PasswordLogic 负责用户修改密码：登录时按要求修改初始或已过期的密码，以及已登录用户修改自己的密码。
+//
+// 新密码需要满足密码策略，且不能与当前密码及最近使用过的密码相同。
+type PasswordLogic struct {
+	base
+}
+
+// NewPassword 创建一个新的 PasswordLogic 实例。
+func NewPassword(c *gin.Context) *PasswordLogic {
+	return &PasswordLogic{base: newBase(c)}
+}
+
+// Change 使用登录时得到的修改密码挑战设置新密码，并继续完成登录。
+//
+// 密码修改后用户在其他设备上的登录一并退出。
+func (p *PasswordLogic) Change(c *gin.Context, challengeToken, password string) (*dto.Login, error) {
+	key := fmt.Sprintf(constants.RedisPasswordChange, challengeToken)
+	value, err := p.rdb.Get(c, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, errPasswordChallengeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	var challenge passwordChallenge
+	if err := jsoniter.UnmarshalFromString(value, &challenge); err != nil {
+		return nil, err
+	}
+
+	var user entity.User
+	err = p.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "uuid = ?", challenge.UserUUID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errPasswordChallengeInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if !user.IsActive {
+			return result.ErrForbidden.WithMessage("账号已被停用")
+		}
+		if passwordStamp(&user) != challenge.Stamp {
+			return errPasswordChallengeInvalid
+		}
+		if err := p.setPassword(tx, &user, password, false); err != nil {
+			return err
+		}
+		deleted, err := p.rdb.Del(c, key).Result()
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return errPasswordChallengeInvalid
+		}
+		return writeAudit(c, tx, constants.AuditPasswordChange, constants.ResourceUser, &user.UUID, map[string]any{
+			"reason": challenge.Reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.signOutUser(c, user.UUID); err != nil {
+		return nil, err
+	}
+	return NewLogin(c).complete(c, &user, challenge.LoginType, challenge.ProviderUUID)
+}
+
+// Update 修改当前登录用户的密码，已设置密码时需要验证当前密码。
+//
+// 密码修改后用户在其他设备上的登录一并退出，当前设备保持登录。
+func (p *PasswordLogic) Update(c *gin.Context, currentPassword, newPassword string) error {
+	userUUID := currentUser(c).UUID
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "uuid = ?", userUUID).Error; err != nil {
+			return err
+		}
+		if user.HasPassword() {
+			if match, _ := p.policy.Verify(*user.PasswordHash, currentPassword); !match {
+				return result.ErrParameter.WithMessage("当前密码错误")
+			}
+		}
+		if err := p.setPassword(tx, &user, newPassword, false); err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditPasswordChange, constants.ResourceUser, &user.UUID, nil)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.revokeOtherDevices(c, userUUID, currentToken(c))
+	return err
+}
+
+// passwordChangeReason 返回用户使用密码登录时必须修改密码的原因，不需要修改时返回空字符串。
+func (b *base) passwordChangeReason(user *entity.User) string {
+	if !user.HasPassword() {
+		return ""
+	}
+	if user.PasswordChangeRequired {
+		return constants.PasswordChangeRequired
+	}
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	if b.policy.Expired(changedAt) {
+		return constants.PasswordChangeExpired
+	}
+	return ""
+}
+
+// newPasswordChallenge 为通过验证但必须修改密码的登录创建修改密码挑战。
+func (b *base) newPasswordChallenge(c *gin.Context, user *entity.User, challenge *passwordChallenge) (*dto.PasswordChallenge, error) {
+	challenge.UserUUID = user.UUID
+	challenge.Stamp = passwordStamp(user)
+	value, err := jsoniter.MarshalToString(challenge)
+	if err != nil {
+		return nil, err
+	}
+	token := utility.RandomToken(32)
+	if err := b.rdb.Set(c, fmt.Sprintf(constants.RedisPasswordChange, token), value, b.sso.Password.ChangeTTL).Err(); err != nil {
+		return nil, err
+	}
+	return &dto.PasswordChallenge{
+		Token:     token,
+		Reason:    challenge.Reason,
+		ExpiresIn: int64(b.sso.Password.ChangeTTL.Seconds()),
+	}, nil
+}
+
+// checkPassword 按密码策略校验新密码，personal 为不能出现在密码中的用户名与邮箱。
+func (b *base) checkPassword(password string, personal ...string) error {
+	if err := b.policy.Check(password, personal...); err != nil {
+		return result.ErrParameter.WithMessage(err.Error())
+	}
+	return nil
+}
+
+// setPassword 为已锁定的用户设置新密码，应在事务中调用，并同步更新 user 的密码相关字段。
+//
+// 新密码需要满足密码策略，且不能与当前密码及最近使用过的密码相同；被替换的密码记入历史，只保留策略要求的数量。
+// requireChange 为 true 时用户下次使用密码登录需要先修改密码。
+func (b *base) setPassword(tx *gorm.DB, user *entity.User, password string, requireChange bool) error {
+	personal := []string{user.Username}
+	if user.Email != nil {
+		personal = append(personal, *user.Email)
+	}
+	if err := b.checkPassword(password, personal...); err != nil {
+		return err
+	}
+	keep := b.policy.History()
+	if err := b.checkPasswordHistory(tx, user, password, keep); err != nil {
+		return err
+	}
+	passwordHash, err := b.policy.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	previous := user.PasswordHash
+	now := time.Now()
+	err = tx.Model(user).Updates(map[string]any{
+		"password_hash":            passwordHash,
+		"password_changed_at":      now,
+		"password_change_required": requireChange,
+	}).Error
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = &passwordHash
+	user.PasswordChangedAt = &now
+	user.PasswordChangeRequired = requireChange
+
+	if keep > 0 && previous != nil && *previous != "" {
+		if err := tx.Create(&entity.UserPasswordHistory{UserUUID: user.UUID, PasswordHash: *previous}).Error; err != nil {
+			return err
+		}
+	}
+	var stale []uuid.UUID
+	err = tx.Model(&entity.UserPasswordHistory{}).
+		Where("user_uuid = ?", user.UUID).
+		Order("created_at DESC").Order("uuid DESC").
+		Offset(keep).
+		Pluck("uuid", &stale).Error
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	return tx.Where("uuid IN ?", stale).Delete(&entity.UserPasswordHistory{}).Error
+}
+
+// checkPasswordHistory 校验新密码与用户的当前密码及最近 keep 个历史密码均不相同，keep 为 0 时不检查。
+func (b *base) checkPasswordHistory(tx *gorm.DB, user *entity.User, password string, keep int) error {
+	if keep == 0 {
+		return nil
+	}
+	hashes := make([]string, 0, keep+1)
+	if user.HasPassword() {
+		hashes = append(hashes, *user.PasswordHash)
+	}
+	var histories []string
+	err := tx.Model(&entity.UserPasswordHistory{}).
+		Where("user_uuid = ?", user.UUID).
+		Order("created_at DESC").Order("uuid DESC").
+		Limit(keep).
+		Pluck("password_hash", &histories).Error
+	if err != nil {
+		return err
+	}
+	for _, hash := range append(hashes, histories...) {
+		if match, _ := b.policy.Verify(hash, password); match {
+			return errPasswordReused
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// earliestBirthday 是允许填写的最早生日。
+var earliestBirthday = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ProfileLogic 负责当前登录用户查看与修改个人资料，以及上传与移除头像。
+//
+// 头像被裁剪缩放后保存到文件存储，资料中记录其公开访问地址；更换或移除头像时删除原来上传的文件。
+type ProfileLogic struct {
+	base
+}
+
+// NewProfile 创建一个新的 ProfileLogic 实例。
+func NewProfile(c *gin.Context) *ProfileLogic {
+	return &ProfileLogic{base: newBase(c)}
+}
+
+// Get 查看当前登录用户的个人资料，尚未填写过资料时返回空白资料。
+func (p *ProfileLogic) Get(c *gin.Context) (*dto.UserProfile, error) {
+	var profile entity.UserProfile
+	err := p.db.Where(&entity.UserProfile{UserUUID: currentUser(c).UUID}).First(&profile).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return profileDTO(&profile), nil
+}
+
+// Update 修改当前登录用户的个人资料，尚未填写过资料时一并创建。
+func (p *ProfileLogic) Update(c *gin.Context, req *request.ProfileUpdate) (*dto.UserProfile, error) {
+	var birthday *time.Time
+	if req.Birthday != nil && strings.TrimSpace(*req.Birthday) != "" {
+		parsed, err := time.Parse(time.DateOnly, strings.TrimSpace(*req.Birthday))
+		if err != nil {
+			return nil, result.ErrParameter.WithMessage("生日的格式应为 YYYY-MM-DD")
+		}
+		if parsed.Before(earliestBirthday) || parsed.After(time.Now()) {
+			return nil, result.ErrParameter.WithMessage("生日不在有效范围内")
+		}
+		birthday = &parsed
+	}
+
+	var profile *entity.UserProfile
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if profile, err = lockProfile(tx, currentUser(c).UUID); err != nil {
+			return err
+		}
+
+		updates := make(map[string]any)
+		setOptional := func(column string, current *string, value *string) {
+			if value == nil {
+				return
+			}
+			next := utility.NilIfBlank(strings.TrimSpace(*value))
+			if (current == nil) != (next == nil) || (current != nil && *current != *next) {
+				updates[column] = next
+			}
+		}
+		setOptional("nickname", profile.Nickname, req.Nickname)
+		setOptional("country", profile.Country, req.Country)
+		setOptional("province", profile.Province, req.Province)
+		setOptional("city", profile.City, req.City)
+		setOptional("bio", profile.Bio, req.Bio)
+		if req.Gender != nil && *req.Gender != profile.Gender {
+			updates["gender"] = *req.Gender
+		}
+		if req.Birthday != nil && !equalDate(profile.Birthday, birthday) {
+			updates["birthday"] = birthday
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+
+		fields := make([]string, 0, len(updates))
+		for column := range updates {
+			fields = append(fields, column)
+		}
+		slices.Sort(fields)
+		if err := tx.Model(profile).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.First(profile, "uuid = ?", profile.UUID).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditProfileUpdate, constants.ResourceUserProfile, &profile.UUID, map[string]any{
+			"fields": fields,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profileDTO(profile), nil
+}
+
+// UploadAvatar 将上传的图片裁剪为正方形并缩放后保存为当前登录用户的头像，原来上传的头像文件随之删除。
+func (p *ProfileLogic) UploadAvatar(c *gin.Context, header *multipart.FileHeader) (*dto.UserProfile, error) {
+	limit := p.sso.Profile.AvatarMaxBytes
+	tooLarge := result.ErrParameter.WithMessage(fmt.Sprintf("头像文件不能超过 %d KiB", limit>>10))
+	if header.Size > limit {
+		return nil, tooLarge
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, tooLarge
+	}
+
+	avatar, contentType, err := utility.SquareThumbnail(data, p.sso.Profile.AvatarSize, p.sso.Profile.AvatarMaxPixels)
+	if errors.Is(err, utility.ErrImageFormat) {
+		return nil, result.ErrParameter.WithMessage("头像只支持 JPEG、PNG、GIF 与 WebP 格式的图片")
+	}
+	if errors.Is(err, utility.ErrImageTooLarge) {
+		return nil, result.ErrParameter.WithMessage("头像图片的尺寸过大")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID := currentUser(c).UUID
+	extension := ".png"
+	if contentType == "image/jpeg" {
+		extension = ".jpg"
+	}
+	key := fmt.Sprintf("avatars/%s/%s%s", userUUID, utility.RandomToken(12), extension)
+	url, err := p.storage.Put(c, key, avatar, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, previous, err := p.replaceAvatar(c, userUUID, &url, constants.AuditAvatarUpdate)
+	if err != nil {
+		p.removeStored(c, &url)
+		return nil, err
+	}
+	p.removeStored(c, previous)
+	return profileDTO(profile), nil
+}
+
+// RemoveAvatar 移除当前登录用户的头像，原来上传的头像文件随之删除。
+func (p *ProfileLogic) RemoveAvatar(c *gin.Context) (*dto.UserProfile, error) {
+	profile, previous, err := p.replaceAvatar(c, currentUser(c).UUID, nil, constants.AuditAvatarRemove)
+	if err != nil {
+		return nil, err
+	}
+	p.removeStored(c, previous)
+	return profileDTO(profile), nil
+}
+
+// replaceAvatar 将用户资料中的头像地址替换为 avatar，返回替换后的资料与原来的头像地址。
+func (p *ProfileLogic) replaceAvatar(c *gin.Context, userUUID uuid.UUID, avatar *string, action string) (*entity.UserProfile, *string, error) {
+	var profile *entity.UserProfile
+	var previous *string
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if profile, err = lockProfile(tx, userUUID); err != nil {
+			return err
+		}
+		previous = profile.Avatar
+		if err := tx.Model(profile).Update("avatar", avatar).Error; err != nil {
+			return err
+		}
+		profile.Avatar = avatar
+		return writeAudit(c, tx, action, constants.ResourceUserProfile, &profile.UUID, map[string]any{
+			"avatar": avatar,
+		})
+	})
+	return profile, previous, err
+}
+
+// removeStored 删除文件存储中的头像文件，头像地址不属于文件存储（如第三方平台的头像）时忽略；删除失败只记录日志。
+func (p *ProfileLogic) removeStored(c *gin.Context, url *string) {
+	if url == nil {
+		return
+	}
+	key, ok := p.storage.Key(*url)
+	if !ok {
+		return
+	}
+	if err := p.storage.Delete(c, key); err != nil {
+		p.log.Named("PROFILE").Warn("删除头像文件失败", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// lockProfile 锁定并返回用户的资料，尚未填写过资料时创建一份空白资料。
+func lockProfile(tx *gorm.DB, userUUID uuid.UUID) (*entity.UserProfile, error) {
+	var profile entity.UserProfile
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(&entity.UserProfile{UserUUID: userUUID}).First(&profile).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		profile = entity.UserProfile{UserUUID: userUUID}
+		err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&profile).Error
+		if err == nil {
+			err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(&entity.UserProfile{UserUUID: userUUID}).First(&profile).Error
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// equalDate 比较两个可为空的日期是否相同。
+func equalDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Format(time.DateOnly) == b.Format(time.DateOnly)
+}
+
+// profileDTO 将用户资料实体转换为展示信息。
+func profileDTO(profile *entity.UserProfile) *dto.UserProfile {
+	return &dto.UserProfile{
+		Nickname: profile.Nickname,
+		Avatar:   profile.Avatar,
+		Gender:   profile.Gender,
+		Birthday: profile.Birthday,
+		Country:  profile.Country,
+		Province: profile.Province,
+		City:     profile.City,
+		Bio:      profile.Bio,
+	}
+}
@@ -0,0 +1,305 @@
+package logic
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// providerCodePattern 限定提供商代码的字符集，代码会作为路径参数出现在登录地址中。
+var providerCodePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// ProviderLogic 负责第三方提供商的配置管理与登录页展示。
+type ProviderLogic struct {
+	base
+}
+
+// NewProvider 创建一个新的 ProviderLogic 实例。
+func NewProvider(c *gin.Context) *ProviderLogic {
+	return &ProviderLogic{base: newBase(c)}
+}
+
+// PublicList 列出当前租户中已启用的第三方登录方式，按 SortOrder 升序排列，供登录页展示。
+func (p *ProviderLogic) PublicList() ([]*dto.PublicProvider, error) {
+	var providers []*entity.ThirdPartyProvider
+	err := p.inTenant(p.db).Where(&entity.ThirdPartyProvider{IsEnabled: true}).Order("sort_order, name").Find(&providers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*dto.PublicProvider, 0, len(providers))
+	for _, provider := range providers {
+		list = append(list, &dto.PublicProvider{Name: provider.Name, Code: provider.Code, Type: provider.Type})
+	}
+	return list, nil
+}
+
+// List 列出当前租户的全部第三方提供商配置，按 SortOrder 升序排列。
+func (p *ProviderLogic) List() ([]*dto.Provider, error) {
+	var providers []*entity.ThirdPartyProvider
+	if err := p.inTenant(p.db).Order("sort_order, name").Find(&providers).Error; err != nil {
+		return nil, err
+	}
+
+	list := make([]*dto.Provider, 0, len(providers))
+	for _, provider := range providers {
+		list = append(list, providerDTO(provider))
+	}
+	return list, nil
+}
+
+// Get 获取指定的第三方提供商配置。
+func (p *ProviderLogic) Get(providerUUID uuid.UUID) (*dto.Provider, error) {
+	provider, err := findProvider(p.inTenant(p.db), providerUUID)
+	if err != nil {
+		return nil, err
+	}
+	return providerDTO(provider), nil
+}
+
+// Create 创建一个通用驱动的第三方提供商并写入审计日志。
+func (p *ProviderLogic) Create(c *gin.Context, req *request.ProviderCreate) (*dto.Provider, error) {
+	if !providerCodePattern.MatchString(req.Code) {
+		return nil, result.ErrParameter.WithMessage("提供商代码只能包含小写字母、数字、下划线与短横线")
+	}
+	claimMapping, err := marshalClaimMapping(req.ClaimMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Issuer != nil {
+		req.Issuer = utility.NilIfBlank(*req.Issuer)
+	}
+
+	provider := &entity.ThirdPartyProvider{
+		TenantUUID:   p.tenant.UUID,
+		Name:         req.Name,
+		Code:         req.Code,
+		Type:         req.Type,
+		Issuer:       req.Issuer,
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+		AuthURL:      req.AuthURL,
+		TokenURL:     req.TokenURL,
+		UserInfoURL:  req.UserInfoURL,
+		Scope:        req.Scope,
+		RedirectURL:  req.RedirectURL,
+		ClaimMapping: claimMapping,
+		IsEnabled:    req.IsEnabled == nil || *req.IsEnabled,
+		SortOrder:    req.SortOrder,
+	}
+	if err := validateProvider(provider); err != nil {
+		return nil, err
+	}
+
+	err = p.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entity.ThirdPartyProvider{}).Where(&entity.ThirdPartyProvider{TenantUUID: provider.TenantUUID, Code: provider.Code}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return result.ErrConflict.WithMessage("提供商代码已存在")
+		}
+
+		// IsEnabled 带有数据库默认值，需要显式写入全部字段，否则 false 会被默认值覆盖
+		if err := tx.Select("*").Create(provider).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditProviderCreate, constants.ResourceProvider, &provider.UUID, map[string]any{
+			"code": provider.Code,
+			"type": provider.Type,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return providerDTO(provider), nil
+}
+
+// Update 修改第三方提供商配置，只更新请求中传入的字段，并在审计日志中记录被修改的字段名。
+func (p *ProviderLogic) Update(c *gin.Context, providerUUID uuid.UUID, req *request.ProviderUpdate) (*dto.Provider, error) {
+	var provider *entity.ThirdPartyProvider
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		provider, err = findProvider(p.inTenant(tx.Clauses(clause.Locking{Strength: "UPDATE"})), providerUUID)
+		if err != nil {
+			return err
+		}
+
+		changed := make([]string, 0)
+		setString := func(field string, target *string, value *string) {
+			if value != nil && *target != *value {
+				*target = *value
+				changed = append(changed, field)
+			}
+		}
+		setString("name", &provider.Name, req.Name)
+		setString("client_id", &provider.ClientID, req.ClientID)
+		setString("auth_url", &provider.AuthURL, req.AuthURL)
+		setString("token_url", &provider.TokenURL, req.TokenURL)
+		setString("user_info_url", &provider.UserInfoURL, req.UserInfoURL)
+		setString("scope", &provider.Scope, req.Scope)
+		setString("redirect_url", &provider.RedirectURL, req.RedirectURL)
+
+		if req.Issuer != nil {
+			issuer := utility.NilIfBlank(*req.Issuer)
+			if (issuer == nil) != (provider.Issuer == nil) || (issuer != nil && *issuer != *provider.Issuer) {
+				provider.Issuer = issuer
+				changed = append(changed, "issuer")
+			}
+		}
+		if req.ClientSecret != nil {
+			provider.ClientSecret = *req.ClientSecret
+			changed = append(changed, "client_secret")
+		}
+		if req.ClaimMapping != nil {
+			claimMapping, err := marshalClaimMapping(req.ClaimMapping)
+			if err != nil {
+				return err
+			}
+			provider.ClaimMapping = claimMapping
+			changed = append(changed, "claim_mapping")
+		}
+		if req.IsEnabled != nil && provider.IsEnabled != *req.IsEnabled {
+			provider.IsEnabled = *req.IsEnabled
+			changed = append(changed, "is_enabled")
+		}
+		if req.SortOrder != nil && provider.SortOrder != *req.SortOrder {
+			provider.SortOrder = *req.SortOrder
+			changed = append(changed, "sort_order")
+		}
+
+		if len(changed) == 0 {
+			return nil
+		}
+		if err := validateProvider(provider); err != nil {
+			return err
+		}
+		if err := tx.Save(provider).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditProviderUpdate, constants.ResourceProvider, &provider.UUID, map[string]any{
+			"code":   provider.Code,
+			"fields": changed,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return providerDTO(provider), nil
+}
+
+// Delete 删除一个通用驱动的第三方提供商并写入审计日志。
+//
+// 内置的专用驱动提供商只能停用不能删除；仍有用户绑定的提供商也不能删除，以免用户失去登录方式。
+func (p *ProviderLogic) Delete(c *gin.Context, providerUUID uuid.UUID) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		provider, err := findProvider(p.inTenant(tx.Clauses(clause.Locking{Strength: "UPDATE"})), providerUUID)
+		if err != nil {
+			return err
+		}
+		if !isGenericProvider(provider.Type) {
+			return result.ErrForbidden.WithMessage("内置的第三方提供商只能停用，不能删除")
+		}
+
+		var count int64
+		if err := tx.Model(&entity.UserExternalIdentity{}).Where(&entity.UserExternalIdentity{ProviderUUID: provider.UUID}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return result.ErrConflict.WithMessage("仍有用户绑定该提供商，请先停用")
+		}
+
+		if err := tx.Delete(provider).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditProviderDelete, constants.ResourceProvider, &provider.UUID, map[string]any{
+			"code": provider.Code,
+		})
+	})
+}
+
+// findProvider 根据 UUID 查找第三方提供商。
+func findProvider(db *gorm.DB, providerUUID uuid.UUID) (*entity.ThirdPartyProvider, error) {
+	var provider entity.ThirdPartyProvider
+	err := db.First(&provider, "uuid = ?", providerUUID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, result.ErrNotFound.WithMessage("第三方提供商不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
+
+// isGenericProvider 判断提供商是否使用通用驱动。
+func isGenericProvider(providerType string) bool {
+	return providerType == constants.ProviderTypeOIDC || providerType == constants.ProviderTypeOAuth2
+}
+
+// validateProvider 校验通用驱动提供商的端点配置是否完整。
+//
+// OIDC 提供商必须配置 Issuer，端点可由服务发现获得；OAuth2 提供商必须配置授权、令牌与用户信息端点。
+func validateProvider(provider *entity.ThirdPartyProvider) error {
+	switch provider.Type {
+	case constants.ProviderTypeOIDC:
+		if provider.Issuer == nil {
+			return result.ErrParameter.WithMessage("OIDC 提供商必须配置 Issuer")
+		}
+	case constants.ProviderTypeOAuth2:
+		if provider.AuthURL == "" || provider.TokenURL == "" || provider.UserInfoURL == "" {
+			return result.ErrParameter.WithMessage("OAuth2 提供商必须配置授权、令牌与用户信息地址")
+		}
+	}
+	return nil
+}
+
+// marshalClaimMapping 将声明映射序列化为 JSON 字符串，未传入或全部为空时返回 nil。
+func marshalClaimMapping(mapping *request.ClaimMapping) (*string, error) {
+	if mapping == nil || *mapping == (request.ClaimMapping{}) {
+		return nil, nil
+	}
+	value, err := jsoniter.MarshalToString(mapping)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// providerDTO 将第三方提供商实体转换为管理后台的展示信息，客户端密钥不会返回。
+func providerDTO(provider *entity.ThirdPartyProvider) *dto.Provider {
+	var claimMapping map[string]string
+	if provider.ClaimMapping != nil {
+		_ = jsoniter.UnmarshalFromString(*provider.ClaimMapping, &claimMapping)
+	}
+	return &dto.Provider{
+		UUID:            provider.UUID,
+		Name:            provider.Name,
+		Code:            provider.Code,
+		Type:            provider.Type,
+		Issuer:          provider.Issuer,
+		ClientID:        provider.ClientID,
+		HasClientSecret: provider.ClientSecret != "",
+		AuthURL:         provider.AuthURL,
+		TokenURL:        provider.TokenURL,
+		UserInfoURL:     provider.UserInfoURL,
+		Scope:           provider.Scope,
+		RedirectURL:     provider.RedirectURL,
+		ClaimMapping:    claimMapping,
+		IsEnabled:       provider.IsEnabled,
+		SortOrder:       provider.SortOrder,
+		CreatedAt:       provider.CreatedAt,
+		UpdatedAt:       provider.UpdatedAt,
+	}
+}
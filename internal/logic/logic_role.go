@@ -0,0 +1,700 @@
+package logic
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// roleNamePattern 限定自定义全局角色名称的字符集，与内置角色的命名保持一致。
+var roleNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// applicationRoleNamePattern 限定应用角色名称的字符集，如 "billing:viewer"；只使用小写字母，不会与全局角色混淆。
+var applicationRoleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_.:-]*$`)
+
+// builtinRoles 是系统内置的角色，不能删除或改名；超级管理员角色的权限同样不能修改。
+var builtinRoles = []string{constants.RoleSuperAdmin, constants.RoleAdmin, constants.RoleUser}
+
+// RoleLogic 负责用户角色与权限的查询与判定，以及管理后台的角色管理与用户角色分配。
+//
+// 除超级管理员外，操作者只能授予自己持有的权限，也只能分配权限不超出自己的角色，避免借助角色管理提升权限。
+// 角色可以属于某个接入应用，应用角色不授予 SSO 权限，只作为声明返回给该应用。
+type RoleLogic struct {
+	base
+}
+
+// NewRole 创建一个新的 RoleLogic 实例。
+func NewRole(c *gin.Context) *RoleLogic {
+	return &RoleLogic{base: newBase(c)}
+}
+
+// HasAnyRole 判断用户是否持有任意一个指定名称的有效全局角色，包括通过分组持有的角色；已停用或已过期的角色关联不计入。
+func (r *RoleLogic) HasAnyRole(userUUID uuid.UUID, names ...string) (bool, error) {
+	roles, _, _, err := activeGrants(r.db, userUUID)
+	if err != nil {
+		return false, err
+	}
+	for _, name := range names {
+		if slices.Contains(roles, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListPermissions 列出系统中的全部权限，按分类与权限代码排序，用于在管理后台为角色分配权限。
+func (r *RoleLogic) ListPermissions() ([]*entity.Permission, error) {
+	var permissions []*entity.Permission
+	if err := r.db.Order("category").Order("code").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// activeGrants 查询用户持有的有效全局角色名称、这些角色被授予的权限代码，以及直接分配的有效角色中最早的过期时间（均不过期时为 nil）。
+//
+// 有效角色为直接分配的角色与所属分组（含上级分组）的角色之和；应用角色只由所属应用解释，不计入 SSO 的角色与权限。
+func activeGrants(tx *gorm.DB, userUUID uuid.UUID) ([]string, []string, *time.Time, error) {
+	roleUUIDs, expiresAt, err := effectiveRoleUUIDs(tx, userUUID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	roles := make([]string, 0)
+	permissions := make([]string, 0)
+	if len(roleUUIDs) == 0 {
+		return roles, permissions, expiresAt, nil
+	}
+
+	global := tx.Model(&entity.Role{}).Select("uuid").Where("application_uuid IS NULL AND uuid IN ?", roleUUIDs)
+	if err := tx.Model(&entity.Role{}).Where("application_uuid IS NULL AND uuid IN ?", roleUUIDs).Order("name").Pluck("name", &roles).Error; err != nil {
+		return nil, nil, nil, err
+	}
+	err = tx.Model(&entity.Permission{}).
+		Where("uuid IN (?)", tx.Model(&entity.RolePermission{}).Select("permission_uuid").Where("role_uuid IN (?)", global)).
+		Order("code").
+		Pluck("code", &permissions).Error
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return roles, permissions, expiresAt, nil
+}
+
+// effectiveRoleUUIDs 查询用户的有效角色，包括直接分配且未停用、未过期的角色与所属分组（含上级分组）的角色，
+// 同时返回直接分配的角色中最早的过期时间，分组的角色不会过期。
+func effectiveRoleUUIDs(tx *gorm.DB, userUUID uuid.UUID) ([]uuid.UUID, *time.Time, error) {
+	var userRoles []entity.UserRole
+	err := tx.Where("user_uuid = ? AND is_active = ?", userUUID, true).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Find(&userRoles).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	roleUUIDs := make([]uuid.UUID, 0, len(userRoles))
+	var expiresAt *time.Time
+	for _, userRole := range userRoles {
+		roleUUIDs = append(roleUUIDs, userRole.RoleUUID)
+		if userRole.ExpiresAt != nil && (expiresAt == nil || userRole.ExpiresAt.Before(*expiresAt)) {
+			expiresAt = userRole.ExpiresAt
+		}
+	}
+
+	groups, err := userGroups(tx, userUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(groups) > 0 {
+		groupUUIDs := make([]uuid.UUID, 0, len(groups))
+		for _, group := range groups {
+			groupUUIDs = append(groupUUIDs, group.UUID)
+		}
+		var groupRoleUUIDs []uuid.UUID
+		if err := tx.Model(&entity.GroupRole{}).Where("group_uuid IN ?", groupUUIDs).Pluck("role_uuid", &groupRoleUUIDs).Error; err != nil {
+			return nil, nil, err
+		}
+		roleUUIDs = append(roleUUIDs, groupRoleUUIDs...)
+	}
+	return uniqueUUIDs(roleUUIDs), expiresAt, nil
+}
+
+// HasPermission 判断持有指定角色与权限的用户是否可以执行需要 permission 的操作，超级管理员不受权限限制。
+func HasPermission(roles, permissions []string, permission string) bool {
+	return slices.Contains(roles, constants.RoleSuperAdmin) || slices.Contains(permissions, permission)
+}
+
+// applicationRoles 查询用户持有的属于指定接入应用的有效角色名称，包括通过分组持有的角色，按名称排序。
+func applicationRoles(tx *gorm.DB, userUUID, applicationUUID uuid.UUID) ([]string, error) {
+	roleUUIDs, _, err := effectiveRoleUUIDs(tx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]string, 0)
+	if len(roleUUIDs) == 0 {
+		return roles, nil
+	}
+	err = tx.Model(&entity.Role{}).
+		Where("application_uuid = ? AND uuid IN ?", applicationUUID, roleUUIDs).
+		Order("name").
+		Pluck("name", &roles).Error
+	return roles, err
+}
+
+// List 列出当前租户的角色与内置角色及其权限与成员数量，内置角色排在前面；传入 applicationUUID 时只列出属于该接入应用的角色。
+func (r *RoleLogic) List(applicationUUID *uuid.UUID) ([]*dto.Role, error) {
+	query := r.inTenantRoles(r.db).Order("created_at").Order("name")
+	if applicationUUID != nil {
+		query = query.Where("application_uuid = ?", *applicationUUID)
+	}
+	var roles []*entity.Role
+	if err := query.Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	list, err := roleDTOs(r.db, r.tenant.UUID, roles)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(list, func(a, b *dto.Role) int {
+		switch {
+		case a.IsBuiltin == b.IsBuiltin:
+			return 0
+		case a.IsBuiltin:
+			return -1
+		default:
+			return 1
+		}
+	})
+	return list, nil
+}
+
+// Get 获取指定的角色及其权限与成员数量。
+func (r *RoleLogic) Get(roleUUID uuid.UUID) (*dto.Role, error) {
+	role, err := findRole(r.inTenantRoles(r.db), roleUUID)
+	if err != nil {
+		return nil, err
+	}
+	return roleDTO(r.db, r.tenant.UUID, role)
+}
+
+// Create 创建一个自定义角色并授予指定的权限，写入审计日志。
+//
+// 角色归属当前租户，全局角色不能与内置角色同名；传入 ApplicationUUID 时创建属于该接入应用的角色，应用角色不能授予 SSO 权限。
+func (r *RoleLogic) Create(c *gin.Context, req *request.RoleCreate) (*dto.Role, error) {
+	if req.ApplicationUUID != nil {
+		if !applicationRoleNamePattern.MatchString(req.Name) {
+			return nil, result.ErrParameter.WithMessage("应用角色名称只能包含小写字母、数字与 _ . : -，且必须以字母开头")
+		}
+		if len(req.Permissions) > 0 {
+			return nil, result.ErrParameter.WithMessage("应用角色不能授予 SSO 权限")
+		}
+	} else if !roleNamePattern.MatchString(req.Name) {
+		return nil, result.ErrParameter.WithMessage("角色名称只能包含大写字母、数字与下划线，且必须以字母开头")
+	}
+	if err := checkGrantable(c, req.Permissions); err != nil {
+		return nil, err
+	}
+
+	role := &entity.Role{TenantUUID: &r.tenant.UUID, ApplicationUUID: req.ApplicationUUID, Name: req.Name, DisplayName: req.DisplayName}
+	if req.Description != nil {
+		role.Description = utility.NilIfBlank(*req.Description)
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&entity.Role{}).Where("name = ?", role.Name)
+		if role.ApplicationUUID != nil {
+			var count int64
+			if err := r.inTenant(tx.Model(&entity.Application{})).Where("uuid = ?", *role.ApplicationUUID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return result.ErrNotFound.WithMessage("接入应用不存在")
+			}
+			query = query.Where("application_uuid = ?", *role.ApplicationUUID)
+		} else {
+			query = r.inTenantRoles(query.Where("application_uuid IS NULL"))
+		}
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return result.ErrConflict.WithMessage("角色名称已存在")
+		}
+		if err := tx.Create(role).Error; err != nil {
+			return err
+		}
+		codes, err := replaceRolePermissions(tx, role.UUID, req.Permissions)
+		if err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditRoleCreate, constants.ResourceRole, &role.UUID, map[string]any{
+			"name":             role.Name,
+			"application_uuid": role.ApplicationUUID,
+			"permissions":      codes,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return roleDTO(r.db, r.tenant.UUID, role)
+}
+
+// Update 修改角色的显示名称、描述与权限，只更新请求中传入的字段，并在审计日志中记录被修改的字段名。
+//
+// 超级管理员角色不受权限限制，不能修改；内置角色由全部租户共用，只能在默认租户中修改。
+// 角色的权限发生变化时，清除全部成员的认证缓存使其立即生效。
+func (r *RoleLogic) Update(c *gin.Context, roleUUID uuid.UUID, req *request.RoleUpdate) (*dto.Role, error) {
+	var role *entity.Role
+	var members []uuid.UUID
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		role, err = findRole(r.inTenantRoles(tx.Clauses(clause.Locking{Strength: "UPDATE"})), roleUUID)
+		if err != nil {
+			return err
+		}
+		if role.Name == constants.RoleSuperAdmin {
+			return result.ErrForbidden.WithMessage("超级管理员角色不能修改")
+		}
+		if role.TenantUUID == nil && r.tenant.Code != constants.TenantDefault {
+			return result.ErrForbidden.WithMessage("内置角色只能在默认租户中修改")
+		}
+
+		changed := make([]string, 0)
+		if req.DisplayName != nil && role.DisplayName != *req.DisplayName {
+			role.DisplayName = *req.DisplayName
+			changed = append(changed, "display_name")
+		}
+		if req.Description != nil {
+			description := utility.NilIfBlank(*req.Description)
+			if (description == nil) != (role.Description == nil) || (description != nil && *description != *role.Description) {
+				role.Description = description
+				changed = append(changed, "description")
+			}
+		}
+		if len(changed) > 0 {
+			if err := tx.Save(role).Error; err != nil {
+				return err
+			}
+		}
+
+		detail := map[string]any{"name": role.Name}
+		if req.Permissions != nil {
+			if role.ApplicationUUID != nil && len(*req.Permissions) > 0 {
+				return result.ErrParameter.WithMessage("应用角色不能授予 SSO 权限")
+			}
+			current, err := rolePermissionCodes(tx, role.UUID)
+			if err != nil {
+				return err
+			}
+			added := make([]string, 0)
+			for _, code := range *req.Permissions {
+				if !slices.Contains(current, code) && !slices.Contains(added, code) {
+					added = append(added, code)
+				}
+			}
+			if err := checkGrantable(c, added); err != nil {
+				return err
+			}
+			codes, err := replaceRolePermissions(tx, role.UUID, *req.Permissions)
+			if err != nil {
+				return err
+			}
+			if !slices.Equal(codes, current) {
+				changed = append(changed, "permissions")
+				detail["permissions"] = codes
+				if members, err = roleMembers(tx, role.UUID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(changed) == 0 {
+			return nil
+		}
+		detail["fields"] = changed
+		return writeAudit(c, tx, constants.AuditRoleUpdate, constants.ResourceRole, &role.UUID, detail)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := r.invalidateAuths(c, members); err != nil {
+		return nil, err
+	}
+	return roleDTO(r.db, r.tenant.UUID, role)
+}
+
+// Delete 删除一个自定义角色，角色的权限、用户与分组的分配以及引用该角色的访问规则一并删除，并清除原成员的认证缓存。
+func (r *RoleLogic) Delete(c *gin.Context, roleUUID uuid.UUID) error {
+	var members []uuid.UUID
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		role, err := findRole(r.inTenantRoles(tx.Clauses(clause.Locking{Strength: "UPDATE"})), roleUUID)
+		if err != nil {
+			return err
+		}
+		if role.ApplicationUUID == nil && slices.Contains(builtinRoles, role.Name) {
+			return result.ErrForbidden.WithMessage("内置角色不能删除")
+		}
+		if err := checkAssignable(c, tx, role); err != nil {
+			return err
+		}
+		if members, err = roleMembers(tx, role.UUID); err != nil {
+			return err
+		}
+
+		err = tx.Where(&entity.ApplicationAccess{SubjectType: constants.AccessSubjectRole, SubjectUUID: role.UUID}).
+			Delete(&entity.ApplicationAccess{}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(role).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditRoleDelete, constants.ResourceRole, &role.UUID, map[string]any{
+			"name":    role.Name,
+			"members": len(members),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return r.invalidateAuths(c, members)
+}
+
+// Members 列出当前租户中直接持有指定角色且未过期的用户，按分配时间倒序排列；通过分组持有角色的用户不在其中。
+func (r *RoleLogic) Members(roleUUID uuid.UUID) ([]*dto.RoleMember, error) {
+	if _, err := findRole(r.inTenantRoles(r.db), roleUUID); err != nil {
+		return nil, err
+	}
+	var userRoles []entity.UserRole
+	err := r.db.Preload("User").
+		Where("role_uuid = ? AND is_active = ?", roleUUID, true).
+		Where("user_uuid IN (?)", tenantUsers(r.db, r.tenant.UUID)).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("assigned_at DESC").
+		Find(&userRoles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*dto.RoleMember, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		if userRole.User == nil {
+			continue
+		}
+		members = append(members, &dto.RoleMember{
+			UserUUID:   userRole.UserUUID,
+			Username:   userRole.User.Username,
+			AssignedBy: userRole.AssignedBy,
+			AssignedAt: userRole.AssignedAt,
+			ExpiresAt:  userRole.ExpiresAt,
+		})
+	}
+	return members, nil
+}
+
+// Assign 为用户分配角色，过期时间为空表示永不过期；用户已持有或曾经持有该角色时，以本次请求重新分配。
+//
+// 只有超级管理员可以分配超级管理员角色。
+func (r *RoleLogic) Assign(c *gin.Context, roleUUID uuid.UUID, req *request.RoleAssign) error {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return result.ErrParameter.WithMessage("过期时间必须晚于当前时间")
+	}
+	operator := currentUser(c)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		role, err := findRole(r.inTenantRoles(tx), roleUUID)
+		if err != nil {
+			return err
+		}
+		if err := checkAssignable(c, tx, role); err != nil {
+			return err
+		}
+		_, err = findUser(r.inTenant(tx), req.UserUUID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return result.ErrNotFound.WithMessage("用户不存在")
+		}
+		if err != nil {
+			return err
+		}
+
+		var userRole entity.UserRole
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(&entity.UserRole{UserUUID: req.UserUUID, RoleUUID: role.UUID}).
+			Order("created_at DESC").
+			First(&userRole).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		userRole.UserUUID = req.UserUUID
+		userRole.RoleUUID = role.UUID
+		userRole.AssignedBy = &operator.UUID
+		userRole.AssignedAt = time.Now()
+		userRole.ExpiresAt = req.ExpiresAt
+		userRole.IsActive = true
+		if err := tx.Save(&userRole).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditRoleAssign, constants.ResourceRole, &role.UUID, map[string]any{
+			"name":       role.Name,
+			"user_uuid":  req.UserUUID,
+			"expires_at": req.ExpiresAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return r.invalidateAuth(c, req.UserUUID)
+}
+
+// Unassign 收回用户持有的角色。
+//
+// 只有超级管理员可以收回超级管理员角色，且每个租户中至少保留一位有效的超级管理员。
+func (r *RoleLogic) Unassign(c *gin.Context, roleUUID, userUUID uuid.UUID) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		role, err := findRole(r.inTenantRoles(tx.Clauses(clause.Locking{Strength: "UPDATE"})), roleUUID)
+		if err != nil {
+			return err
+		}
+		if err := checkAssignable(c, tx, role); err != nil {
+			return err
+		}
+		if role.Name == constants.RoleSuperAdmin {
+			if err := checkOtherSuperAdmin(tx, r.tenant.UUID, userUUID); err != nil {
+				return err
+			}
+		}
+
+		revoked := tx.Model(&entity.UserRole{}).
+			Where("role_uuid = ? AND user_uuid = ? AND is_active = ?", role.UUID, userUUID, true).
+			Where("user_uuid IN (?)", tenantUsers(tx, r.tenant.UUID)).
+			Update("is_active", false)
+		if revoked.Error != nil {
+			return revoked.Error
+		}
+		if revoked.RowsAffected == 0 {
+			return result.ErrNotFound.WithMessage("用户未持有该角色")
+		}
+		return writeAudit(c, tx, constants.AuditRoleUnassign, constants.ResourceRole, &role.UUID, map[string]any{
+			"name":      role.Name,
+			"user_uuid": userUUID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return r.invalidateAuth(c, userUUID)
+}
+
+// checkOtherSuperAdmin 校验租户中除指定用户外还有其他已启用且直接持有有效超级管理员角色的用户，
+// 在收回超级管理员角色、停用或删除用户前调用，保证每个租户至少保留一位超级管理员。
+func checkOtherSuperAdmin(tx *gorm.DB, tenantUUID, userUUID uuid.UUID) error {
+	var others int64
+	err := tx.Model(&entity.UserRole{}).
+		Where("role_uuid IN (?)", tx.Model(&entity.Role{}).Select("uuid").Where("name = ? AND tenant_uuid IS NULL AND application_uuid IS NULL", constants.RoleSuperAdmin)).
+		Where("user_uuid <> ? AND is_active = ?", userUUID, true).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Where("user_uuid IN (?)", tenantUsers(tx, tenantUUID).Where("is_active = ?", true)).
+		Count(&others).Error
+	if err != nil {
+		return err
+	}
+	if others == 0 {
+		return result.ErrConflict.WithMessage("租户中至少需要保留一位超级管理员")
+	}
+	return nil
+}
+
+// inTenantRoles 将角色查询限定在当前租户的角色与全部租户共用的内置角色内。
+func (b *base) inTenantRoles(db *gorm.DB) *gorm.DB {
+	return db.Where("(tenant_uuid = ? OR tenant_uuid IS NULL)", b.tenant.UUID)
+}
+
+// tenantUsers 返回查询租户全部用户UUID的子查询。
+func tenantUsers(tx *gorm.DB, tenantUUID uuid.UUID) *gorm.DB {
+	return tx.Model(&entity.User{}).Select("uuid").Where("tenant_uuid = ?", tenantUUID)
+}
+
+// findRole 根据 UUID 查找角色。
+func findRole(db *gorm.DB, roleUUID uuid.UUID) (*entity.Role, error) {
+	var role entity.Role
+	err := db.First(&role, "uuid = ?", roleUUID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, result.ErrNotFound.WithMessage("角色不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// checkGrantable 校验当前登录用户可以授予指定的权限：超级管理员不受限制，其他用户只能授予自己持有的权限。
+func checkGrantable(c *gin.Context, codes []string) error {
+	roles := c.GetStringSlice(constants.ContextUserRoles)
+	permissions := c.GetStringSlice(constants.ContextUserPermissions)
+	for _, code := range codes {
+		if !HasPermission(roles, permissions, code) {
+			return result.ErrForbidden.WithMessage("不能授予自己未持有的权限：" + code)
+		}
+	}
+	return nil
+}
+
+// checkAssignable 校验当前登录用户可以分配、收回或删除指定的角色。
+//
+// 超级管理员角色只能由超级管理员操作；其他角色的权限不能超出操作者持有的权限。
+func checkAssignable(c *gin.Context, tx *gorm.DB, role *entity.Role) error {
+	if role.Name == constants.RoleSuperAdmin && !slices.Contains(c.GetStringSlice(constants.ContextUserRoles), constants.RoleSuperAdmin) {
+		return result.ErrForbidden.WithMessage("只有超级管理员可以分配或收回超级管理员角色")
+	}
+	codes, err := rolePermissionCodes(tx, role.UUID)
+	if err != nil {
+		return err
+	}
+	return checkGrantable(c, codes)
+}
+
+// rolePermissionCodes 查询角色被授予的权限代码，按代码排序。
+func rolePermissionCodes(tx *gorm.DB, roleUUID uuid.UUID) ([]string, error) {
+	codes := make([]string, 0)
+	err := tx.Model(&entity.Permission{}).
+		Where("uuid IN (?)", tx.Model(&entity.RolePermission{}).Select("permission_uuid").Where("role_uuid = ?", roleUUID)).
+		Order("code").
+		Pluck("code", &codes).Error
+	return codes, err
+}
+
+// replaceRolePermissions 以指定的权限代码替换角色的全部权限，返回排序去重后的权限代码，存在未知的权限代码时返回参数错误。
+func replaceRolePermissions(tx *gorm.DB, roleUUID uuid.UUID, codes []string) ([]string, error) {
+	var permissions []*entity.Permission
+	if len(codes) > 0 {
+		if err := tx.Where("code IN ?", codes).Order("code").Find(&permissions).Error; err != nil {
+			return nil, err
+		}
+	}
+	resolved := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		resolved = append(resolved, permission.Code)
+	}
+	for _, code := range codes {
+		if !slices.Contains(resolved, code) {
+			return nil, result.ErrParameter.WithMessage("权限不存在：" + code)
+		}
+	}
+
+	if err := tx.Where(&entity.RolePermission{RoleUUID: roleUUID}).Delete(&entity.RolePermission{}).Error; err != nil {
+		return nil, err
+	}
+	if len(permissions) == 0 {
+		return resolved, nil
+	}
+	rolePermissions := make([]*entity.RolePermission, 0, len(permissions))
+	for _, permission := range permissions {
+		rolePermissions = append(rolePermissions, &entity.RolePermission{RoleUUID: roleUUID, PermissionUUID: permission.UUID})
+	}
+	if err := tx.Create(rolePermissions).Error; err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// roleMembers 查询持有角色的全部用户，包括已过期但尚未被停用的分配，以及通过分组（含下级分组）持有角色的用户，
+// 用于在角色变更后清除认证缓存。
+func roleMembers(tx *gorm.DB, roleUUID uuid.UUID) ([]uuid.UUID, error) {
+	var userUUIDs []uuid.UUID
+	err := tx.Model(&entity.UserRole{}).
+		Where("role_uuid = ? AND is_active = ?", roleUUID, true).
+		Distinct().
+		Pluck("user_uuid", &userUUIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var groupUUIDs []uuid.UUID
+	if err := tx.Model(&entity.GroupRole{}).Where("role_uuid = ?", roleUUID).Pluck("group_uuid", &groupUUIDs).Error; err != nil {
+		return nil, err
+	}
+	groupUsers, err := groupMembers(tx, groupUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	return uniqueUUIDs(append(userUUIDs, groupUsers...)), nil
+}
+
+// roleDTO 将角色实体转换为管理后台的展示信息，成员数量只统计指定租户的用户。
+func roleDTO(tx *gorm.DB, tenantUUID uuid.UUID, role *entity.Role) (*dto.Role, error) {
+	list, err := roleDTOs(tx, tenantUUID, []*entity.Role{role})
+	if err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// roleDTOs 批量查询角色的权限与成员数量，并转换为管理后台的展示信息；内置角色由全部租户共用，成员数量只统计指定租户的用户。
+func roleDTOs(tx *gorm.DB, tenantUUID uuid.UUID, roles []*entity.Role) ([]*dto.Role, error) {
+	roleUUIDs := make([]uuid.UUID, 0, len(roles))
+	for _, role := range roles {
+		roleUUIDs = append(roleUUIDs, role.UUID)
+	}
+
+	permissions := make(map[uuid.UUID][]string, len(roles))
+	counts := make(map[uuid.UUID]int64, len(roles))
+	if len(roleUUIDs) > 0 {
+		var rolePermissions []entity.RolePermission
+		if err := tx.Preload("Permission").Where("role_uuid IN ?", roleUUIDs).Find(&rolePermissions).Error; err != nil {
+			return nil, err
+		}
+		for _, rolePermission := range rolePermissions {
+			if rolePermission.Permission != nil {
+				permissions[rolePermission.RoleUUID] = append(permissions[rolePermission.RoleUUID], rolePermission.Permission.Code)
+			}
+		}
+
+		var memberCounts []struct {
+			RoleUUID uuid.UUID
+			Count    int64
+		}
+		err := tx.Model(&entity.UserRole{}).
+			Select("role_uuid, COUNT(DISTINCT user_uuid) AS count").
+			Where("role_uuid IN ? AND is_active = ?", roleUUIDs, true).
+			Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+			Where("user_uuid IN (?)", tenantUsers(tx, tenantUUID)).
+			Group("role_uuid").
+			Scan(&memberCounts).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, memberCount := range memberCounts {
+			counts[memberCount.RoleUUID] = memberCount.Count
+		}
+	}
+
+	list := make([]*dto.Role, 0, len(roles))
+	for _, role := range roles {
+		codes := permissions[role.UUID]
+		if codes == nil {
+			codes = make([]string, 0)
+		}
+		slices.Sort(codes)
+		list = append(list, &dto.Role{
+			UUID:            role.UUID,
+			ApplicationUUID: role.ApplicationUUID,
+			Name:            role.Name,
+			DisplayName:     role.DisplayName,
+			Description:     role.Description,
+			IsBuiltin:       role.ApplicationUUID == nil && slices.Contains(builtinRoles, role.Name),
+			Permissions:     codes,
+			MemberCount:     counts[role.UUID],
+			CreatedAt:       role.CreatedAt,
+			UpdatedAt:       role.UpdatedAt,
+		})
+	}
+	return list, nil
+}
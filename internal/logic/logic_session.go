@@ -0,0 +1,175 @@
+package logic
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+)
+
+// ssoSession 表示用户在 SSO 的一次浏览器会话，保存在 Redis 中，由 HttpOnly Cookie 引用。
+//
+// 会话ID是 Cookie 值的 SHA-256 摘要，数据库与 Redis 中只保存会话ID，泄露后不能据此伪造 Cookie。
+//
+// 字段说明：
+//   - TenantUUID: 会话所属的租户UUID，会话只在该租户内有效。
+//   - UserUUID: 会话所属的用户UUID。
+//   - LoginType: 建立会话时使用的登录方式。
+//   - AuthTime: 用户最近一次输入凭据完成登录的时间，用于处理授权请求的 prompt=login 与 max_age。
+//   - IPAddress: 建立会话时的客户端 IP。
+//   - UserAgent: 建立会话时的 User-Agent。
+//   - ExpiresAt: 会话的最长有效期截止时间，空闲续期不会超过该时间。
+type ssoSession struct {
+	TenantUUID uuid.UUID `json:"tenant_uuid"`
+	UserUUID   uuid.UUID `json:"user_uuid"`
+	LoginType  string    `json:"login_type"`
+	AuthTime   time.Time `json:"auth_time"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// newSession 在用户完成登录后建立 SSO 会话并写入会话 Cookie，返回会话ID。
+//
+// 浏览器已持有的会话会被结束，每次登录都使用新的会话ID，避免会话固定攻击。
+func (b *base) newSession(c *gin.Context, userUUID uuid.UUID, loginType string) (string, error) {
+	previous, _, err := b.currentSession(c)
+	if err != nil {
+		return "", err
+	}
+	if previous != "" {
+		if err := b.destroySession(c, previous); err != nil {
+			return "", err
+		}
+	}
+
+	now := time.Now()
+	session := ssoSession{
+		TenantUUID: b.tenant.UUID,
+		UserUUID:   userUUID,
+		LoginType:  loginType,
+		AuthTime:   now,
+		IPAddress:  c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		ExpiresAt:  now.Add(b.sso.Session.MaxLifetime),
+	}
+	value, err := jsoniter.MarshalToString(&session)
+	if err != nil {
+		return "", err
+	}
+
+	cookie := utility.RandomToken(32)
+	sessionID := sessionIDOf(cookie)
+	userKey := fmt.Sprintf(constants.RedisUserSessions, userUUID)
+	_, err = b.rdb.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.Set(c, fmt.Sprintf(constants.RedisSession, sessionID), value, min(b.sso.Session.IdleTTL, b.sso.Session.MaxLifetime))
+		pipe.SAdd(c, userKey, sessionID)
+		pipe.Expire(c, userKey, b.sso.Session.MaxLifetime)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	b.setSessionCookie(c, cookie, b.sso.Session.MaxLifetime)
+	return sessionID, nil
+}
+
+// currentSession 读取浏览器会话 Cookie 引用的 SSO 会话并续期，没有可用的会话时返回空的会话ID。
+//
+// 属于其他租户的会话视为不可用，但不会被结束，以免影响用户在该租户中的登录状态。
+func (b *base) currentSession(c *gin.Context) (string, *ssoSession, error) {
+	cookie, err := c.Cookie(b.sessionCookieName())
+	if err != nil || cookie == "" {
+		return "", nil, nil
+	}
+
+	sessionID := sessionIDOf(cookie)
+	key := fmt.Sprintf(constants.RedisSession, sessionID)
+	value, err := b.rdb.Get(c, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	var session ssoSession
+	if err := jsoniter.UnmarshalFromString(value, &session); err != nil {
+		return "", nil, err
+	}
+	if session.TenantUUID != b.tenant.UUID {
+		return "", nil, nil
+	}
+
+	remaining := time.Until(session.ExpiresAt)
+	if remaining <= 0 {
+		return "", nil, b.destroySession(c, sessionID)
+	}
+	if err := b.rdb.Expire(c, key, min(b.sso.Session.IdleTTL, remaining)).Err(); err != nil {
+		return "", nil, err
+	}
+	return sessionID, &session, nil
+}
+
+// destroySession 结束一个 SSO 会话。
+func (b *base) destroySession(c *gin.Context, sessionID string) error {
+	key := fmt.Sprintf(constants.RedisSession, sessionID)
+	value, err := b.rdb.GetDel(c, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var session ssoSession
+	if err := jsoniter.UnmarshalFromString(value, &session); err != nil {
+		return err
+	}
+	return b.rdb.SRem(c, fmt.Sprintf(constants.RedisUserSessions, session.UserUUID), sessionID).Err()
+}
+
+// destroyUserSessions 结束用户的全部 SSO 会话，使其在所有浏览器上都需要重新登录。
+func (b *base) destroyUserSessions(c *gin.Context, userUUID uuid.UUID) error {
+	userKey := fmt.Sprintf(constants.RedisUserSessions, userUUID)
+	sessionIDs, err := b.rdb.SMembers(c, userKey).Result()
+	if err != nil {
+		return err
+	}
+	keys := []string{userKey}
+	for _, sessionID := range sessionIDs {
+		keys = append(keys, fmt.Sprintf(constants.RedisSession, sessionID))
+	}
+	return b.rdb.Del(c, keys...).Err()
+}
+
+// setSessionCookie 写入会话 Cookie；maxAge 为负数时删除 Cookie。
+func (b *base) setSessionCookie(c *gin.Context, value string, maxAge time.Duration) {
+	cfg := b.sso.Session
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(b.sessionCookieName(), value, int(maxAge.Seconds()), "/", cfg.CookieDomain, cfg.Secure, true)
+}
+
+// sessionCookieName 返回当前租户的会话 Cookie 名称。
+//
+// 默认租户使用配置的名称，其余租户在其后加上租户代码，使同一域名下通过路径访问的多个租户可以同时保持登录。
+func (b *base) sessionCookieName() string {
+	if b.tenant.Code == constants.TenantDefault {
+		return b.sso.Session.CookieName
+	}
+	return b.sso.Session.CookieName + "_" + b.tenant.Code
+}
+
+// sessionIDOf 计算会话 Cookie 对应的会话ID。
+func sessionIDOf(cookie string) string {
+	sum := sha256.Sum256([]byte(cookie))
+	return hex.EncodeToString(sum[:])
+}
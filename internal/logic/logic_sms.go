@@ -0,0 +1,319 @@
+package logic
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/secret"
+	"github.com/bamboo-services/bamboo-sso/pkg/sms"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 短信验证码的用途，Redis 键名中包含用途，不同用途的验证码不能混用。
+const (
+	smsPurposeLogin = "login" // 短信验证码登录
+	smsPurposeBind  = "bind"  // 绑定手机号
+)
+
+const (
+	smsCodeLength  = 6                // 短信验证码的位数
+	smsSendTimeout = 10 * time.Second // 异步发送短信的超时时间
+)
+
+var (
+	// errSMSCodeInvalid 表示短信验证码错误、已过期或已被使用。
+	errSMSCodeInvalid = result.ErrUnauthorized.WithMessage("验证码错误或已过期")
+	// errSMSCooldown 表示同一手机号的发送间隔尚未结束。
+	errSMSCooldown = result.ErrTooMany.WithMessage("验证码发送过于频繁，请稍后再试")
+	// errPhoneTaken 表示手机号已被其他账号绑定。
+	errPhoneTaken = result.ErrConflict.WithMessage("该手机号已被其他账号绑定")
+)
+
+// SMSLogic 负责短信验证码登录与手机号绑定。
+//
+// 手机号加密存储，查询时使用其盲索引 User.PhoneHash；验证码只在 Redis 中保存摘要。
+type SMSLogic struct {
+	base
+}
+
+// NewSMS 创建一个新的 SMSLogic 实例。
+func NewSMS(c *gin.Context) *SMSLogic {
+	return &SMSLogic{base: newBase(c)}
+}
+
+// SendLoginCode 向手机号发送登录验证码。
+//
+// 为避免泄露手机号是否已注册，手机号未绑定、未验证或账号已停用时同样返回成功并进入发送冷却，
+// 且短信在后台异步发送，使几种情况的响应耗时一致。
+func (s *SMSLogic) SendLoginCode(c *gin.Context, phone string) error {
+	index, err := secret.BlindIndex(phone)
+	if err != nil {
+		return err
+	}
+	if err := s.limitAccount(c, constants.RateLimitCode, index); err != nil {
+		return err
+	}
+	if err := s.claimSMSCooldown(c, index); err != nil {
+		return err
+	}
+
+	user, err := s.findUserByPhone(index)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	code, err := s.newSMSCode(c, smsPurposeLogin, index)
+	if err != nil {
+		return err
+	}
+	send, log := s.sms.Send, s.log.Named("SMS")
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), smsSendTimeout)
+		defer cancel()
+		if err := send(ctx, phone, sms.TemplateLogin, map[string]string{"code": code}); err != nil {
+			log.Error("发送登录验证码失败", zap.Stringer("user", user.UUID), zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Login 使用手机号与短信验证码完成登录的第一步验证。
+//
+// 只有已验证的手机号可以用于登录；需要两步验证的用户会得到两步验证挑战，每次失败都会写入登录日志。
+func (s *SMSLogic) Login(c *gin.Context, phone, code string) (*dto.Login, error) {
+	index, err := secret.BlindIndex(phone)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.limitAccount(c, constants.RateLimitLogin, index); err != nil {
+		return nil, err
+	}
+	user, err := s.findUserByPhone(index)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		NewLogin(c).Failed(c, nil, constants.LoginTypeSMS, nil, constants.FailureUserNotFound)
+		return nil, errSMSCodeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLockout(c, user.UUID); err != nil {
+		NewLogin(c).Failed(c, &user.UUID, constants.LoginTypeSMS, nil, constants.FailureAccountLocked)
+		return nil, err
+	}
+
+	if err := s.verifySMSCode(c, smsPurposeLogin, index, code); err != nil {
+		if errors.Is(err, errSMSCodeInvalid) {
+			NewLogin(c).Failed(c, &user.UUID, constants.LoginTypeSMS, nil, constants.FailureSMSCodeInvalid)
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		NewLogin(c).Failed(c, &user.UUID, constants.LoginTypeSMS, nil, constants.FailureUserInactive)
+		return nil, result.ErrForbidden.WithMessage("账号已被停用")
+	}
+	return NewLogin(c).Succeed(c, user, constants.LoginTypeSMS, nil)
+}
+
+// SendBindCode 向当前登录用户要绑定的手机号发送验证码。
+func (s *SMSLogic) SendBindCode(c *gin.Context, phone string) error {
+	user := currentUser(c)
+	index, err := secret.BlindIndex(phone)
+	if err != nil {
+		return err
+	}
+	if user.IsPhoneVerified() && user.PhoneHash != nil && *user.PhoneHash == index {
+		return result.ErrConflict.WithMessage("该手机号已绑定")
+	}
+	if err := s.checkPhoneAvailable(s.db, user.UUID, index); err != nil {
+		return err
+	}
+	if err := s.limitAccount(c, constants.RateLimitCode, user.UUID.String()); err != nil {
+		return err
+	}
+	if err := s.claimSMSCooldown(c, index); err != nil {
+		return err
+	}
+
+	code, err := s.newSMSCode(c, smsPurposeBind, bindSubject(user.UUID, index))
+	if err != nil {
+		return err
+	}
+	if err := s.sms.Send(c, phone, sms.TemplateBind, map[string]string{"code": code}); err != nil {
+		s.log.Named("SMS").Error("发送绑定验证码失败", zap.Stringer("user", user.UUID), zap.Error(err))
+		// 发送失败时解除冷却，允许用户立即重试
+		_ = s.rdb.Del(c, fmt.Sprintf(constants.RedisSMSCooldown, index)).Err()
+		return result.ErrThirdParty.WithMessage("短信发送失败，请稍后重试")
+	}
+	return nil
+}
+
+// BindPhone 使用短信验证码为当前登录用户绑定手机号，已绑定的手机号会被替换。
+func (s *SMSLogic) BindPhone(c *gin.Context, phone, code string) error {
+	user := currentUser(c)
+	index, err := secret.BlindIndex(phone)
+	if err != nil {
+		return err
+	}
+	if err := s.verifySMSCode(c, smsPurposeBind, bindSubject(user.UUID, index), code); err != nil {
+		return err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var locked entity.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, "uuid = ?", user.UUID).Error; err != nil {
+			return err
+		}
+		if err := s.checkPhoneAvailable(tx, user.UUID, index); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		err := tx.Model(&locked).Select("phone", "phone_hash", "phone_verified_at").Updates(&entity.User{
+			Phone:           &phone,
+			PhoneHash:       &index,
+			PhoneVerifiedAt: &now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditPhoneBind, constants.ResourceUser, &user.UUID, map[string]any{
+			"phone": maskPhone(phone),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return s.invalidateAuth(c, user.UUID)
+}
+
+// findUserByPhone 根据手机号的盲索引查询当前租户中手机号已验证的用户。
+func (s *SMSLogic) findUserByPhone(index string) (*entity.User, error) {
+	var user entity.User
+	err := s.inTenant(s.db).Where("phone_hash = ? AND phone_verified_at IS NOT NULL", index).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// checkPhoneAvailable 检查手机号是否已被当前租户中的其他用户绑定。
+func (s *SMSLogic) checkPhoneAvailable(tx *gorm.DB, userUUID uuid.UUID, index string) error {
+	var count int64
+	err := s.inTenant(tx.Model(&entity.User{})).Where("phone_hash = ? AND uuid <> ?", index, userUUID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errPhoneTaken
+	}
+	return nil
+}
+
+// claimSMSCooldown 为手机号开始发送冷却，冷却尚未结束时返回错误；冷却按手机号计算，与验证码用途无关。
+func (s *SMSLogic) claimSMSCooldown(c *gin.Context, index string) error {
+	ok, err := s.rdb.SetNX(c, fmt.Sprintf(constants.RedisSMSCooldown, index), 1, s.sso.SMS.Cooldown).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errSMSCooldown
+	}
+	return nil
+}
+
+// newSMSCode 生成一枚短信验证码并在 Redis 中保存其摘要，同一用途与对象之前发送的验证码随之作废。
+func (s *SMSLogic) newSMSCode(c *gin.Context, purpose, subject string) (string, error) {
+	code := utility.RandomDigits(smsCodeLength)
+	key := fmt.Sprintf(constants.RedisSMSCode, purpose, subject)
+	_, err := s.rdb.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.Del(c, key)
+		pipe.HSet(c, key, "hash", smsCodeHash(code), "attempts", 0)
+		pipe.Expire(c, key, s.sso.SMS.CodeTTL)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// verifySMSCode 校验短信验证码，验证成功后作废验证码；每枚验证码只能使用一次，错误次数达到上限时同样作废。
+func (s *SMSLogic) verifySMSCode(c *gin.Context, purpose, subject, code string) error {
+	key := fmt.Sprintf(constants.RedisSMSCode, purpose, subject)
+	hash, err := s.rdb.HGet(c, key, "hash").Result()
+	if errors.Is(err, redis.Nil) {
+		return errSMSCodeInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(smsCodeHash(code))) != 1 {
+		// 验证码可能恰好在此期间过期，ExpireNX 保证自增重新创建的键不会永久残留
+		var attempts *redis.IntCmd
+		_, err := s.rdb.TxPipelined(c, func(pipe redis.Pipeliner) error {
+			attempts = pipe.HIncrBy(c, key, "attempts", 1)
+			pipe.ExpireNX(c, key, s.sso.SMS.CodeTTL)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if attempts.Val() >= int64(s.sso.SMS.MaxAttempts) {
+			if err := s.rdb.Del(c, key).Err(); err != nil {
+				return err
+			}
+		}
+		return errSMSCodeInvalid
+	}
+
+	// 并发提交同一验证码时只有一次能够删除成功
+	deleted, err := s.rdb.Del(c, key).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errSMSCodeInvalid
+	}
+	return nil
+}
+
+// bindSubject 返回绑定手机号验证码的对象，验证码同时绑定用户与手机号，不能用于其他账号或其他手机号。
+func bindSubject(userUUID uuid.UUID, index string) string {
+	return userUUID.String() + ":" + index
+}
+
+// smsCodeHash 计算短信验证码的摘要。
+func smsCodeHash(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// maskPhone 隐藏手机号中间的数字，仅保留前 3 位与后 4 位，用于审计日志等需要展示手机号的场景。
+func maskPhone(phone string) string {
+	if len(phone) <= 7 {
+		return phone
+	}
+	return phone[:3] + "****" + phone[len(phone)-4:]
+}
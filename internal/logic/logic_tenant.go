@@ -0,0 +1,329 @@
+package logic
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+
+	xConsts "github.com/bamboo-services/bamboo-base-go/constants"
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tenantCodePattern 限定租户代码的字符集，代码会作为路径参数出现在访问地址与签发者标识中。
+var tenantCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// TenantLogic 负责租户的管理。
+type TenantLogic struct {
+	base
+}
+
+// NewTenant 创建一个新的 TenantLogic 实例。
+func NewTenant(c *gin.Context) *TenantLogic {
+	return &TenantLogic{base: newBase(c)}
+}
+
+// ResolveTenant 确定请求所属的租户。
+//
+// 路径参数 tenant 中的租户代码优先，其次是与请求 Host 相同的租户域名，都未匹配时使用默认租户；
+// 租户不存在时返回未找到错误，已停用时返回禁止访问错误。
+func ResolveTenant(c *gin.Context) (*entity.Tenant, error) {
+	db := c.MustGet(xConsts.ContextDatabase).(*gorm.DB).WithContext(c)
+
+	var tenant *entity.Tenant
+	if code := c.Param("tenant"); code != "" {
+		var found entity.Tenant
+		err := db.Where("code = ?", code).First(&found).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, result.ErrNotFound.WithMessage("租户不存在")
+		}
+		if err != nil {
+			return nil, err
+		}
+		tenant = &found
+	} else {
+		var tenants []*entity.Tenant
+		if err := db.Where("domain = ? OR code = ?", requestHost(c), constants.TenantDefault).Find(&tenants).Error; err != nil {
+			return nil, err
+		}
+		for _, found := range tenants {
+			if tenant == nil || found.Code != constants.TenantDefault {
+				tenant = found
+			}
+		}
+		if tenant == nil {
+			return nil, result.ErrNotFound.WithMessage("租户不存在")
+		}
+	}
+
+	if !tenant.IsActive {
+		return nil, result.ErrForbidden.WithMessage("租户已停用")
+	}
+	return tenant, nil
+}
+
+// Current 获取当前请求所属租户的公开信息，供登录页展示。
+func (t *TenantLogic) Current() *dto.PublicTenant {
+	return &dto.PublicTenant{Code: t.tenant.Code, Name: t.tenant.Name, Issuer: t.issuer()}
+}
+
+// List 列出全部租户，默认租户排在最前面。只能在默认租户中管理租户。
+func (t *TenantLogic) List() ([]*dto.Tenant, error) {
+	if err := t.requireDefaultTenant(); err != nil {
+		return nil, err
+	}
+	var tenants []*entity.Tenant
+	err := t.db.Order(clause.Expr{SQL: "code = ? DESC", Vars: []any{constants.TenantDefault}}).Order("created_at").Find(&tenants).Error
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*dto.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		list = append(list, tenantDTO(t.sso, tenant))
+	}
+	return list, nil
+}
+
+// Get 获取指定的租户。
+func (t *TenantLogic) Get(tenantUUID uuid.UUID) (*dto.Tenant, error) {
+	if err := t.requireDefaultTenant(); err != nil {
+		return nil, err
+	}
+	tenant, err := findTenant(t.db, tenantUUID)
+	if err != nil {
+		return nil, err
+	}
+	return tenantDTO(t.sso, tenant), nil
+}
+
+// Create 创建一个租户及其首位超级管理员，并写入审计日志。
+//
+// 新租户没有接入应用与第三方提供商，由其超级管理员登录该租户后自行配置；内置角色由全部租户共用。
+func (t *TenantLogic) Create(c *gin.Context, req *request.TenantCreate) (*dto.Tenant, error) {
+	if err := t.requireDefaultTenant(); err != nil {
+		return nil, err
+	}
+	if !tenantCodePattern.MatchString(req.Code) {
+		return nil, result.ErrParameter.WithMessage("租户代码只能包含小写字母、数字与短横线，且不能以短横线开头")
+	}
+	if err := t.checkPassword(req.AdminPassword, req.AdminUsername, req.AdminEmail); err != nil {
+		return nil, err
+	}
+	passwordHash, err := t.policy.Hash(req.AdminPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant := &entity.Tenant{Code: req.Code, Name: req.Name}
+	if req.Domain != nil {
+		tenant.Domain = normalizeDomain(*req.Domain)
+	}
+	if req.Issuer != nil {
+		tenant.Issuer = utility.NilIfBlank(*req.Issuer)
+	}
+
+	err = t.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entity.Tenant{}).Where("code = ?", tenant.Code).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return result.ErrConflict.WithMessage("租户代码已存在")
+		}
+		if err := checkDomainAvailable(tx, tenant.Domain, uuid.Nil); err != nil {
+			return err
+		}
+
+		if err := tx.Create(tenant).Error; err != nil {
+			return err
+		}
+
+		// 首位超级管理员的密码由创建者设置，登录后需要先修改
+		admin := &entity.User{
+			TenantUUID:             tenant.UUID,
+			Username:               req.AdminUsername,
+			Email:                  &req.AdminEmail,
+			PasswordHash:           &passwordHash,
+			PasswordChangeRequired: true,
+		}
+		if err := tx.Create(admin).Error; err != nil {
+			return err
+		}
+		var role entity.Role
+		if err := tx.Where("tenant_uuid IS NULL AND application_uuid IS NULL AND name = ?", constants.RoleSuperAdmin).First(&role).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&entity.UserRole{UserUUID: admin.UUID, RoleUUID: role.UUID, AssignedBy: &currentUser(c).UUID}).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditTenantCreate, constants.ResourceTenant, &tenant.UUID, map[string]any{
+			"code":       tenant.Code,
+			"domain":     tenant.Domain,
+			"admin_uuid": admin.UUID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tenantDTO(t.sso, tenant), nil
+}
+
+// Update 修改租户信息或启用状态，只更新请求中传入的字段，并在审计日志中记录被修改的字段名。
+//
+// 停用的租户的全部请求都会被拒绝，其用户持有的令牌也随之无法使用；默认租户不能停用。
+func (t *TenantLogic) Update(c *gin.Context, tenantUUID uuid.UUID, req *request.TenantUpdate) (*dto.Tenant, error) {
+	if err := t.requireDefaultTenant(); err != nil {
+		return nil, err
+	}
+
+	var tenant *entity.Tenant
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		tenant, err = findTenant(tx.Clauses(clause.Locking{Strength: "UPDATE"}), tenantUUID)
+		if err != nil {
+			return err
+		}
+
+		changed := make([]string, 0)
+		setOptional := func(field string, target **string, value *string) {
+			if (value == nil) != (*target == nil) || (value != nil && *value != **target) {
+				*target = value
+				changed = append(changed, field)
+			}
+		}
+		if req.Name != nil && tenant.Name != *req.Name {
+			tenant.Name = *req.Name
+			changed = append(changed, "name")
+		}
+		if req.Domain != nil {
+			domain := normalizeDomain(*req.Domain)
+			if err := checkDomainAvailable(tx, domain, tenant.UUID); err != nil {
+				return err
+			}
+			setOptional("domain", &tenant.Domain, domain)
+		}
+		if req.Issuer != nil {
+			setOptional("issuer", &tenant.Issuer, utility.NilIfBlank(*req.Issuer))
+		}
+		if req.IsActive != nil && tenant.IsActive != *req.IsActive {
+			if tenant.Code == constants.TenantDefault {
+				return result.ErrForbidden.WithMessage("默认租户不能停用")
+			}
+			tenant.IsActive = *req.IsActive
+			changed = append(changed, "is_active")
+		}
+
+		if len(changed) == 0 {
+			return nil
+		}
+		if err := tx.Save(tenant).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditTenantUpdate, constants.ResourceTenant, &tenant.UUID, map[string]any{
+			"code":   tenant.Code,
+			"fields": changed,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tenantDTO(t.sso, tenant), nil
+}
+
+// requireDefaultTenant 要求当前请求属于默认租户，其他租户的管理员不能管理租户。
+func (t *TenantLogic) requireDefaultTenant() error {
+	if t.tenant.Code != constants.TenantDefault {
+		return result.ErrForbidden.WithMessage("只能在默认租户中管理租户")
+	}
+	return nil
+}
+
+// inTenant 将查询限定在当前请求所属的租户内。
+func (b *base) inTenant(db *gorm.DB) *gorm.DB {
+	return db.Where("tenant_uuid = ?", b.tenant.UUID)
+}
+
+// issuer 返回当前请求所属租户的签发者标识。
+func (b *base) issuer() string {
+	return tenantIssuer(b.sso, b.tenant)
+}
+
+// tenantIssuer 返回租户的签发者标识：租户单独配置的签发者优先，默认租户使用 SSO 的签发者，
+// 其余租户使用 SSO 的签发者加 "/t/<租户代码>"。
+func tenantIssuer(sso *config.SSO, tenant *entity.Tenant) string {
+	if tenant.Issuer != nil {
+		return *tenant.Issuer
+	}
+	if tenant.Code == constants.TenantDefault {
+		return sso.OAuth.Issuer
+	}
+	return strings.TrimSuffix(sso.OAuth.Issuer, "/") + "/t/" + tenant.Code
+}
+
+// findTenant 根据 UUID 查找租户。
+func findTenant(db *gorm.DB, tenantUUID uuid.UUID) (*entity.Tenant, error) {
+	var tenant entity.Tenant
+	err := db.First(&tenant, "uuid = ?", tenantUUID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, result.ErrNotFound.WithMessage("租户不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// checkDomainAvailable 检查域名是否未被 exclude 以外的租户使用，domain 为空时直接通过。
+func checkDomainAvailable(tx *gorm.DB, domain *string, exclude uuid.UUID) error {
+	if domain == nil {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&entity.Tenant{}).Where("domain = ? AND uuid <> ?", *domain, exclude).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return result.ErrConflict.WithMessage("域名已被其他租户使用")
+	}
+	return nil
+}
+
+// normalizeDomain 将域名转换为小写，空白的域名返回 nil。
+func normalizeDomain(domain string) *string {
+	return utility.NilIfBlank(strings.ToLower(strings.TrimSpace(domain)))
+}
+
+// requestHost 返回请求的 Host 中不含端口的小写主机名。
+func requestHost(c *gin.Context) string {
+	host := c.Request.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	return strings.ToLower(host)
+}
+
+// tenantDTO 将租户实体转换为管理后台使用的租户信息。
+func tenantDTO(sso *config.SSO, tenant *entity.Tenant) *dto.Tenant {
+	return &dto.Tenant{
+		UUID:      tenant.UUID,
+		Code:      tenant.Code,
+		Name:      tenant.Name,
+		Domain:    tenant.Domain,
+		Issuer:    tenantIssuer(sso, tenant),
+		IsDefault: tenant.Code == constants.TenantDefault,
+		IsActive:  tenant.IsActive,
+		CreatedAt: tenant.CreatedAt,
+		UpdatedAt: tenant.UpdatedAt,
+	}
+}
@@ -0,0 +1,249 @@
+package logic
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/secret"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// TokenLogic 负责用户令牌的签发与管理。
+type TokenLogic struct {
+	base
+}
+
+// NewToken 创建一个新的 TokenLogic 实例。
+func NewToken(c *gin.Context) *TokenLogic {
+	return &TokenLogic{base: newBase(c)}
+}
+
+// Issue 为指定用户签发一组新的访问令牌与刷新令牌，并记录登录设备信息。
+//
+// 参数 sessionID 为令牌所属的 SSO 会话，没有会话时传入空字符串；applicationUUID 仅在为接入应用签发令牌时传入，
+// 此时返回的令牌信息中包含用户持有的该应用的角色与用户所属的分组。
+func (t *TokenLogic) Issue(c *gin.Context, userUUID uuid.UUID, sessionID string, applicationUUID *uuid.UUID) (*dto.Token, error) {
+	now := time.Now()
+	userAgent := c.Request.UserAgent()
+	ipAddress := c.ClientIP()
+
+	userToken := entity.UserToken{
+		UserUUID:              userUUID,
+		ApplicationUUID:       applicationUUID,
+		SessionID:             utility.NilIfBlank(sessionID),
+		AccessToken:           utility.RandomToken(32),
+		RefreshToken:          utility.RandomToken(48),
+		AccessTokenExpiresAt:  now.Add(t.sso.Token.AccessTTL),
+		RefreshTokenExpiresAt: now.Add(t.sso.Token.RefreshTTL),
+		IPAddress:             &ipAddress,
+		UserAgent:             &userAgent,
+		LastUsedAt:            &now,
+	}
+	if err := t.db.Create(&userToken).Error; err != nil {
+		return nil, err
+	}
+
+	token := &dto.Token{
+		UserUUID:     userUUID,
+		AccessToken:  userToken.AccessToken,
+		RefreshToken: userToken.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(t.sso.Token.AccessTTL.Seconds()),
+	}
+	if applicationUUID != nil {
+		token.SessionID = sessionID
+		roles, err := applicationRoles(t.db, userUUID, *applicationUUID)
+		if err != nil {
+			return nil, err
+		}
+		token.Roles = roles
+		groups, err := userGroups(t.db, userUUID)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groups {
+			token.Groups = append(token.Groups, group.Name)
+		}
+		slices.Sort(token.Groups)
+	}
+	return token, nil
+}
+
+// Identity 表示一次访问令牌认证的结果，由认证中间件写入请求上下文。
+//
+// 认证结果以 gob 编码缓存在 Redis 中以保留 JSON 中隐藏的密码哈希等字段，并使用敏感字段的密钥环加密，
+// Redis 中不保存明文的个人信息。
+//
+// 字段说明：
+//   - User: 令牌所属的用户，不含关联数据。
+//   - Token: 令牌记录，不含关联数据。
+//   - Roles: 用户持有的有效角色名称。
+//   - Permissions: 有效角色被授予的权限代码。
+//   - GrantsExpireAt: 有效角色中最早的过期时间，缓存不会超过该时间。
+type Identity struct {
+	User           *entity.User
+	Token          *entity.UserToken
+	Roles          []string
+	Permissions    []string
+	GrantsExpireAt *time.Time
+}
+
+// Authenticate 根据访问令牌查找对应的令牌记录、用户与用户持有的有效角色和权限，
+// 令牌无效、过期、已撤销、用户被停用或用户不属于当前请求的租户时返回未登录错误。
+//
+// 认证结果缓存在 Redis 中，缓存期间不再查询数据库；令牌撤销、用户信息或角色变更时由 invalidateAuth 清除缓存。
+func (t *TokenLogic) Authenticate(c *gin.Context, accessToken string) (*Identity, error) {
+	key := fmt.Sprintf(constants.RedisAuthCache, tokenDigest(accessToken))
+	identity, err := t.loadAuth(c, key)
+	if err != nil {
+		return nil, err
+	}
+	if identity == nil {
+		if identity, err = t.resolveAuth(accessToken); err != nil {
+			return nil, err
+		}
+		if err := t.saveAuth(c, key, identity); err != nil {
+			return nil, err
+		}
+	}
+	if identity.Token.IsAccessTokenExpired() {
+		return nil, result.ErrUnauthorized.WithMessage("登录已过期，请重新登录")
+	}
+	if identity.User.TenantUUID != t.tenant.UUID {
+		return nil, result.ErrUnauthorized
+	}
+	return identity, nil
+}
+
+// resolveAuth 从数据库中查询访问令牌的认证结果，并更新令牌的最近使用时间。
+//
+// 只在缓存未命中时调用，最近使用时间因此每个缓存周期最多写入一次。
+func (t *TokenLogic) resolveAuth(accessToken string) (*Identity, error) {
+	var userToken entity.UserToken
+	err := t.db.Preload("User").Where(&entity.UserToken{AccessToken: accessToken}).First(&userToken).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, result.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+	if userToken.IsAccessTokenExpired() || userToken.User == nil {
+		return nil, result.ErrUnauthorized.WithMessage("登录已过期，请重新登录")
+	}
+	if !userToken.User.IsActive {
+		return nil, result.ErrUnauthorized.WithMessage("账号已被停用")
+	}
+
+	now := time.Now()
+	if err := t.db.Model(&userToken).UpdateColumn("last_used_at", now).Error; err != nil {
+		return nil, err
+	}
+	userToken.LastUsedAt = &now
+
+	identity := &Identity{User: userToken.User, Token: &userToken}
+	userToken.User = nil
+	identity.Roles, identity.Permissions, identity.GrantsExpireAt, err = activeGrants(t.db, identity.User.UUID)
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// loadAuth 读取访问令牌的认证缓存，缓存不存在或无法解密时返回 nil。
+func (t *TokenLogic) loadAuth(c *gin.Context, key string) (*Identity, error) {
+	value, err := t.rdb.Get(c, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// 轮换密钥后旧缓存可能无法解密，视为未命中重新查询即可
+	plaintext, err := secret.Current().Open(value)
+	if err != nil {
+		return nil, nil
+	}
+	var identity Identity
+	if err := gob.NewDecoder(strings.NewReader(plaintext)).Decode(&identity); err != nil {
+		return nil, nil
+	}
+	return &identity, nil
+}
+
+// saveAuth 缓存访问令牌的认证结果，缓存时间不超过访问令牌与有效角色的过期时间。
+func (t *TokenLogic) saveAuth(c *gin.Context, key string, identity *Identity) error {
+	ttl := min(t.sso.Token.CacheTTL, time.Until(identity.Token.AccessTokenExpiresAt))
+	if identity.GrantsExpireAt != nil {
+		ttl = min(ttl, time.Until(*identity.GrantsExpireAt))
+	}
+	if ttl <= 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(identity); err != nil {
+		return err
+	}
+	sealed, err := secret.Current().Seal(buf.String())
+	if err != nil {
+		return err
+	}
+	userKey := fmt.Sprintf(constants.RedisUserAuthCache, identity.User.UUID)
+	_, err = t.rdb.TxPipelined(c, func(pipe redis.Pipeliner) error {
+		pipe.Set(c, key, sealed, ttl)
+		pipe.SAdd(c, userKey, key)
+		pipe.Expire(c, userKey, t.sso.Token.CacheTTL)
+		return nil
+	})
+	return err
+}
+
+// invalidateAuth 清除用户全部访问令牌的认证缓存。
+//
+// 撤销令牌、修改用户信息或角色后必须调用，否则缓存期间仍会使用旧的认证结果。
+func (b *base) invalidateAuth(c *gin.Context, userUUID uuid.UUID) error {
+	userKey := fmt.Sprintf(constants.RedisUserAuthCache, userUUID)
+	keys, err := b.rdb.SMembers(c, userKey).Result()
+	if err != nil {
+		return err
+	}
+	return b.rdb.Del(c, append(keys, userKey)...).Err()
+}
+
+// invalidateAuths 清除多个用户全部访问令牌的认证缓存，用于角色或分组变更影响多个用户时。
+func (b *base) invalidateAuths(c *gin.Context, userUUIDs []uuid.UUID) error {
+	for _, userUUID := range userUUIDs {
+		if err := b.invalidateAuth(c, userUUID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tokenDigest 计算访问令牌的摘要，Redis 键名中不保存令牌原文。
+func tokenDigest(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// revokeUserTokens 撤销用户全部未撤销的令牌，使其在所有设备上退出登录。
+func revokeUserTokens(tx *gorm.DB, userUUID uuid.UUID) error {
+	return tx.Model(&entity.UserToken{}).
+		Where("user_uuid = ? AND is_revoked = ?", userUUID, false).
+		Update("is_revoked", true).Error
+}
@@ -0,0 +1,325 @@
+package logic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/totp"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// recoveryCodeCount 是每次生成的恢复码数量。
+	recoveryCodeCount = 10
+	// recoveryCodeAlphabet 是恢复码使用的字符集，去掉了容易混淆的 0/o、1/l/i。
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+var (
+	// errTOTPInvalid 表示动态口令错误或已被使用。
+	errTOTPInvalid = result.ErrParameter.WithMessage("动态口令错误或已被使用")
+	// errRecoveryInvalid 表示恢复码错误或已被使用。
+	errRecoveryInvalid = result.ErrParameter.WithMessage("恢复码错误或已被使用")
+	// errTOTPNotEnrolled 表示用户尚未登记身份验证器。
+	errTOTPNotEnrolled = result.ErrNotFound.WithMessage("尚未登记身份验证器")
+	// errTOTPAttemptsExceeded 表示已登录用户的动态口令错误次数过多，需等待一段时间后再试。
+	errTOTPAttemptsExceeded = result.ErrTooMany.WithMessage("动态口令错误次数过多，请稍后再试")
+)
+
+// TOTPLogic 负责当前登录用户对 TOTP 两步验证的登记、启用、关闭与恢复码管理。
+//
+// 启用、关闭与重新生成恢复码都需要提交动态口令，动态口令在时间窗口内错误次数过多时暂时拒绝校验，防止持有访问令牌者穷举动态口令。
+type TOTPLogic struct {
+	base
+}
+
+// NewTOTP 创建一个新的 TOTPLogic 实例。
+func NewTOTP(c *gin.Context) *TOTPLogic {
+	return &TOTPLogic{base: newBase(c)}
+}
+
+// Status 获取当前登录用户的两步验证状态。
+func (t *TOTPLogic) Status(c *gin.Context) (*dto.TOTPStatus, error) {
+	user := currentUser(c)
+	required, err := t.mfaMandatory(user.UUID)
+	if err != nil {
+		return nil, err
+	}
+	status := &dto.TOTPStatus{Required: required}
+
+	var userTOTP entity.UserTOTP
+	err = t.db.Where(&entity.UserTOTP{UserUUID: user.UUID, IsEnabled: true}).First(&userTOTP).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status.Enabled = true
+	status.ConfirmedAt = userTOTP.ConfirmedAt
+
+	err = t.db.Model(&entity.UserRecoveryCode{}).Where("user_uuid = ? AND used_at IS NULL", user.UUID).Count(&status.RecoveryCodesCount).Error
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Enroll 为当前登录用户登记身份验证器，随后需要使用 Confirm 提交一次动态口令才会启用。
+func (t *TOTPLogic) Enroll(c *gin.Context) (*dto.TOTPEnrollment, error) {
+	return t.enrollTOTP(currentUser(c))
+}
+
+// Confirm 使用动态口令确认登记的身份验证器并启用两步验证，返回新生成的恢复码。
+func (t *TOTPLogic) Confirm(c *gin.Context, code string) ([]string, error) {
+	var recoveryCodes []string
+	userUUID := currentUser(c).UUID
+	err := t.limitTOTPAttempts(c, userUUID, func() error {
+		return t.db.Transaction(func(tx *gorm.DB) error {
+			userTOTP, err := verifyTOTP(tx, userUUID, code)
+			if err != nil {
+				return err
+			}
+			if userTOTP.IsEnabled {
+				return result.ErrConflict.WithMessage("已启用两步验证")
+			}
+			recoveryCodes, err = confirmTOTP(c, tx, userTOTP)
+			return err
+		})
+	})
+	return recoveryCodes, err
+}
+
+// RegenerateRecoveryCodes 校验动态口令后重新生成恢复码，原有的恢复码全部作废。
+func (t *TOTPLogic) RegenerateRecoveryCodes(c *gin.Context, code string) ([]string, error) {
+	var recoveryCodes []string
+	userUUID := currentUser(c).UUID
+	err := t.limitTOTPAttempts(c, userUUID, func() error {
+		return t.db.Transaction(func(tx *gorm.DB) error {
+			userTOTP, err := verifyEnabledTOTP(tx, userUUID, code)
+			if err != nil {
+				return err
+			}
+			if recoveryCodes, err = generateRecoveryCodes(tx, userTOTP.UserUUID); err != nil {
+				return err
+			}
+			return writeAudit(c, tx, constants.AuditRecoveryGenerate, constants.ResourceTOTP, &userTOTP.UUID, map[string]any{
+				"user_uuid": userTOTP.UserUUID,
+			})
+		})
+	})
+	return recoveryCodes, err
+}
+
+// Disable 校验动态口令后关闭两步验证并删除恢复码；持有管理角色的用户不能关闭。
+func (t *TOTPLogic) Disable(c *gin.Context, code string) error {
+	userUUID := currentUser(c).UUID
+	mandatory, err := t.mfaMandatory(userUUID)
+	if err != nil {
+		return err
+	}
+	if mandatory {
+		return result.ErrForbidden.WithMessage("管理员账号必须启用两步验证")
+	}
+
+	return t.limitTOTPAttempts(c, userUUID, func() error {
+		return t.db.Transaction(func(tx *gorm.DB) error {
+			userTOTP, err := verifyEnabledTOTP(tx, userUUID, code)
+			if err != nil {
+				return err
+			}
+			if err := tx.Delete(userTOTP).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("user_uuid = ?", userUUID).Delete(&entity.UserRecoveryCode{}).Error; err != nil {
+				return err
+			}
+			return writeAudit(c, tx, constants.AuditTOTPDisable, constants.ResourceTOTP, &userTOTP.UUID, map[string]any{
+				"user_uuid": userUUID,
+			})
+		})
+	})
+}
+
+// limitTOTPAttempts 统计已登录用户的动态口令错误次数并执行 verify。
+//
+// 时间窗口内错误次数达到上限时不再校验，直接拒绝；动态口令错误时计数加一，校验通过后清零。
+func (t *TOTPLogic) limitTOTPAttempts(c *gin.Context, userUUID uuid.UUID, verify func() error) error {
+	key := fmt.Sprintf(constants.RedisTOTPAttempts, userUUID)
+	attempts, err := t.rdb.Get(c, key).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	if attempts >= int64(t.sso.MFA.MaxAttempts) {
+		if ttl, err := t.rdb.PTTL(c, key).Result(); err == nil && ttl > 0 {
+			c.Header("Retry-After", strconv.Itoa(max(1, int(math.Ceil(ttl.Seconds())))))
+		}
+		return errTOTPAttemptsExceeded
+	}
+
+	err = verify()
+	if errors.Is(err, errTOTPInvalid) {
+		// 窗口从第一次错误开始计算，ExpireNX 不会因后续的错误而延长
+		_, pipeErr := t.rdb.TxPipelined(c, func(pipe redis.Pipeliner) error {
+			pipe.Incr(c, key)
+			pipe.ExpireNX(c, key, t.sso.MFA.AttemptWindow)
+			return nil
+		})
+		if pipeErr != nil {
+			return pipeErr
+		}
+		return err
+	}
+	if err == nil && attempts > 0 {
+		return t.rdb.Del(c, key).Err()
+	}
+	return err
+}
+
+// enrollTOTP 为用户生成新的共享密钥；尚未启用的登记会被覆盖，已启用时需要先关闭。
+func (b *base) enrollTOTP(user *entity.User) (*dto.TOTPEnrollment, error) {
+	secret := totp.GenerateSecret()
+	err := b.db.Transaction(func(tx *gorm.DB) error {
+		var userTOTP entity.UserTOTP
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(&entity.UserTOTP{UserUUID: user.UUID}).First(&userTOTP).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(&entity.UserTOTP{UserUUID: user.UUID, Secret: secret}).Error
+		}
+		if err != nil {
+			return err
+		}
+		if userTOTP.IsEnabled {
+			return result.ErrConflict.WithMessage("已启用两步验证，如需更换身份验证器请先关闭")
+		}
+		userTOTP.Secret = secret
+		userTOTP.LastUsedStep = 0
+		return tx.Save(&userTOTP).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	account := user.Username
+	if user.Email != nil {
+		account = *user.Email
+	}
+	return &dto.TOTPEnrollment{Secret: secret, URI: totp.URI(b.sso.MFA.Issuer, account, secret)}, nil
+}
+
+// verifyTOTP 在事务中锁定用户的 TOTP 配置并校验动态口令，通过后记录其时间步以防止重复使用。
+func verifyTOTP(tx *gorm.DB, userUUID uuid.UUID, code string) (*entity.UserTOTP, error) {
+	var userTOTP entity.UserTOTP
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(&entity.UserTOTP{UserUUID: userUUID}).First(&userTOTP).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errTOTPNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok := totp.Validate(userTOTP.Secret, strings.TrimSpace(code), time.Now())
+	if !ok || step <= userTOTP.LastUsedStep {
+		return nil, errTOTPInvalid
+	}
+	userTOTP.LastUsedStep = step
+	if err := tx.Model(&userTOTP).Update("last_used_step", step).Error; err != nil {
+		return nil, err
+	}
+	return &userTOTP, nil
+}
+
+// verifyEnabledTOTP 校验已启用的 TOTP 配置的动态口令。
+func verifyEnabledTOTP(tx *gorm.DB, userUUID uuid.UUID, code string) (*entity.UserTOTP, error) {
+	userTOTP, err := verifyTOTP(tx, userUUID, code)
+	if err != nil {
+		return nil, err
+	}
+	if !userTOTP.IsEnabled {
+		return nil, result.ErrNotFound.WithMessage("尚未启用两步验证")
+	}
+	return userTOTP, nil
+}
+
+// confirmTOTP 启用已通过校验的 TOTP 配置，生成恢复码并写入审计日志。
+func confirmTOTP(c *gin.Context, tx *gorm.DB, userTOTP *entity.UserTOTP) ([]string, error) {
+	now := time.Now()
+	err := tx.Model(userTOTP).Updates(map[string]any{"is_enabled": true, "confirmed_at": now}).Error
+	if err != nil {
+		return nil, err
+	}
+	recoveryCodes, err := generateRecoveryCodes(tx, userTOTP.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+	err = writeAudit(c, tx, constants.AuditTOTPEnable, constants.ResourceTOTP, &userTOTP.UUID, map[string]any{
+		"user_uuid": userTOTP.UserUUID,
+	})
+	return recoveryCodes, err
+}
+
+// generateRecoveryCodes 删除用户原有的恢复码并生成一组新的恢复码，数据库中只保存其哈希值。
+func generateRecoveryCodes(tx *gorm.DB, userUUID uuid.UUID) ([]string, error) {
+	if err := tx.Where("user_uuid = ?", userUUID).Delete(&entity.UserRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]*entity.UserRecoveryCode, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code := newRecoveryCode()
+		codes = append(codes, code)
+		records = append(records, &entity.UserRecoveryCode{UserUUID: userUUID, CodeHash: hashRecoveryCode(code)})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// useRecoveryCode 核销一枚未使用的恢复码，恢复码错误或已被使用时返回 errRecoveryInvalid。
+func useRecoveryCode(tx *gorm.DB, userUUID uuid.UUID, code string) error {
+	update := tx.Model(&entity.UserRecoveryCode{}).
+		Where("user_uuid = ? AND code_hash = ? AND used_at IS NULL", userUUID, hashRecoveryCode(code)).
+		Update("used_at", time.Now())
+	if update.Error != nil {
+		return update.Error
+	}
+	if update.RowsAffected == 0 {
+		return errRecoveryInvalid
+	}
+	return nil
+}
+
+// newRecoveryCode 生成一枚形如 "abcde-fghjk" 的恢复码。
+func newRecoveryCode() string {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		panic("生成随机数失败: " + err.Error())
+	}
+	for i, b := range buf {
+		buf[i] = recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)]
+	}
+	return string(buf[:5]) + "-" + string(buf[5:])
+}
+
+// hashRecoveryCode 计算恢复码的哈希值，忽略大小写、空白与分隔符。
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,554 @@
+package logic
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/internal/models/request"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/secret"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// userPageSize 是搜索用户时未指定数量的默认每页数量。
+	userPageSize = 20
+	// userRecentLogins 是用户详情中展示的最近登录记录数量。
+	userRecentLogins = 20
+	// userExportBatchSize 是导出用户时每批查询的用户数量。
+	userExportBatchSize = 500
+)
+
+// phonePattern 匹配 E.164 格式的手机号，搜索关键字符合时按手机号盲索引精确匹配。
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
+// likeEscaper 转义 LIKE 模式中的通配符，使搜索关键字按字面匹配。
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// UserLogic 负责管理后台的用户管理：搜索、查看与导出用户，启用或停用账号、重置密码与删除账号。
+//
+// 操作者不能管理自己的账号；持有超级管理员角色的用户只能由超级管理员管理，且每个租户至少保留一位已启用的超级管理员。
+type UserLogic struct {
+	base
+}
+
+// NewUser 创建一个新的 UserLogic 实例。
+func NewUser(c *gin.Context) *UserLogic {
+	return &UserLogic{base: newBase(c)}
+}
+
+// Search 按关键字与状态搜索当前租户的用户，按注册时间倒序分页返回。
+//
+// 用户 UUID 为 UUIDv7，按 UUID 倒序即按注册时间倒序，游标为上一页最后一个用户的 UUID。
+func (u *UserLogic) Search(req *request.UserSearch) (*dto.UserPage, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = userPageSize
+	}
+
+	query := u.inTenant(u.db.Model(&entity.User{}))
+	if keyword := strings.TrimSpace(req.Query); keyword != "" {
+		pattern := "%" + likeEscaper.Replace(keyword) + "%"
+		conditions := u.db.Where("username ILIKE ?", pattern).
+			Or("email ILIKE ?", pattern).
+			Or("uuid IN (?)", u.db.Model(&entity.UserProfile{}).Select("user_uuid").Where("nickname ILIKE ?", pattern))
+		if phonePattern.MatchString(keyword) {
+			index, err := secret.BlindIndex(keyword)
+			if err != nil {
+				return nil, err
+			}
+			conditions = conditions.Or("phone_hash = ?", index)
+		}
+		query = query.Where(conditions)
+	}
+	switch req.Status {
+	case "active":
+		query = query.Where("is_active = ?", true)
+	case "inactive":
+		query = query.Where("is_active = ?", false)
+	}
+	if req.Cursor != nil {
+		query = query.Where("uuid < ?", *req.Cursor)
+	}
+
+	var users []*entity.User
+	if err := query.Order("uuid DESC").Limit(limit + 1).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	page := &dto.UserPage{}
+	if len(users) > limit {
+		users = users[:limit]
+		page.NextCursor = &users[limit-1].UUID
+	}
+	items, err := userDTOs(u.db, users)
+	if err != nil {
+		return nil, err
+	}
+	page.Items = items
+	return page, nil
+}
+
+// Get 查看当前租户中用户的详情，包括资料、直接分配的角色、分组、第三方身份绑定与最近的登录记录。
+func (u *UserLogic) Get(userUUID uuid.UUID) (*dto.UserDetail, error) {
+	user, err := findUser(u.inTenant(u.db), userUUID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, result.ErrNotFound.WithMessage("用户不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+	items, err := userDTOs(u.db, []*entity.User{user})
+	if err != nil {
+		return nil, err
+	}
+	detail := &dto.UserDetail{User: items[0]}
+
+	var profile entity.UserProfile
+	err = u.db.Where(&entity.UserProfile{UserUUID: user.UUID}).First(&profile).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		detail.Profile = profileDTO(&profile)
+	}
+
+	var userRoles []entity.UserRole
+	err = u.db.Preload("Role").
+		Where("user_uuid = ? AND is_active = ?", user.UUID, true).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("assigned_at DESC").
+		Find(&userRoles).Error
+	if err != nil {
+		return nil, err
+	}
+	detail.Roles = make([]*dto.UserRole, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		if userRole.Role == nil {
+			continue
+		}
+		detail.Roles = append(detail.Roles, &dto.UserRole{
+			RoleUUID:        userRole.RoleUUID,
+			ApplicationUUID: userRole.Role.ApplicationUUID,
+			Name:            userRole.Role.Name,
+			DisplayName:     userRole.Role.DisplayName,
+			AssignedAt:      userRole.AssignedAt,
+			ExpiresAt:       userRole.ExpiresAt,
+		})
+	}
+
+	groups, err := userGroups(u.db, user.UUID)
+	if err != nil {
+		return nil, err
+	}
+	detail.Groups = make([]*dto.UserGroup, 0, len(groups))
+	for _, group := range groups {
+		detail.Groups = append(detail.Groups, &dto.UserGroup{UUID: group.UUID, Name: group.Name, DisplayName: group.DisplayName})
+	}
+
+	if detail.Bindings, err = userBindings(u.db, user.UUID); err != nil {
+		return nil, err
+	}
+
+	var loginLogs []entity.LoginLog
+	err = u.db.Where("user_uuid = ?", user.UUID).Order("login_at DESC").Limit(userRecentLogins).Find(&loginLogs).Error
+	if err != nil {
+		return nil, err
+	}
+	detail.LoginLogs = make([]*dto.UserLoginLog, 0, len(loginLogs))
+	for _, loginLog := range loginLogs {
+		detail.LoginLogs = append(detail.LoginLogs, &dto.UserLoginLog{
+			UUID:          loginLog.UUID,
+			LoginType:     loginLog.LoginType,
+			IPAddress:     loginLog.IPAddress,
+			Client:        utility.ParseUserAgent(loginLog.UserAgent),
+			IsSuccess:     loginLog.IsSuccess,
+			FailureReason: loginLog.FailureReason,
+			LoginAt:       loginLog.LoginAt,
+		})
+	}
+	return detail, nil
+}
+
+// UpdateStatus 启用或停用账号。停用后用户在所有设备上退出登录，且不能再登录，直到重新启用。
+func (u *UserLogic) UpdateStatus(c *gin.Context, userUUID uuid.UUID, isActive bool) (*dto.User, error) {
+	var user *entity.User
+	changed := false
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if user, err = u.lockManageable(c, tx, userUUID, !isActive); err != nil {
+			return err
+		}
+		if user.IsActive == isActive {
+			return nil
+		}
+		if err := tx.Model(user).Update("is_active", isActive).Error; err != nil {
+			return err
+		}
+		changed = true
+		action := constants.AuditUserActivate
+		if !isActive {
+			action = constants.AuditUserDeactivate
+		}
+		return writeAudit(c, tx, action, constants.ResourceUser, &user.UUID, map[string]any{
+			"username": user.Username,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if changed && !isActive {
+		if _, err := u.signOutUser(c, user.UUID); err != nil {
+			return nil, err
+		}
+	}
+	items, err := userDTOs(u.db, []*entity.User{user})
+	if err != nil {
+		return nil, err
+	}
+	return items[0], nil
+}
+
+// SetPassword 由管理员为用户设置新密码，并使用户在所有设备上退出登录。
+//
+// 新密码同样需要满足密码策略；requireChange 为 true 时用户下次使用密码登录需要先修改密码。
+func (u *UserLogic) SetPassword(c *gin.Context, userUUID uuid.UUID, password string, requireChange bool) error {
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		user, err := u.lockManageable(c, tx, userUUID, false)
+		if err != nil {
+			return err
+		}
+		if err := u.setPassword(tx, user, password, requireChange); err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditUserPasswordSet, constants.ResourceUser, &user.UUID, map[string]any{
+			"username":       user.Username,
+			"require_change": requireChange,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	_, err = u.signOutUser(c, userUUID)
+	return err
+}
+
+// Delete 软删除账号，并使用户在所有设备上退出登录。
+//
+// 账号的第三方身份绑定与通行密钥一并删除，使这些身份可以绑定到其他账号；用户名、邮箱与手机号可以重新注册。
+func (u *UserLogic) Delete(c *gin.Context, userUUID uuid.UUID) error {
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		user, err := u.lockManageable(c, tx, userUUID, true)
+		if err != nil {
+			return err
+		}
+		credentials := []any{
+			&entity.UserThirdPartyWechat{},
+			&entity.UserThirdPartyGithub{},
+			&entity.UserThirdPartyQQ{},
+			&entity.UserExternalIdentity{},
+			&entity.UserWebAuthnCredential{},
+		}
+		for _, credential := range credentials {
+			if err := tx.Where("user_uuid = ?", user.UUID).Delete(credential).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(user).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditUserDelete, constants.ResourceUser, &user.UUID, map[string]any{
+			"username": user.Username,
+			"email":    user.Email,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	_, err = u.signOutUser(c, userUUID)
+	return err
+}
+
+// Export 导出当前租户的全部用户，导出文件的格式与导入文件相同，可以直接用于导入。
+//
+// 导出内容包括完整的手机号、资料与直接分配的全局角色；密码哈希只有超级管理员可以导出。
+// CSV 文件中可能被表格软件当作公式执行的单元格会加上单引号，JSON 文件原样保存全部值。
+func (u *UserLogic) Export(c *gin.Context, req *request.UserExport) ([]byte, error) {
+	if req.IncludePasswordHash && !slices.Contains(c.GetStringSlice(constants.ContextUserRoles), constants.RoleSuperAdmin) {
+		return nil, result.ErrForbidden.WithMessage("只有超级管理员可以导出密码哈希")
+	}
+	format := req.Format
+	if format == "" {
+		format = constants.ImportFormatCSV
+	}
+
+	var items []*dto.UserExport
+	var batch []*entity.User
+	err := u.inTenant(u.db).FindInBatches(&batch, userExportBatchSize, func(tx *gorm.DB, _ int) error {
+		exported, err := userExports(u.db, batch, req.IncludePasswordHash)
+		if err != nil {
+			return err
+		}
+		items = append(items, exported...)
+		return nil
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if format == constants.ImportFormatJSON {
+		if items == nil {
+			items = make([]*dto.UserExport, 0)
+		}
+		data, err = jsoniter.Marshal(items)
+	} else {
+		data, err = userExportCSV(items)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = writeAudit(c, u.db, constants.AuditUserExport, constants.ResourceUser, nil, map[string]any{
+		"format":                format,
+		"count":                 len(items),
+		"include_password_hash": req.IncludePasswordHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// lockManageable 锁定并返回当前租户中的用户，同时校验当前登录用户可以管理该用户。
+//
+// 操作者不能管理自己；持有超级管理员角色的用户只能由超级管理员管理，其余用户的有效角色（含所属分组的角色）
+// 均须是操作者可以分配的角色，以免操作者通过重置密码、停用或删除账号接管或处置权限高于自己的用户。
+// removing 为 true 表示操作会使用户失去管理能力（停用或删除），此时要求租户中还有其他超级管理员。
+func (b *base) lockManageable(c *gin.Context, tx *gorm.DB, userUUID uuid.UUID, removing bool) (*entity.User, error) {
+	if userUUID == currentUser(c).UUID {
+		return nil, result.ErrForbidden.WithMessage("不能在管理后台操作自己的账号")
+	}
+	user, err := findUser(b.inTenant(tx.Clauses(clause.Locking{Strength: "UPDATE"})), userUUID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, result.ErrNotFound.WithMessage("用户不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	roleUUIDs, _, err := effectiveRoleUUIDs(tx, user.UUID)
+	if err != nil {
+		return nil, err
+	}
+	var roles []*entity.Role
+	if len(roleUUIDs) > 0 {
+		if err := tx.Where("uuid IN ?", roleUUIDs).Find(&roles).Error; err != nil {
+			return nil, err
+		}
+	}
+	for _, role := range roles {
+		if role.Name != constants.RoleSuperAdmin || role.ApplicationUUID != nil {
+			continue
+		}
+		if !slices.Contains(c.GetStringSlice(constants.ContextUserRoles), constants.RoleSuperAdmin) {
+			return nil, result.ErrForbidden.WithMessage("只有超级管理员可以管理超级管理员的账号")
+		}
+		if removing && user.IsActive {
+			if err := checkOtherSuperAdmin(tx, b.tenant.UUID, user.UUID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, role := range roles {
+		if err := checkAssignable(c, tx, role); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// userDTOs 批量查询用户的昵称、头像与两步验证状态，并转换为管理后台的展示信息。
+func userDTOs(tx *gorm.DB, users []*entity.User) ([]*dto.User, error) {
+	userUUIDs := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		userUUIDs = append(userUUIDs, user.UUID)
+	}
+
+	profiles := make(map[uuid.UUID]*entity.UserProfile, len(users))
+	mfaEnabled := make(map[uuid.UUID]bool, len(users))
+	if len(userUUIDs) > 0 {
+		var userProfiles []*entity.UserProfile
+		if err := tx.Where("user_uuid IN ?", userUUIDs).Find(&userProfiles).Error; err != nil {
+			return nil, err
+		}
+		for _, profile := range userProfiles {
+			profiles[profile.UserUUID] = profile
+		}
+
+		var enabled []uuid.UUID
+		err := tx.Model(&entity.UserTOTP{}).Where("user_uuid IN ? AND is_enabled = ?", userUUIDs, true).Pluck("user_uuid", &enabled).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, userUUID := range enabled {
+			mfaEnabled[userUUID] = true
+		}
+	}
+
+	items := make([]*dto.User, 0, len(users))
+	for _, user := range users {
+		item := &dto.User{
+			UUID:            user.UUID,
+			Username:        user.Username,
+			Email:           user.Email,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			PhoneVerifiedAt: user.PhoneVerifiedAt,
+			IsActive:        user.IsActive,
+			HasPassword:     user.HasPassword(),
+			MFAEnabled:      mfaEnabled[user.UUID],
+			LastLoginAt:     user.LastLoginAt,
+			CreatedAt:       user.CreatedAt,
+		}
+		if user.Phone != nil {
+			phone := maskPhone(*user.Phone)
+			item.Phone = &phone
+		}
+		if profile, ok := profiles[user.UUID]; ok {
+			item.Nickname = profile.Nickname
+			item.Avatar = profile.Avatar
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// userExports 批量查询用户的资料与直接分配的有效全局角色，并转换为导出文件中的用户。
+func userExports(tx *gorm.DB, users []*entity.User, includePasswordHash bool) ([]*dto.UserExport, error) {
+	userUUIDs := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		userUUIDs = append(userUUIDs, user.UUID)
+	}
+
+	profiles := make(map[uuid.UUID]*entity.UserProfile, len(users))
+	roles := make(map[uuid.UUID][]string, len(users))
+	if len(userUUIDs) > 0 {
+		var userProfiles []*entity.UserProfile
+		if err := tx.Where("user_uuid IN ?", userUUIDs).Find(&userProfiles).Error; err != nil {
+			return nil, err
+		}
+		for _, profile := range userProfiles {
+			profiles[profile.UserUUID] = profile
+		}
+
+		var userRoles []*entity.UserRole
+		err := tx.Preload("Role").
+			Where("user_uuid IN ? AND is_active = ?", userUUIDs, true).
+			Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+			Find(&userRoles).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, userRole := range userRoles {
+			if userRole.Role == nil || userRole.Role.ApplicationUUID != nil {
+				continue
+			}
+			if !slices.Contains(roles[userRole.UserUUID], userRole.Role.Name) {
+				roles[userRole.UserUUID] = append(roles[userRole.UserUUID], userRole.Role.Name)
+			}
+		}
+	}
+
+	items := make([]*dto.UserExport, 0, len(users))
+	for _, user := range users {
+		item := &dto.UserExport{
+			Username:      user.Username,
+			Email:         user.Email,
+			EmailVerified: user.IsEmailVerified(),
+			Phone:         user.Phone,
+			PhoneVerified: user.IsPhoneVerified(),
+			IsActive:      user.IsActive,
+			Roles:         roles[user.UUID],
+		}
+		if item.Roles == nil {
+			item.Roles = make([]string, 0)
+		}
+		slices.Sort(item.Roles)
+		if includePasswordHash && user.HasPassword() {
+			item.PasswordHash = user.PasswordHash
+		}
+		if profile, ok := profiles[user.UUID]; ok {
+			item.Nickname = profile.Nickname
+			item.Gender = profile.Gender
+			item.Country = profile.Country
+			item.Province = profile.Province
+			item.City = profile.City
+			item.Bio = profile.Bio
+			if profile.Birthday != nil {
+				birthday := profile.Birthday.Format(time.DateOnly)
+				item.Birthday = &birthday
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// userExportCSV 将导出的用户编码为 CSV 文件，首行为列名，列顺序与 userImportColumns 一致。
+//
+// 以 "="、"+"、"-"、"@" 等字符开头的单元格会加上单引号，以免管理员用表格软件打开时被当作公式执行；导入时会去掉单引号。
+func userExportCSV(items []*dto.UserExport) ([]byte, error) {
+	var out bytes.Buffer
+	writer := csv.NewWriter(&out)
+	if err := writer.Write(userImportColumns); err != nil {
+		return nil, err
+	}
+	optional := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+	for _, item := range items {
+		record := []string{
+			item.Username,
+			optional(item.Email),
+			strconv.FormatBool(item.EmailVerified),
+			optional(item.Phone),
+			strconv.FormatBool(item.PhoneVerified),
+			strconv.FormatBool(item.IsActive),
+			optional(item.Nickname),
+			strconv.Itoa(item.Gender),
+			optional(item.Birthday),
+			optional(item.Country),
+			optional(item.Province),
+			optional(item.City),
+			optional(item.Bio),
+			strings.Join(item.Roles, importRoleSeparator),
+			optional(item.PasswordHash),
+		}
+		for index, value := range record {
+			record[index] = escapeCSVCell(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
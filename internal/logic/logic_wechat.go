@@ -0,0 +1,281 @@
+package logic
+
+import (
+	"errors"
+	"time"
+
+	xUtil "github.com/bamboo-services/bamboo-base-go/utility"
+	"github.com/bamboo-services/bamboo-sso/internal/models/dto"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/bamboo-services/bamboo-sso/pkg/thirdparty/wechat"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// WechatLogic 负责微信网站应用扫码登录、公众号网页授权与小程序登录。
+//
+// 三种方式分别对应代码为 wechat、wechat_mp、wechat_mini 的第三方提供商，
+// 同一个人在不同提供商下的 OpenID 不同，通过 UnionID 归并到同一个 User。
+type WechatLogic struct {
+	base
+}
+
+// NewWechat 创建一个新的 WechatLogic 实例。
+func NewWechat(c *gin.Context) *WechatLogic {
+	return &WechatLogic{base: newBase(c)}
+}
+
+// AuthorizeURL 生成扫码登录或公众号网页授权的跳转地址。
+//
+// 参数 providerCode 为 wechat 或 wechat_mp，为空时默认使用 wechat；
+// 参数 linkUser 不为空时，本次授权用于为该用户绑定微信而非登录。
+func (w *WechatLogic) AuthorizeURL(c *gin.Context, providerCode string, linkUser *uuid.UUID) (*dto.AuthorizeURL, error) {
+	if providerCode == "" {
+		providerCode = constants.ProviderWechat
+	}
+	if providerCode != constants.ProviderWechat && providerCode != constants.ProviderWechatMP {
+		return nil, result.ErrParameter.WithMessage("不支持的微信授权方式")
+	}
+
+	provider, err := w.enabledProvider(providerCode)
+	if err != nil {
+		return nil, err
+	}
+	state, err := w.newOAuthState(c, &oauthState{Provider: providerCode, LinkUser: linkUser})
+	if err != nil {
+		return nil, err
+	}
+
+	client := wechat.New(provider.ClientID, provider.ClientSecret)
+	return &dto.AuthorizeURL{
+		URL:   client.AuthorizeURL(provider.AuthURL, provider.RedirectURL, provider.Scope, state),
+		State: state,
+	}, nil
+}
+
+// Callback 处理扫码登录与公众号网页授权的回调。
+//
+// 由登录发起的授权会完成绑定或注册后签发令牌；由已登录用户发起的授权只为其绑定微信身份。
+func (w *WechatLogic) Callback(c *gin.Context, code, state string) (*dto.OAuthCallback, error) {
+	stateValue, err := w.consumeOAuthState(c, state)
+	if err != nil {
+		return nil, err
+	}
+	if stateValue.Provider != constants.ProviderWechat && stateValue.Provider != constants.ProviderWechatMP {
+		return nil, result.ErrParameter.WithMessage("授权状态与微信登录不匹配")
+	}
+	provider, err := w.enabledProvider(stateValue.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	client := wechat.New(provider.ClientID, provider.ClientSecret)
+	token, err := client.ExchangeCode(c, code)
+	if err != nil {
+		return nil, w.thirdPartyFailed(c, provider, err)
+	}
+	info, err := client.GetUserInfo(c, token.AccessToken, token.OpenID)
+	if err != nil {
+		return nil, w.thirdPartyFailed(c, provider, err)
+	}
+
+	expiresAt := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	account := &entity.UserThirdPartyWechat{
+		ProviderUUID:   provider.UUID,
+		OpenID:         token.OpenID,
+		UnionID:        utility.NilIfBlank(xUtil.DefaultIfBlank(info.UnionID, token.UnionID)),
+		Nickname:       utility.NilIfBlank(info.Nickname),
+		Avatar:         utility.NilIfBlank(info.HeadImgURL),
+		Gender:         info.Sex,
+		City:           utility.NilIfBlank(info.City),
+		Province:       utility.NilIfBlank(info.Province),
+		Country:        utility.NilIfBlank(info.Country),
+		Language:       utility.NilIfBlank(info.Language),
+		AccessToken:    utility.NilIfBlank(token.AccessToken),
+		RefreshToken:   utility.NilIfBlank(token.RefreshToken),
+		TokenExpiresAt: &expiresAt,
+	}
+
+	if stateValue.LinkUser != nil {
+		binding, err := w.link(c, provider, *stateValue.LinkUser, account)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.OAuthCallback{Action: constants.OAuthActionLink, Binding: binding}, nil
+	}
+	login, err := w.thirdPartyLogin(c, provider, func(tx *gorm.DB) (*entity.User, error) {
+		return w.bind(tx, account)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.OAuthCallback{Action: constants.OAuthActionLogin, Login: login}, nil
+}
+
+// MiniLogin 使用小程序 wx.login 获取的 code 完成登录。
+func (w *WechatLogic) MiniLogin(c *gin.Context, code string) (*dto.Login, error) {
+	provider, account, err := w.miniSession(c, code)
+	if err != nil {
+		return nil, err
+	}
+	return w.thirdPartyLogin(c, provider, func(tx *gorm.DB) (*entity.User, error) {
+		return w.bind(tx, account)
+	})
+}
+
+// MiniLink 使用小程序 wx.login 获取的 code 为当前登录用户绑定小程序身份。
+func (w *WechatLogic) MiniLink(c *gin.Context, code string) (*dto.Binding, error) {
+	provider, account, err := w.miniSession(c, code)
+	if err != nil {
+		return nil, err
+	}
+	return w.link(c, provider, currentUser(c).UUID, account)
+}
+
+// miniSession 调用 jscode2session 换取小程序身份。
+func (w *WechatLogic) miniSession(c *gin.Context, code string) (*entity.ThirdPartyProvider, *entity.UserThirdPartyWechat, error) {
+	provider, err := w.enabledProvider(constants.ProviderWechatMini)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := wechat.New(provider.ClientID, provider.ClientSecret)
+	session, err := client.JSCode2Session(c, code)
+	if err != nil {
+		return nil, nil, w.thirdPartyFailed(c, provider, err)
+	}
+
+	return provider, &entity.UserThirdPartyWechat{
+		ProviderUUID: provider.UUID,
+		OpenID:       session.OpenID,
+		UnionID:      utility.NilIfBlank(session.UnionID),
+		SessionKey:   utility.NilIfBlank(session.SessionKey),
+	}, nil
+}
+
+// bind 查找或创建微信身份对应的用户。
+//
+// 查找顺序：
+//   - 同一提供商下已存在相同 OpenID 的绑定，则刷新其资料与令牌并返回绑定的用户。
+//   - 当前租户中存在相同 UnionID 的其他绑定（如同一人先用小程序登录过），则为该用户新增一条绑定。
+//   - 以上都不存在时，创建新用户并绑定。
+func (w *WechatLogic) bind(tx *gorm.DB, account *entity.UserThirdPartyWechat) (*entity.User, error) {
+	var exist entity.UserThirdPartyWechat
+	err := tx.Where(&entity.UserThirdPartyWechat{ProviderUUID: account.ProviderUUID, OpenID: account.OpenID}).First(&exist).Error
+	switch {
+	case err == nil:
+		user, err := findUser(tx, exist.UserUUID)
+		if err != nil {
+			return nil, err
+		}
+		if !exist.IsActive {
+			return user, errBindingInactive
+		}
+		now := time.Now()
+		account.UUID = exist.UUID
+		account.UserUUID = exist.UserUUID
+		account.LastLoginAt = &now
+		return user, tx.Model(&exist).Updates(account).Error
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	var userUUID uuid.UUID
+	if account.UnionID != nil {
+		var sameUnion entity.UserThirdPartyWechat
+		err := tx.Where(&entity.UserThirdPartyWechat{UnionID: account.UnionID}).
+			Where("provider_uuid IN (?)", tx.Model(&entity.ThirdPartyProvider{}).Select("uuid").Where("tenant_uuid = ?", w.tenant.UUID)).
+			First(&sameUnion).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		userUUID = sameUnion.UserUUID
+	}
+
+	var user *entity.User
+	if userUUID == uuid.Nil {
+		user, err = createThirdPartyUser(tx, w.tenant.UUID, "wx_", &entity.UserProfile{
+			Nickname: account.Nickname,
+			Avatar:   account.Avatar,
+			Gender:   account.Gender,
+			Country:  account.Country,
+			Province: account.Province,
+			City:     account.City,
+		})
+		if err != nil {
+			return nil, err
+		}
+		w.log.Named("WECHAT").Info("通过微信登录创建新用户", zap.String("user", user.UUID.String()))
+	} else if user, err = findUser(tx, userUUID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	account.UserUUID = user.UUID
+	account.LastLoginAt = &now
+	return user, tx.Create(account).Error
+}
+
+// link 为指定用户绑定微信身份并写入审计日志。
+//
+// 以下情况会拒绝绑定：
+//   - 该微信身份（OpenID 或 UnionID）已绑定到其他用户。
+//   - 该用户在同一提供商下已绑定了另一个微信身份。
+func (w *WechatLogic) link(c *gin.Context, provider *entity.ThirdPartyProvider, userUUID uuid.UUID, account *entity.UserThirdPartyWechat) (*dto.Binding, error) {
+	err := w.db.Transaction(func(tx *gorm.DB) error {
+		var exist entity.UserThirdPartyWechat
+		err := tx.Where(&entity.UserThirdPartyWechat{ProviderUUID: account.ProviderUUID, OpenID: account.OpenID}).First(&exist).Error
+		if err == nil {
+			if exist.UserUUID != userUUID {
+				return errBindingOccupied
+			}
+			account.UUID = exist.UUID
+			account.UserUUID = userUUID
+			account.FirstBindAt = exist.FirstBindAt
+			return tx.Model(&exist).Updates(account).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if account.UnionID != nil {
+			var occupied int64
+			if err := tx.Model(&entity.UserThirdPartyWechat{}).
+				Where("union_id = ? AND user_uuid <> ?", *account.UnionID, userUUID).
+				Count(&occupied).Error; err != nil {
+				return err
+			}
+			if occupied > 0 {
+				return errBindingOccupied
+			}
+		}
+		var bound int64
+		if err := tx.Model(&entity.UserThirdPartyWechat{}).
+			Where(&entity.UserThirdPartyWechat{UserUUID: userUUID, ProviderUUID: account.ProviderUUID}).
+			Count(&bound).Error; err != nil {
+			return err
+		}
+		if bound > 0 {
+			return errProviderBound
+		}
+
+		account.UserUUID = userUUID
+		if err := tx.Create(account).Error; err != nil {
+			return err
+		}
+		return writeAudit(c, tx, constants.AuditBindingLink, constants.ResourceWechat, &account.UUID, map[string]any{
+			"user_uuid": userUUID,
+			"provider":  provider.Code,
+			"open_id":   account.OpenID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wechatBinding(provider, account), nil
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// Auth 返回认证中间件，要求请求携带 "Authorization: Bearer <access_token>" 请求头。
+//
+// 校验通过后将当前用户、令牌与用户持有的有效角色名称、权限代码写入请求上下文，键名分别为
+// constants.ContextUser、constants.ContextUserToken、constants.ContextUserRoles 与 constants.ContextUserPermissions。
+func Auth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accessToken, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || accessToken == "" {
+			result.Fail(c, result.ErrUnauthorized)
+			return
+		}
+
+		identity, err := logic.NewToken(c).Authenticate(c, accessToken)
+		if err != nil {
+			result.Fail(c, err)
+			return
+		}
+
+		c.Set(constants.ContextUser, identity.User)
+		c.Set(constants.ContextUserToken, identity.Token)
+		c.Set(constants.ContextUserRoles, identity.Roles)
+		c.Set(constants.ContextUserPermissions, identity.Permissions)
+		c.Next()
+	}
+}
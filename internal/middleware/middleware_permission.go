@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission 返回权限校验中间件，要求当前登录用户的有效角色被授予指定的权限，
+// 如 RequirePermission(constants.PermissionUserRead)；超级管理员不受权限限制。
+//
+// 必须注册在 Auth 之后使用，权限取自 Auth 写入请求上下文的有效权限，不再查询数据库。
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles := c.GetStringSlice(constants.ContextUserRoles)
+		permissions := c.GetStringSlice(constants.ContextUserPermissions)
+		if !logic.HasPermission(roles, permissions, permission) {
+			result.Fail(c, result.ErrForbidden.WithMessage("没有执行该操作的权限"))
+			return
+		}
+		c.Next()
+	}
+}
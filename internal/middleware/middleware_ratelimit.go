@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit 返回限流中间件，按客户端 IP 对指定类别的接口计数。
+//
+// 参数 scope 为 constants 中定义的限流类别；超出限制时响应 429 并设置 Retry-After 响应头。
+// 按账号与接入应用的限流需要先解析请求体或完成应用认证，由各业务逻辑自行完成。
+func RateLimit(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := logic.NewRateLimit(c).Request(c, scope); err != nil {
+			result.Fail(c, err)
+			return
+		}
+		c.Next()
+	}
+}
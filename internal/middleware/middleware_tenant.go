@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/logic"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/result"
+	"github.com/gin-gonic/gin"
+)
+
+// Tenant 返回租户中间件，确定请求所属的租户并写入请求上下文，键名为 constants.ContextTenant。
+//
+// 路径中包含 ":tenant" 参数时按租户代码查找，否则按请求的 Host 匹配租户域名，都未匹配时使用默认租户；
+// 租户不存在或已停用时拒绝请求。必须注册在其他中间件之前，业务逻辑依赖请求上下文中的租户。
+func Tenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant, err := logic.ResolveTenant(c)
+		if err != nil {
+			result.Fail(c, err)
+			return
+		}
+
+		c.Set(constants.ContextTenant, tenant)
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApplicationAccess 表示接入应用的访问策略。
+//
+// 字段说明：
+//   - Policy: 访问策略，open 表示所有有效用户都可以登录。
+//   - Rules: 策略对应的授权规则，open 策略下为空。
+type ApplicationAccess struct {
+	Policy string        `json:"policy"`
+	Rules  []*AccessRule `json:"rules"`
+}
+
+// AccessRule 表示访问策略中的一条授权规则。
+//
+// 字段说明：
+//   - SubjectType: 授权对象的类型，role、user 或 group。
+//   - SubjectUUID: 授权对象的唯一标识符。
+//   - SubjectName: 授权对象的名称，即角色名称、用户名或分组名称。
+//   - CreatedAt: 规则的添加时间。
+type AccessRule struct {
+	SubjectType string    `json:"subject_type"`
+	SubjectUUID uuid.UUID `json:"subject_uuid"`
+	SubjectName string    `json:"subject_name"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Binding 表示用户已绑定的一个第三方身份。
+//
+// 字段说明：
+//   - UUID: 绑定记录的唯一标识符，解绑时使用。
+//   - ProviderUUID: 提供商的唯一标识符。
+//   - ProviderCode: 提供商代码。
+//   - ProviderName: 提供商名称。
+//   - Account: 第三方平台中的账号标识，如 OpenID、Github 用户名或 OIDC 的 sub。
+//   - Nickname: 第三方平台中的昵称。
+//   - Avatar: 第三方平台中的头像地址。
+//   - IsActive: 绑定是否激活。
+//   - FirstBindAt: 首次绑定时间。
+//   - LastLoginAt: 最后一次通过该身份登录的时间。
+type Binding struct {
+	UUID         uuid.UUID  `json:"uuid"`
+	ProviderUUID uuid.UUID  `json:"provider_uuid"`
+	ProviderCode string     `json:"provider_code"`
+	ProviderName string     `json:"provider_name"`
+	Account      string     `json:"account"`
+	Nickname     *string    `json:"nickname"`
+	Avatar       *string    `json:"avatar"`
+	IsActive     bool       `json:"is_active"`
+	FirstBindAt  time.Time  `json:"first_bind_at"`
+	LastLoginAt  *time.Time `json:"last_login_at"`
+}
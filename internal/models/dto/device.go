@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/google/uuid"
+)
+
+// Device 表示用户设备列表中的一条登录记录，对应一枚未撤销且未过期的用户令牌。
+//
+// 字段说明：
+//   - UUID: 令牌记录的唯一标识符，撤销登录设备时使用。
+//   - Application: 令牌签发给的接入应用名称，为空表示直接登录 SSO。
+//   - Client: 从 User-Agent 解析出的浏览器、操作系统与设备类型。
+//   - DeviceInfo: 客户端上报的设备信息。
+//   - IPAddress: 登录时的 IP 地址。
+//   - LastUsedAt: 令牌最近一次被使用的时间。
+//   - CreatedAt: 登录时间。
+//   - IsCurrent: 是否为当前请求使用的令牌。
+type Device struct {
+	UUID        uuid.UUID         `json:"uuid"`
+	Application *string           `json:"application"`
+	Client      utility.UserAgent `json:"client"`
+	DeviceInfo  *string           `json:"device_info"`
+	IPAddress   *string           `json:"ip_address"`
+	LastUsedAt  *time.Time        `json:"last_used_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+	IsCurrent   bool              `json:"is_current"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Group 表示管理后台查看的分组。
+//
+// 字段说明：
+//   - ParentUUID: 上级分组的唯一标识符，为空表示顶级分组。
+//   - Roles: 分组被直接分配的角色名称，按名称排序；成员同时持有上级分组的角色。
+//   - MemberCount: 直接加入该分组的用户数量，不含下级分组的成员。
+//   - 其余字段与 entity.Group 一致。
+type Group struct {
+	UUID        uuid.UUID  `json:"uuid"`
+	ParentUUID  *uuid.UUID `json:"parent_uuid"`
+	Name        string     `json:"name"`
+	DisplayName string     `json:"display_name"`
+	Description *string    `json:"description"`
+	Roles       []string   `json:"roles"`
+	MemberCount int64      `json:"member_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// GroupMember 表示直接加入某个分组的用户。
+//
+// 字段说明：
+//   - UserUUID: 用户的唯一标识符。
+//   - Username: 用户名。
+//   - AddedBy: 添加者的唯一标识符。
+//   - CreatedAt: 加入分组的时间。
+type GroupMember struct {
+	UserUUID  uuid.UUID  `json:"user_uuid"`
+	Username  string     `json:"username"`
+	AddedBy   *uuid.UUID `json:"added_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
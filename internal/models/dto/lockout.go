@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lockout 表示管理后台查看的账号锁定状态。
+//
+// 字段说明：
+//   - UserUUID: 被锁定用户的唯一标识符。
+//   - Username: 被锁定用户的用户名。
+//   - Level: 近期连续被锁定的次数，决定本次锁定的时长。
+//   - LockedUntil: 锁定的截止时间。
+type Lockout struct {
+	UserUUID    uuid.UUID `json:"user_uuid"`
+	Username    string    `json:"username"`
+	Level       int       `json:"level"`
+	LockedUntil time.Time `json:"locked_until"`
+}
@@ -0,0 +1,55 @@
+package dto
+
+import "time"
+
+// Login 表示登录第一步验证通过后的结果。
+//
+// 不需要两步验证时返回签发的令牌；需要两步验证时返回挑战，客户端完成第二步后再获得令牌；
+// 使用密码登录且密码必须修改时返回修改密码的挑战，客户端设置新密码后再获得令牌。
+// RecoveryCodes 仅在登录过程中首次启用两步验证时返回，明文只展示这一次。
+type Login struct {
+	Token          *Token             `json:"token,omitempty"`
+	MFA            *MFAChallenge      `json:"mfa,omitempty"`
+	PasswordChange *PasswordChallenge `json:"password_change,omitempty"`
+	RecoveryCodes  []string           `json:"recovery_codes,omitempty"`
+}
+
+// PasswordChallenge 表示登录时等待完成的修改密码挑战。
+//
+// 字段说明：
+//   - Token: 挑战令牌，提交新密码时携带。
+//   - Reason: 必须修改密码的原因："required" 表示初始或由管理员设置的密码，"expired" 表示密码已过期。
+//   - ExpiresIn: 挑战的剩余有效秒数。
+type PasswordChallenge struct {
+	Token     string `json:"token"`
+	Reason    string `json:"reason"`
+	ExpiresIn int64  `json:"expires_in"`
+}
+
+// MFAChallenge 表示等待完成的两步验证挑战。
+//
+// 字段说明：
+//   - Token: 挑战令牌，提交第二步验证时携带。
+//   - Methods: 可用的验证方式，如 "totp"、"recovery"、"passkey"。
+//   - EnrollRequired: 用户必须启用两步验证但尚未登记，需要先登记身份验证器。
+//   - ExpiresIn: 挑战的剩余有效秒数。
+type MFAChallenge struct {
+	Token          string   `json:"token"`
+	Methods        []string `json:"methods"`
+	EnrollRequired bool     `json:"enroll_required"`
+	ExpiresIn      int64    `json:"expires_in"`
+}
+
+// TOTPEnrollment 表示登记身份验证器所需的信息。
+type TOTPEnrollment struct {
+	Secret string `json:"secret"` // Base32 编码的共享密钥，供无法扫码时手动输入
+	URI    string `json:"uri"`    // otpauth 地址，通常由前端渲染为二维码
+}
+
+// TOTPStatus 表示用户两步验证的启用状态。
+type TOTPStatus struct {
+	Enabled            bool       `json:"enabled"`             // 是否已启用
+	Required           bool       `json:"required"`            // 是否因持有管理角色而必须启用
+	ConfirmedAt        *time.Time `json:"confirmed_at"`        // 启用时间
+	RecoveryCodesCount int64      `json:"recovery_codes_left"` // 剩余可用的恢复码数量
+}
@@ -0,0 +1,11 @@
+package dto
+
+// Logout 表示结束 SSO 会话的结果。
+//
+// 字段说明：
+//   - FrontchannelLogoutURIs: 接入应用的前端通道登出地址，需要由浏览器在隐藏的 iframe 中依次加载。
+//   - RedirectURI: 登出完成后浏览器跳转的地址，接入应用未指定时为空。
+type Logout struct {
+	FrontchannelLogoutURIs []string `json:"frontchannel_logout_uris"`
+	RedirectURI            string   `json:"redirect_uri,omitempty"`
+}
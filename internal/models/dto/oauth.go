@@ -0,0 +1,16 @@
+package dto
+
+// AuthorizeURL 表示第三方登录的授权跳转地址。
+type AuthorizeURL struct {
+	URL   string `json:"url"`   // 用户需要跳转的授权地址
+	State string `json:"state"` // 本次授权的 state，回调时原样带回
+}
+
+// OAuthCallback 表示第三方授权回调的处理结果。
+//
+// 由登录发起的授权返回登录结果（令牌或两步验证挑战）；由已登录用户发起的绑定返回新绑定的身份。
+type OAuthCallback struct {
+	Action string `json:"action"` // login 或 link
+	*Login
+	Binding *Binding `json:"binding,omitempty"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Passkey 表示用户已注册的一个通行密钥。
+//
+// 字段说明：
+//   - UUID: 通行密钥的唯一标识符，重命名与删除时使用。
+//   - Nickname: 用户为通行密钥设置的名称。
+//   - AAGUID: 身份验证器型号标识，前端可据此显示密码管理器的图标与名称。
+//   - Transports: 身份验证器支持的传输方式。
+//   - BackupEligible: 是否为可同步的通行密钥。
+//   - BackupState: 是否已同步备份。
+//   - LastUsedAt: 最后一次使用的时间。
+//   - CreatedAt: 注册时间。
+type Passkey struct {
+	UUID           uuid.UUID  `json:"uuid"`
+	Nickname       string     `json:"nickname"`
+	AAGUID         uuid.UUID  `json:"aaguid"`
+	Transports     []string   `json:"transports"`
+	BackupEligible bool       `json:"backup_eligible"`
+	BackupState    bool       `json:"backup_state"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PasskeyCeremony 表示一次通行密钥注册或登录流程的起始数据。
+//
+// Options 原样传给浏览器的 navigator.credentials.create 或 navigator.credentials.get；
+// 完成流程时需要携带 Token 与浏览器返回的凭据。
+type PasskeyCeremony struct {
+	Token   string `json:"token"`
+	Options any    `json:"options"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Provider 表示管理后台查看的第三方提供商配置。
+//
+// 字段说明：
+//   - HasClientSecret: 是否已配置客户端密钥，密钥本身只写不读，不会返回。
+//   - ClaimMapping: 通用驱动的声明映射，未配置时为空。
+//   - 其余字段与 entity.ThirdPartyProvider 一致。
+type Provider struct {
+	UUID            uuid.UUID         `json:"uuid"`
+	Name            string            `json:"name"`
+	Code            string            `json:"code"`
+	Type            string            `json:"type"`
+	Issuer          *string           `json:"issuer"`
+	ClientID        string            `json:"client_id"`
+	HasClientSecret bool              `json:"has_client_secret"`
+	AuthURL         string            `json:"auth_url"`
+	TokenURL        string            `json:"token_url"`
+	UserInfoURL     string            `json:"user_info_url"`
+	Scope           string            `json:"scope"`
+	RedirectURL     string            `json:"redirect_url"`
+	ClaimMapping    map[string]string `json:"claim_mapping"`
+	IsEnabled       bool              `json:"is_enabled"`
+	SortOrder       int               `json:"sort_order"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// PublicProvider 表示登录页展示的已启用第三方登录方式。
+type PublicProvider struct {
+	Name string `json:"name"` // 提供商名称
+	Code string `json:"code"` // 提供商代码，用于拼接授权地址
+	Type string `json:"type"` // 提供商驱动类型
+}
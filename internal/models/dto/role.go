@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role 表示管理后台查看的角色。
+//
+// 字段说明：
+//   - ApplicationUUID: 角色所属的接入应用，为空表示全局角色。
+//   - IsBuiltin: 是否为系统内置角色，内置角色不能删除或改名。
+//   - Permissions: 角色被授予的权限代码，按代码排序。
+//   - MemberCount: 直接持有该角色且未过期的用户数量，不含通过分组持有的用户。
+//   - 其余字段与 entity.Role 一致。
+type Role struct {
+	UUID            uuid.UUID  `json:"uuid"`
+	ApplicationUUID *uuid.UUID `json:"application_uuid"`
+	Name            string     `json:"name"`
+	DisplayName     string     `json:"display_name"`
+	Description     *string    `json:"description"`
+	IsBuiltin       bool       `json:"is_builtin"`
+	Permissions     []string   `json:"permissions"`
+	MemberCount     int64      `json:"member_count"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// RoleMember 表示持有某个角色的用户。
+//
+// 字段说明：
+//   - UserUUID: 用户的唯一标识符。
+//   - Username: 用户名。
+//   - AssignedBy: 分配者的唯一标识符，系统分配时为空。
+//   - AssignedAt: 分配时间。
+//   - ExpiresAt: 过期时间，为空表示永不过期。
+type RoleMember struct {
+	UserUUID   uuid.UUID  `json:"user_uuid"`
+	Username   string     `json:"username"`
+	AssignedBy *uuid.UUID `json:"assigned_by"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tenant 表示管理后台查看的租户。
+//
+// 字段说明：
+//   - IsDefault: 是否为默认租户。
+//   - Issuer: 租户实际使用的签发者标识，未单独配置时由 SSO 的签发者加租户路径组成。
+//   - 其余字段与 entity.Tenant 一致。
+type Tenant struct {
+	UUID      uuid.UUID `json:"uuid"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Domain    *string   `json:"domain"`
+	Issuer    string    `json:"issuer"`
+	IsDefault bool      `json:"is_default"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PublicTenant 表示登录页展示的当前租户信息。
+type PublicTenant struct {
+	Code   string `json:"code"`   // 租户代码
+	Name   string `json:"name"`   // 租户名称
+	Issuer string `json:"issuer"` // 租户的签发者标识
+}
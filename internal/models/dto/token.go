@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/google/uuid"
+
+// Token 表示登录成功后返回给客户端的令牌信息。
+//
+// 字段说明：
+//   - UserUUID: 登录用户的唯一标识符。
+//   - AccessToken: 访问令牌。
+//   - RefreshToken: 刷新令牌。
+//   - TokenType: 令牌类型，固定为 "Bearer"。
+//   - ExpiresIn: 访问令牌剩余有效秒数。
+//   - SessionID: 令牌所属的 SSO 会话ID，仅签发给接入应用时返回，用于匹配登出令牌中的 sid。
+//   - Roles: 用户持有的属于该接入应用的有效角色名称，仅签发给接入应用时返回。
+//   - Groups: 用户所属的全部分组名称（含上级分组），仅签发给接入应用时返回。
+type Token struct {
+	UserUUID     uuid.UUID `json:"user_uuid"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int64     `json:"expires_in"`
+	SessionID    string    `json:"sid,omitempty"`
+	Roles        []string  `json:"roles,omitempty"`
+	Groups       []string  `json:"groups,omitempty"`
+}
@@ -0,0 +1,186 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"github.com/google/uuid"
+)
+
+// User 表示管理后台用户列表中的一个用户。
+//
+// 字段说明：
+//   - Phone: 脱敏后的手机号，如 "+86****5678"。
+//   - HasPassword: 是否设置了登录密码，仅通过第三方登录的用户没有密码。
+//   - MFAEnabled: 是否已启用 TOTP 两步验证。
+//   - 其余字段与 entity.User 及 entity.UserProfile 一致。
+type User struct {
+	UUID            uuid.UUID  `json:"uuid"`
+	Username        string     `json:"username"`
+	Email           *string    `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	Phone           *string    `json:"phone"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at"`
+	Nickname        *string    `json:"nickname"`
+	Avatar          *string    `json:"avatar"`
+	IsActive        bool       `json:"is_active"`
+	HasPassword     bool       `json:"has_password"`
+	MFAEnabled      bool       `json:"mfa_enabled"`
+	LastLoginAt     *time.Time `json:"last_login_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// UserPage 表示一页用户搜索结果。
+//
+// 字段说明：
+//   - Items: 本页的用户，按注册时间倒序排列。
+//   - NextCursor: 获取下一页时传入的游标，为空表示没有更多结果。
+type UserPage struct {
+	Items      []*User    `json:"items"`
+	NextCursor *uuid.UUID `json:"next_cursor"`
+}
+
+// UserDetail 表示管理后台查看的用户详情。
+//
+// 字段说明：
+//   - User: 用户的基础信息。
+//   - Profile: 用户的详细资料，未填写时为空。
+//   - Roles: 直接分配给用户且未过期的角色，不含通过分组持有的角色。
+//   - Groups: 用户直接加入的分组。
+//   - Bindings: 用户绑定的第三方身份。
+//   - LoginLogs: 最近的登录记录，按登录时间倒序排列。
+type UserDetail struct {
+	User      *User           `json:"user"`
+	Profile   *UserProfile    `json:"profile"`
+	Roles     []*UserRole     `json:"roles"`
+	Groups    []*UserGroup    `json:"groups"`
+	Bindings  []*Binding      `json:"bindings"`
+	LoginLogs []*UserLoginLog `json:"login_logs"`
+}
+
+// UserProfile 表示用户的详细资料，字段与 entity.UserProfile 一致。
+type UserProfile struct {
+	Nickname *string    `json:"nickname"`
+	Avatar   *string    `json:"avatar"`
+	Gender   int        `json:"gender"`
+	Birthday *time.Time `json:"birthday"`
+	Country  *string    `json:"country"`
+	Province *string    `json:"province"`
+	City     *string    `json:"city"`
+	Bio      *string    `json:"bio"`
+}
+
+// UserRole 表示直接分配给用户的一个角色。
+//
+// 字段说明：
+//   - RoleUUID: 角色的唯一标识符。
+//   - ApplicationUUID: 角色所属的接入应用，为空表示全局角色。
+//   - Name: 角色名称。
+//   - DisplayName: 角色显示名称。
+//   - AssignedAt: 分配时间。
+//   - ExpiresAt: 过期时间，为空表示永不过期。
+type UserRole struct {
+	RoleUUID        uuid.UUID  `json:"role_uuid"`
+	ApplicationUUID *uuid.UUID `json:"application_uuid"`
+	Name            string     `json:"name"`
+	DisplayName     string     `json:"display_name"`
+	AssignedAt      time.Time  `json:"assigned_at"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+}
+
+// UserGroup 表示用户直接加入的一个分组。
+type UserGroup struct {
+	UUID        uuid.UUID `json:"uuid"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"display_name"`
+}
+
+// UserLoginLog 表示用户的一条登录记录。
+//
+// 字段说明：
+//   - LoginType: 登录方式，如 "password"、"sms"、"passkey"。
+//   - IPAddress: 登录时的 IP 地址。
+//   - Client: 从 User-Agent 解析出的浏览器、操作系统与设备类型。
+//   - IsSuccess: 是否登录成功。
+//   - FailureReason: 登录失败的原因。
+//   - LoginAt: 登录时间。
+type UserLoginLog struct {
+	UUID          uuid.UUID         `json:"uuid"`
+	LoginType     string            `json:"login_type"`
+	IPAddress     string            `json:"ip_address"`
+	Client        utility.UserAgent `json:"client"`
+	IsSuccess     bool              `json:"is_success"`
+	FailureReason *string           `json:"failure_reason"`
+	LoginAt       time.Time         `json:"login_at"`
+}
+
+// UserImportJob 表示批量导入用户任务的进度与结果。
+//
+// 字段说明：
+//   - Status: 任务状态，pending、running、completed 或 failed。
+//   - Total: 文件中的用户总数。
+//   - Processed: 已处理的用户数，与 Total 一起用于展示进度。
+//   - Succeeded: 导入成功的用户数。
+//   - Failed: 导入失败的用户数。
+//   - Message: 任务中断的原因，任务正常结束时为空。
+//   - 其余字段与 entity.UserImportJob 一致。
+type UserImportJob struct {
+	UUID       uuid.UUID  `json:"uuid"`
+	FileName   string     `json:"file_name"`
+	Format     string     `json:"format"`
+	Status     string     `json:"status"`
+	Total      int        `json:"total"`
+	Processed  int        `json:"processed"`
+	Succeeded  int        `json:"succeeded"`
+	Failed     int        `json:"failed"`
+	Message    *string    `json:"message"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// UserImportJobDetail 表示导入任务的详情。
+//
+// 字段说明：
+//   - Job: 任务的进度与结果。
+//   - Errors: 导入失败的行，按行号排列。
+type UserImportJobDetail struct {
+	Job    *UserImportJob     `json:"job"`
+	Errors []*UserImportError `json:"errors"`
+}
+
+// UserImportError 表示导入失败的一行。
+//
+// 字段说明：
+//   - Row: 行号：CSV 文件为文件中的行号（列名行为第 1 行），JSON 文件为数组下标加 1。
+//   - Username: 该行的用户名，无法读取时为空。
+//   - Message: 导入失败的原因。
+type UserImportError struct {
+	Row      int     `json:"row"`
+	Username *string `json:"username"`
+	Message  string  `json:"message"`
+}
+
+// UserExport 表示导出文件中的一个用户，字段与导入文件相同，导出的文件可以直接用于导入。
+//
+// 字段说明：
+//   - Roles: 直接分配给用户且未过期的全局角色名称，按名称排序。
+//   - PasswordHash: 密码哈希，只在要求导出密码哈希时填写。
+//   - 其余字段与 entity.User 及 entity.UserProfile 一致，Birthday 的格式为 "2006-01-02"。
+type UserExport struct {
+	Username      string   `json:"username"`
+	Email         *string  `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Phone         *string  `json:"phone"`
+	PhoneVerified bool     `json:"phone_verified"`
+	IsActive      bool     `json:"is_active"`
+	Nickname      *string  `json:"nickname"`
+	Gender        int      `json:"gender"`
+	Birthday      *string  `json:"birthday"`
+	Country       *string  `json:"country"`
+	Province      *string  `json:"province"`
+	City          *string  `json:"city"`
+	Bio           *string  `json:"bio"`
+	Roles         []string `json:"roles"`
+	PasswordHash  *string  `json:"password_hash,omitempty"`
+}
@@ -10,39 +10,49 @@ import (
 //
 // 字段说明：
 //   - UUID: 应用的唯一标识符，由 UUID 表示。
+//   - TenantUUID: 应用所属的租户UUID，只有该租户的用户可以登录该应用。
 //   - Name: 应用名称。
 //   - Description: 应用描述信息。
-//   - ApplicationID: 应用标识符，分发给客户端用于身份识别。
+//   - ApplicationID: 应用标识符，分发给客户端用于身份识别，在全部租户之间唯一。
 //   - ApplicationSecret: 应用密钥，用于验证客户端身份。
 //   - RedirectURIs: 允许的回调地址列表，JSON数组格式。
 //   - AllowedOrigins: 允许的来源域名列表，JSON数组格式。
+//   - FrontchannelLogoutURI: 前端通道登出地址，用户退出 SSO 时由浏览器在隐藏的 iframe 中访问。
+//   - BackchannelLogoutURI: 后端通道登出地址，用户退出 SSO 时由服务端提交签名的登出令牌。
 //   - LogoURL: 应用Logo地址。
 //   - HomepageURL: 应用主页地址。
 //   - PrivacyPolicyURL: 隐私政策地址。
 //   - TermsOfServiceURL: 服务条款地址。
+//   - AccessPolicy: 访问策略，决定哪些用户可以登录该应用，取值见 constants.AccessPolicyOpen 等常量。
 //   - IsActive: 应用是否激活，默认为 true。
 //   - CreatedBy: 创建者UUID。
 //   - CreatedAt: 创建记录的时间戳。
 //   - UpdatedAt: 最后更新时间戳。
 type Application struct {
-	UUID              uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:应用唯一标识符"`
-	Name              string     `json:"name" gorm:"type:varchar(100);not null;comment:应用名称"`
-	Description       *string    `json:"description" gorm:"type:text;comment:应用描述"`
-	ApplicationID     string     `json:"application_id" gorm:"type:varchar(50);not null;uniqueIndex;comment:应用标识符"`
-	ApplicationSecret string     `json:"-" gorm:"type:varchar(255);not null;comment:应用密钥"`
-	RedirectURIs      *string    `json:"redirect_uris" gorm:"type:jsonb;comment:允许的回调地址(JSON数组)"`
-	AllowedOrigins    *string    `json:"allowed_origins" gorm:"type:jsonb;comment:允许的来源域名(JSON数组)"`
-	LogoURL           *string    `json:"logo_url" gorm:"type:varchar(500);comment:应用Logo地址"`
-	HomepageURL       *string    `json:"homepage_url" gorm:"type:varchar(500);comment:应用���页地址"`
-	PrivacyPolicyURL  *string    `json:"privacy_policy_url" gorm:"type:varchar(500);comment:隐私政策地址"`
-	TermsOfServiceURL *string    `json:"terms_of_service_url" gorm:"type:varchar(500);comment:服务条款地址"`
-	IsActive          bool       `json:"is_active" gorm:"type:boolean;not null;default:true;comment:是否激活"`
-	CreatedBy         *uuid.UUID `json:"created_by" gorm:"type:uuid;comment:创建者UUID"`
-	CreatedAt         time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
-	UpdatedAt         time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+	UUID                  uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:应用唯一标识符"`
+	TenantUUID            uuid.UUID  `json:"tenant_uuid" gorm:"type:uuid;index;comment:所属租户UUID"`
+	Name                  string     `json:"name" gorm:"type:varchar(100);not null;comment:应用名称"`
+	Description           *string    `json:"description" gorm:"type:text;comment:应用描述"`
+	ApplicationID         string     `json:"application_id" gorm:"type:varchar(50);not null;uniqueIndex;comment:应用标识符"`
+	ApplicationSecret     string     `json:"-" gorm:"type:varchar(255);not null;comment:应用密钥"`
+	RedirectURIs          *string    `json:"redirect_uris" gorm:"type:jsonb;comment:允许的回调地址(JSON数组)"`
+	AllowedOrigins        *string    `json:"allowed_origins" gorm:"type:jsonb;comment:允许的来源域名(JSON数组)"`
+	FrontchannelLogoutURI *string    `json:"frontchannel_logout_uri" gorm:"type:varchar(500);comment:前端通道登出地址"`
+	BackchannelLogoutURI  *string    `json:"backchannel_logout_uri" gorm:"type:varchar(500);comment:后端通道登出地址"`
+	LogoURL               *string    `json:"logo_url" gorm:"type:varchar(500);comment:应用Logo地址"`
+	HomepageURL           *string    `json:"homepage_url" gorm:"type:varchar(500);comment:应用���页地址"`
+	PrivacyPolicyURL      *string    `json:"privacy_policy_url" gorm:"type:varchar(500);comment:隐私政策地址"`
+	TermsOfServiceURL     *string    `json:"terms_of_service_url" gorm:"type:varchar(500);comment:服务条款地址"`
+	AccessPolicy          string     `json:"access_policy" gorm:"type:varchar(20);not null;default:open;comment:访问策略"`
+	IsActive              bool       `json:"is_active" gorm:"type:boolean;not null;default:true;comment:是否激活"`
+	CreatedBy             *uuid.UUID `json:"created_by" gorm:"type:uuid;comment:创建者UUID"`
+	CreatedAt             time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt             time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
 
 	// 关联关系
+	Tenant             *Tenant              `json:"tenant,omitempty" gorm:"foreignKey:TenantUUID;references:UUID;comment:所属租户"`
 	AuthorizationCodes []*AuthorizationCode `json:"authorization_codes,omitempty" gorm:"foreignKey:ApplicationUUID;references:UUID;constraint:OnDelete:CASCADE;comment:授权码"`
+	AccessRules        []*ApplicationAccess `json:"access_rules,omitempty" gorm:"foreignKey:ApplicationUUID;references:UUID;constraint:OnDelete:CASCADE;comment:访问规则"`
 	Creator            *User                `json:"creator,omitempty" gorm:"foreignKey:CreatedBy;references:UUID;comment:创建者"`
 }
 
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ApplicationAccess 表示接入应用访问策略中的一条授权规则，决定哪些用户可以登录该应用。
+//
+// 字段说明：
+//   - UUID: 规则的唯一标识符，由 UUID 表示。
+//   - ApplicationUUID: 规则所属的接入应用UUID，外键。
+//   - SubjectType: 授权对象的类型，取值见 constants.AccessSubjectRole 等常量。
+//   - SubjectUUID: 授权对象的UUID，按 SubjectType 指向角色、用户或分组；同一应用不能重复授权同一对象。
+//   - CreatedBy: 添加规则的管理员UUID。
+//   - CreatedAt: 创建记录的时间戳。
+type ApplicationAccess struct {
+	UUID            uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:访问规则唯一标识符"`
+	ApplicationUUID uuid.UUID  `json:"application_uuid" gorm:"type:uuid;not null;uniqueIndex:idx_application_access_subject;comment:关联应用UUID"`
+	SubjectType     string     `json:"subject_type" gorm:"type:varchar(20);not null;uniqueIndex:idx_application_access_subject;comment:授权对象类型"`
+	SubjectUUID     uuid.UUID  `json:"subject_uuid" gorm:"type:uuid;not null;uniqueIndex:idx_application_access_subject;index;comment:授权对象UUID"`
+	CreatedBy       *uuid.UUID `json:"created_by" gorm:"type:uuid;comment:添加者UUID"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+
+	// 关联关系
+	Application *Application `json:"application,omitempty" gorm:"foreignKey:ApplicationUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联应用"`
+}
+
+// BeforeCreate 在创建 ApplicationAccess 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (aa *ApplicationAccess) BeforeCreate(_ *gorm.DB) (err error) {
+	if aa.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		aa.UUID = newUUID
+	}
+	return
+}
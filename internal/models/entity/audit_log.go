@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// AuditLog 表示审计日志实体，记录用户与管理员对账号、绑定及系统配置所做的变更。
+//
+// 字段说明：
+//   - UUID: 审计日志的唯一标识符，由 UUID 表示。
+//   - ActorUUID: 操作者UUID，系统任务触发时为空。
+//   - Action: 操作类型，如 "binding.link"、"binding.unlink"。
+//   - ResourceType: 被操作资源的类型，如 "wechat"、"external_identity"。
+//   - ResourceUUID: 被操作资源的UUID。
+//   - Detail: 操作详情，JSON 对象格式。
+//   - IPAddress: 操作者的 IP 地址。
+//   - UserAgent: 操作者的用户代理信息。
+//   - CreatedAt: 创建记录的时间戳。
+type AuditLog struct {
+	UUID         uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:审计日志唯一标识符"`
+	ActorUUID    *uuid.UUID `json:"actor_uuid" gorm:"type:uuid;index;comment:操作者UUID"`
+	Action       string     `json:"action" gorm:"type:varchar(50);not null;index;comment:操作类型"`
+	ResourceType string     `json:"resource_type" gorm:"type:varchar(50);not null;comment:资源类型"`
+	ResourceUUID *uuid.UUID `json:"resource_uuid" gorm:"type:uuid;index;comment:资源UUID"`
+	Detail       *string    `json:"detail" gorm:"type:jsonb;comment:操作详情(JSON对象)"`
+	IPAddress    string     `json:"ip_address" gorm:"type:varchar(45);not null;comment:操作IP地址"`
+	UserAgent    string     `json:"user_agent" gorm:"type:text;not null;comment:操作User-Agent"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+
+	// 关联关系
+	Actor *User `json:"actor,omitempty" gorm:"foreignKey:ActorUUID;references:UUID;comment:操作者"`
+}
+
+// BeforeCreate 在创建 AuditLog 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (al *AuditLog) BeforeCreate(_ *gorm.DB) (err error) {
+	if al.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		al.UUID = newUUID
+	}
+	return
+}
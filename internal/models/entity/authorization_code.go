@@ -19,6 +19,8 @@ import (
 //   - Code: 授权码值，分发给客户端的实际码值。
 //   - UserUUID: 关联的用户UUID，外键。
 //   - ApplicationUUID: 关联的应用UUID，外键。
+//   - RedirectURI: 签发授权码时的回调地址，换取令牌时必须一致。
+//   - SessionID: 签发授权码时用户所在的 SSO 会话ID，换取的令牌归属于该会话。
 //   - UserAgent: 用户浏览器User-Agent字符串。
 //   - BrowserFingerprint: 浏览器指纹哈希值。
 //   - IPAddress: 用户IP地址。
@@ -33,6 +35,8 @@ type AuthorizationCode struct {
 	Code               string     `json:"code" gorm:"type:varchar(128);not null;uniqueIndex;comment:授权码值"`
 	UserUUID           uuid.UUID  `json:"user_uuid" gorm:"type:uuid;not null;index;comment:关联用户UUID"`
 	ApplicationUUID    uuid.UUID  `json:"application_uuid" gorm:"type:uuid;not null;index;comment:关联应用UUID"`
+	RedirectURI        string     `json:"redirect_uri" gorm:"type:varchar(500);not null;default:'';comment:回调地址"`
+	SessionID          *string    `json:"-" gorm:"type:varchar(64);comment:所属SSO会话ID"`
 	UserAgent          string     `json:"user_agent" gorm:"type:text;not null;comment:用户浏览器User-Agent"`
 	BrowserFingerprint string     `json:"browser_fingerprint" gorm:"type:varchar(128);not null;comment:浏览器指纹哈希"`
 	IPAddress          string     `json:"ip_address" gorm:"type:varchar(45);not null;comment:用户IP地址"`
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Group 表示用户分组实体，用于按团队组织用户，分组可以嵌套。
+//
+// 子分组的成员同时视为所有上级分组的成员；分组被分配的角色由其全部成员（含子分组成员）共同持有。
+//
+// 字段说明：
+//   - UUID: 分组的唯一标识符，由 UUID 表示。
+//   - TenantUUID: 分组所属的租户UUID，分组只能包含所属租户的用户。
+//   - ParentUUID: 上级分组UUID，为空表示顶级分组。
+//   - Name: 分组名称，在所属租户内唯一，会出现在签发给接入应用的令牌中。
+//   - DisplayName: 分组的显示名称。
+//   - Description: 分组的描述信息，可选字段。
+//   - CreatedAt: 创建记录的时间戳。
+//   - UpdatedAt: 最后更新时间戳。
+type Group struct {
+	UUID        uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:分组唯一标识符"`
+	TenantUUID  uuid.UUID  `json:"tenant_uuid" gorm:"type:uuid;uniqueIndex:idx_group_tenant_name;comment:所属租户UUID"`
+	ParentUUID  *uuid.UUID `json:"parent_uuid" gorm:"type:uuid;index;comment:上级分组UUID"`
+	Name        string     `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_group_tenant_name;comment:分组名称"`
+	DisplayName string     `json:"display_name" gorm:"type:varchar(100);not null;comment:分组显示名称"`
+	Description *string    `json:"description" gorm:"type:varchar(255);comment:分组描述信息"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+
+	// 关联关系
+	Parent *Group `json:"parent,omitempty" gorm:"foreignKey:ParentUUID;references:UUID;comment:上级分组"`
+}
+
+// BeforeCreate 在创建 Group 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (g *Group) BeforeCreate(_ *gorm.DB) (err error) {
+	if g.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		g.UUID = newUUID
+	}
+	return
+}
+
+// BeforeUpdate 在更新 Group 记录前自动更新 UpdatedAt 字段。
+func (g *Group) BeforeUpdate(_ *gorm.DB) (err error) {
+	g.UpdatedAt = time.Now()
+	return
+}
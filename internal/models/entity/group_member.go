@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// GroupMember 表示分组成员关联实体，记录用户直接所属的分组。
+//
+// 字段说明：
+//   - UUID: 关联记录的唯一标识符，由 UUID 表示。
+//   - GroupUUID: 关联的分组UUID，外键。
+//   - UserUUID: 关联的用户UUID，外键；同一用户不能重复加入同一分组。
+//   - AddedBy: 添加者UUID，记录是谁将用户加入分组。
+//   - CreatedAt: 创建记录的时间戳。
+type GroupMember struct {
+	UUID      uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:分组成员关联唯一标识符"`
+	GroupUUID uuid.UUID  `json:"group_uuid" gorm:"type:uuid;not null;uniqueIndex:idx_group_member;comment:关联分组UUID"`
+	UserUUID  uuid.UUID  `json:"user_uuid" gorm:"type:uuid;not null;uniqueIndex:idx_group_member;index;comment:关联用户UUID"`
+	AddedBy   *uuid.UUID `json:"added_by" gorm:"type:uuid;comment:添加者UUID"`
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+
+	// 关联关系
+	Group *Group `json:"group,omitempty" gorm:"foreignKey:GroupUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联分组"`
+	User  *User  `json:"user,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联用户"`
+}
+
+// BeforeCreate 在创建 GroupMember 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (gm *GroupMember) BeforeCreate(_ *gorm.DB) (err error) {
+	if gm.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		gm.UUID = newUUID
+	}
+	return
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// GroupRole 表示分组角色关联实体，分组的全部成员（含子分组成员）共同持有该角色。
+//
+// 字段说明：
+//   - UUID: 关联记录的唯一标识符，由 UUID 表示。
+//   - GroupUUID: 关联的分组UUID，外键。
+//   - RoleUUID: 关联的角色UUID，外键；同一分组不能重复关联同一角色。
+//   - AssignedBy: 分配者UUID，记录是谁分配的此角色。
+//   - CreatedAt: 创建记录的时间戳。
+type GroupRole struct {
+	UUID       uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:分组角色关联唯一标识符"`
+	GroupUUID  uuid.UUID  `json:"group_uuid" gorm:"type:uuid;not null;uniqueIndex:idx_group_role;comment:关联分组UUID"`
+	RoleUUID   uuid.UUID  `json:"role_uuid" gorm:"type:uuid;not null;uniqueIndex:idx_group_role;index;comment:关联角色UUID"`
+	AssignedBy *uuid.UUID `json:"assigned_by" gorm:"type:uuid;comment:分配者UUID"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+
+	// 关联关系
+	Group *Group `json:"group,omitempty" gorm:"foreignKey:GroupUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联分组"`
+	Role  *Role  `json:"role,omitempty" gorm:"foreignKey:RoleUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联角色"`
+}
+
+// BeforeCreate 在创建 GroupRole 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (gr *GroupRole) BeforeCreate(_ *gorm.DB) (err error) {
+	if gr.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		gr.UUID = newUUID
+	}
+	return
+}
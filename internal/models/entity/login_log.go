@@ -11,7 +11,7 @@ import (
 // 字段说明：
 //   - UUID: 日志记录的唯一标识符，由 UUID 表示。
 //   - UserUUID: 关联的用户UUID，外键（可为空，记录登录失败的情况）。
-//   - LoginType: 登录类型（password-密码登录，third_party-第三方登录）。
+//   - LoginType: 登录类型（password-密码登录，third_party-第三方登录，passkey-通行密钥登录，sms-短信验证码登录）。
 //   - ProviderUUID: 第三方提供商UUID，外键（第三方登录时使用）。
 //   - IPAddress: 登录IP地址。
 //   - UserAgent: 用户浏览器User-Agent字符串。
@@ -23,7 +23,7 @@ import (
 type LoginLog struct {
 	UUID               uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:登录日志唯一标识符"`
 	UserUUID           *uuid.UUID `json:"user_uuid" gorm:"type:uuid;index;comment:关联用户UUID(可为空)"`
-	LoginType          string     `json:"login_type" gorm:"type:varchar(20);not null;comment:登录类型(password/third_party)"`
+	LoginType          string     `json:"login_type" gorm:"type:varchar(20);not null;comment:登录类型(password/third_party/passkey/sms)"`
 	ProviderUUID       *uuid.UUID `json:"provider_uuid" gorm:"type:uuid;comment:第三方提供商UUID(第三方登录时)"`
 	IPAddress          string     `json:"ip_address" gorm:"type:varchar(45);not null;comment:登录IP地址"`
 	UserAgent          string     `json:"user_agent" gorm:"type:text;not null;comment:用户浏览器User-Agent"`
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Permission 表示系统中的权限实体，角色通过 RolePermission 关联权限，管理接口按权限代码校验访问。
+//
+// 字段说明：
+//   - UUID: 权限的唯一标识符，由 UUID 表示。
+//   - Code: 权限代码，格式为 "资源:操作"，如 "user:read"，必须唯一。
+//   - Name: 权限显示名称。
+//   - Category: 权限所属的资源分类，如 "user"，用于在管理后台分组展示。
+//   - Description: 权限的描述信息，可选字段。
+//   - CreatedAt: 创建记录的时间戳。
+//   - UpdatedAt: 最后更新时间戳。
+type Permission struct {
+	UUID        uuid.UUID `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:权限唯一标识符"`
+	Code        string    `json:"code" gorm:"type:varchar(100);not null;uniqueIndex;comment:权限代码"`
+	Name        string    `json:"name" gorm:"type:varchar(100);not null;comment:权限显示名称"`
+	Category    string    `json:"category" gorm:"type:varchar(50);not null;index;comment:权限分类"`
+	Description *string   `json:"description" gorm:"type:varchar(255);comment:权限描述信息"`
+	CreatedAt   time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+}
+
+// BeforeCreate 在创建 Permission 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (p *Permission) BeforeCreate(_ *gorm.DB) (err error) {
+	if p.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		p.UUID = newUUID
+	}
+	return
+}
+
+// BeforeUpdate 在更新 Permission 记录前自动更新 UpdatedAt 字段。
+func (p *Permission) BeforeUpdate(_ *gorm.DB) (err error) {
+	p.UpdatedAt = time.Now()
+	return
+}
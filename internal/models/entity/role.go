@@ -8,19 +8,30 @@ import (
 
 // Role 表示系统中的角色实体，用于定义权限和业务角色。
 //
+// 全局角色决定用户在 SSO 中的权限；属于接入应用的角色只由该应用解释，仅出现在签发给该应用的令牌中。
+//
 // 字段说明：
 //   - UUID: 角色的唯一标识符，由 UUID 表示。
-//   - Name: 角色名称，必须唯一。
+//   - TenantUUID: 角色所属的租户UUID，为空表示全部租户共用的内置角色。
+//   - ApplicationUUID: 角色所属的接入应用UUID，为空表示全局角色。
+//   - Name: 角色名称，全局角色在所属租户内唯一且不能与内置角色同名，应用角色在所属应用内唯一。
+//   - DisplayName: 角色的显示名称。
 //   - Description: 角色的描述信息，可选字段。
 //   - CreatedAt: 创建记录的时间戳。
 //   - UpdatedAt: 最后更新时间戳。
 type Role struct {
-	UUID        uuid.UUID `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:角色唯一标识符"`
-	Name        string    `json:"name" gorm:"type:varchar(50);not null;uniqueIndex;comment:角色名称"`
-	DisplayName string    `json:"display_name" gorm:"type:varchar(100);not null;comment:角色显示名称"`
-	Description *string   `json:"description" gorm:"type:varchar(255);comment:角色描述信息"`
-	CreatedAt   time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+	UUID            uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:角色唯一标识符"`
+	TenantUUID      *uuid.UUID `json:"tenant_uuid" gorm:"type:uuid;uniqueIndex:idx_role_tenant_name,where:application_uuid IS NULL;comment:所属租户UUID"`
+	ApplicationUUID *uuid.UUID `json:"application_uuid" gorm:"type:uuid;uniqueIndex:idx_role_application_name;comment:所属接入应用UUID"`
+	Name            string     `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_role_application_name;uniqueIndex:idx_role_tenant_name,where:application_uuid IS NULL;comment:角色名称"`
+	DisplayName     string     `json:"display_name" gorm:"type:varchar(100);not null;comment:角色显示名称"`
+	Description     *string    `json:"description" gorm:"type:varchar(255);comment:角色描述信息"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+
+	// 关联关系
+	Tenant      *Tenant      `json:"tenant,omitempty" gorm:"foreignKey:TenantUUID;references:UUID;comment:所属租户"`
+	Application *Application `json:"application,omitempty" gorm:"foreignKey:ApplicationUUID;references:UUID;constraint:OnDelete:CASCADE;comment:所属接入应用"`
 }
 
 // BeforeCreate 在创建 Role 记录前自动生成新的 UUID（如果当前 UUID 为空）。
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// RolePermission 表示角色权限关联实体，用于多对多关系映射。
+//
+// 字段说明：
+//   - UUID: 关联记录的唯一标识符，由 UUID 表示。
+//   - RoleUUID: 关联的角色UUID，外键。
+//   - PermissionUUID: 关联的权限UUID，外键；同一角色不能重复关联同一权限。
+//   - CreatedAt: 创建记录的时间戳。
+type RolePermission struct {
+	UUID           uuid.UUID `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:角色权限关联唯一标识符"`
+	RoleUUID       uuid.UUID `json:"role_uuid" gorm:"type:uuid;not null;uniqueIndex:idx_role_permission;comment:关联角色UUID"`
+	PermissionUUID uuid.UUID `json:"permission_uuid" gorm:"type:uuid;not null;uniqueIndex:idx_role_permission;index;comment:关联权限UUID"`
+	CreatedAt      time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+
+	// 关联关系
+	Role       *Role       `json:"role,omitempty" gorm:"foreignKey:RoleUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联角色"`
+	Permission *Permission `json:"permission,omitempty" gorm:"foreignKey:PermissionUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联权限"`
+}
+
+// BeforeCreate 在创建 RolePermission 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (rp *RolePermission) BeforeCreate(_ *gorm.DB) (err error) {
+	if rp.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		rp.UUID = newUUID
+	}
+	return
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Tenant 表示租户实体，用于隔离不同组织的用户、接入应用、角色与第三方登录提供商。
+//
+// 请求所属的租户依次按路径 "/t/:tenant" 中的租户代码、请求的 Host 与租户域名确定，都未匹配时使用默认租户。
+//
+// 字段说明：
+//   - UUID: 租户的唯一标识符，由 UUID 表示。
+//   - Code: 租户代码，必须唯一，出现在 "/t/:tenant" 路径与签发者标识中；默认租户的代码为 constants.TenantDefault。
+//   - Name: 租户名称。
+//   - Domain: 租户绑定的域名，请求的 Host 与之相同时使用该租户，可选字段。
+//   - Issuer: 租户的签发者标识，为空时由 SSO 的签发者加租户路径组成，可选字段。
+//   - IsActive: 租户是否启用，停用后该租户的全部请求都会被拒绝，默认为 true。
+//   - CreatedAt: 创建记录的时间戳。
+//   - UpdatedAt: 最后更新时间戳。
+type Tenant struct {
+	UUID      uuid.UUID `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:租户唯一标识符"`
+	Code      string    `json:"code" gorm:"type:varchar(50);not null;uniqueIndex;comment:租户代码"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null;comment:租户名称"`
+	Domain    *string   `json:"domain" gorm:"type:varchar(255);uniqueIndex;comment:租户域名"`
+	Issuer    *string   `json:"issuer" gorm:"type:varchar(500);comment:租户签发者标识"`
+	IsActive  bool      `json:"is_active" gorm:"type:boolean;not null;default:true;comment:是否启用"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+}
+
+// BeforeCreate 在创建 Tenant 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (t *Tenant) BeforeCreate(_ *gorm.DB) (err error) {
+	if t.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		t.UUID = newUUID
+	}
+	return
+}
+
+// BeforeUpdate 在更新 Tenant 记录前自动更新 UpdatedAt 字段。
+func (t *Tenant) BeforeUpdate(_ *gorm.DB) (err error) {
+	t.UpdatedAt = time.Now()
+	return
+}
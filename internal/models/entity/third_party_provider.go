@@ -10,39 +10,48 @@ import (
 //
 // 字段说明：
 //   - UUID: 提供商的唯一标识符，由 UUID 表示。
+//   - TenantUUID: 提供商所属的租户UUID，只在该租户的登录页中提供。
 //   - Name: 提供商名称，如 "QQ"、"微信"、"Github"。
-//   - Code: 提供商代码，用于程序识别，如 "qq"、"wechat"、"github"。
+//   - Code: 提供商代码，用于程序识别，在所属租户内唯一，如 "qq"、"wechat"、"github"。
+//   - Type: 提供商驱动类型，"oidc"、"oauth2" 为通用驱动，其余为专用驱动，如 "wechat"。
+//   - Issuer: OIDC 签发者地址，通用 OIDC 驱动据此进行服务发现。
 //   - ClientID: 第三方平台分配的客户端ID。
-//   - ClientSecret: 第三方平台分配的客户端密钥。
+//   - ClientSecret: 第三方平台分配的客户端密钥（加密存储）。
 //   - AuthURL: 授权地址。
 //   - TokenURL: 获取Token的地址。
 //   - UserInfoURL: 获取用户信息的地址。
 //   - Scope: 请求的权限范围。
 //   - RedirectURL: 回调地址。
+//   - ClaimMapping: 声明映射，JSON 对象格式，用于从通用驱动的用户信息中提取 subject、email 等字段。
 //   - IsEnabled: 是否启用该提供商，默认为 true。
 //   - SortOrder: 排序顺序，用于前端显示。
 //   - CreatedAt: 创建记录的时间戳。
 //   - UpdatedAt: 最后更新时间戳。
 type ThirdPartyProvider struct {
 	UUID         uuid.UUID `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:第三方提供商唯一标识符"`
+	TenantUUID   uuid.UUID `json:"tenant_uuid" gorm:"type:uuid;uniqueIndex:idx_provider_tenant_code;comment:所属租户UUID"`
 	Name         string    `json:"name" gorm:"type:varchar(50);not null;comment:提供商名称"`
-	Code         string    `json:"code" gorm:"type:varchar(30);not null;uniqueIndex;comment:提供商代码"`
+	Code         string    `json:"code" gorm:"type:varchar(30);not null;uniqueIndex:idx_provider_tenant_code;comment:提供商代码"`
+	Type         string    `json:"type" gorm:"type:varchar(20);not null;default:'oauth2';comment:驱动类型(oidc/oauth2/wechat等)"`
+	Issuer       *string   `json:"issuer" gorm:"type:varchar(500);comment:OIDC签发者地址"`
 	ClientID     string    `json:"client_id" gorm:"type:varchar(255);not null;comment:客户端ID"`
-	ClientSecret string    `json:"-" gorm:"type:varchar(255);not null;comment:客户端密钥"`
+	ClientSecret string    `json:"-" gorm:"type:text;not null;serializer:encrypted;comment:客户端密钥(加密)"`
 	AuthURL      string    `json:"auth_url" gorm:"type:varchar(500);not null;comment:授权地址"`
 	TokenURL     string    `json:"token_url" gorm:"type:varchar(500);not null;comment:Token地址"`
 	UserInfoURL  string    `json:"user_info_url" gorm:"type:varchar(500);not null;comment:用户信息地址"`
 	Scope        string    `json:"scope" gorm:"type:varchar(200);comment:权限范围"`
 	RedirectURL  string    `json:"redirect_url" gorm:"type:varchar(500);not null;comment:回调地址"`
+	ClaimMapping *string   `json:"claim_mapping" gorm:"type:jsonb;comment:声明映射(JSON对象)"`
 	IsEnabled    bool      `json:"is_enabled" gorm:"type:boolean;not null;default:true;comment:是否启用"`
 	SortOrder    int       `json:"sort_order" gorm:"type:integer;default:0;comment:排序顺序"`
 	CreatedAt    time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
 	UpdatedAt    time.Time `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
 
 	// 关联关系
-	WechatAccounts []*UserThirdPartyWechat `json:"wechat_accounts,omitempty" gorm:"foreignKey:ProviderUUID;references:UUID;constraint:OnDelete:CASCADE;comment:微信用户账号"`
-	GithubAccounts []*UserThirdPartyGithub `json:"github_accounts,omitempty" gorm:"foreignKey:ProviderUUID;references:UUID;constraint:OnDelete:CASCADE;comment:Github用户账号"`
-	QQAccounts     []*UserThirdPartyQQ     `json:"qq_accounts,omitempty" gorm:"foreignKey:ProviderUUID;references:UUID;constraint:OnDelete:CASCADE;comment:QQ用户账号"`
+	WechatAccounts     []*UserThirdPartyWechat `json:"wechat_accounts,omitempty" gorm:"foreignKey:ProviderUUID;references:UUID;constraint:OnDelete:CASCADE;comment:微信用户账号"`
+	GithubAccounts     []*UserThirdPartyGithub `json:"github_accounts,omitempty" gorm:"foreignKey:ProviderUUID;references:UUID;constraint:OnDelete:CASCADE;comment:Github用户账号"`
+	QQAccounts         []*UserThirdPartyQQ     `json:"qq_accounts,omitempty" gorm:"foreignKey:ProviderUUID;references:UUID;constraint:OnDelete:CASCADE;comment:QQ用户账号"`
+	ExternalIdentities []*UserExternalIdentity `json:"external_identities,omitempty" gorm:"foreignKey:ProviderUUID;references:UUID;constraint:OnDelete:CASCADE;comment:通用外部身份"`
 }
 
 // BeforeCreate 在创建 ThirdPartyProvider 记录前自动生成新的 UUID（如果当前 UUID 为空）。
@@ -10,33 +10,53 @@ import (
 //
 // 字段说明：
 //   - UUID: 用户的唯一标识符，由 UUID 表示。
-//   - Username: 用户名，必须唯一。
-//   - Email: 邮箱地址，必须唯一，可用于登录。
-//   - Phone: 手机号，可选字段。
-//   - PasswordHash: 加密后的密码哈希值。
+//   - TenantUUID: 用户所属的租户UUID，用户只能登录所属租户。
+//   - Username: 用户名，在所属租户未删除的用户中唯一。
+//   - Email: 邮箱地址，在所属租户未删除的用户中唯一，可用于登录；第三方登录创建的用户可为空。
+//   - EmailVerifiedAt: 邮箱验证通过的时间，为空表示尚未验证；修改邮箱后需要重新验证。
+//   - Phone: 手机号，可选字段（加密存储），格式为 E.164。
+//   - PhoneHash: 手机号的盲索引，用于按手机号查询用户与保证手机号在所属租户内唯一。
+//   - PhoneVerifiedAt: 手机号验证通过的时间，为空表示尚未验证；只有验证过的手机号可用于短信登录。
+//   - PasswordHash: 自描述格式的密码哈希，记录算法与参数（argon2id 为 PHC 字符串）；仅通过第三方登录的用户可为空。
+//   - PasswordChangedAt: 最近一次设置密码的时间，用于判断密码是否超过最长使用期限；为空时按注册时间计算。
+//   - PasswordChangeRequired: 是否必须在下次使用密码登录时修改密码，如初始化的超级管理员或由管理员重置的密码。
 //   - IsActive: 用户是否激活，默认为 true。
 //   - LastLoginAt: 最后登录时间。
 //   - CreatedAt: 创建记录的时间戳。
 //   - UpdatedAt: 最后更新时间戳。
+//   - DeletedAt: 软删除时间，已删除的用户不会出现在查询结果中，其用户名、邮箱与手机号可以重新注册。
 type User struct {
-	UUID         uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:用户唯一标识符"`
-	Username     string     `json:"username" gorm:"type:varchar(50);not null;uniqueIndex;comment:用户名"`
-	Email        string     `json:"email" gorm:"type:varchar(100);not null;uniqueIndex;comment:邮箱地址"`
-	Phone        *string    `json:"phone" gorm:"type:varchar(20);comment:手机号"`
-	PasswordHash string     `json:"-" gorm:"type:char(60);not null;comment:密码哈希值"`
-	IsActive     bool       `json:"is_active" gorm:"type:boolean;not null;default:true;comment:是否激活"`
-	LastLoginAt  *time.Time `json:"last_login_at" gorm:"type:timestamp;comment:最后登录时间"`
-	CreatedAt    time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
-	UpdatedAt    time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+	UUID                   uuid.UUID      `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:用户唯一标识符"`
+	TenantUUID             uuid.UUID      `json:"tenant_uuid" gorm:"type:uuid;uniqueIndex:idx_user_tenant_username;uniqueIndex:idx_user_tenant_email;uniqueIndex:idx_user_tenant_phone_hash;comment:所属租户UUID"`
+	Username               string         `json:"username" gorm:"type:varchar(50);not null;uniqueIndex:idx_user_tenant_username,where:deleted_at IS NULL;comment:用户名"`
+	Email                  *string        `json:"email" gorm:"type:varchar(100);uniqueIndex:idx_user_tenant_email,where:deleted_at IS NULL;comment:邮箱地址"`
+	EmailVerifiedAt        *time.Time     `json:"email_verified_at" gorm:"type:timestamp;comment:邮箱验证时间"`
+	Phone                  *string        `json:"phone" gorm:"type:text;serializer:encrypted;comment:手机号(加密)"`
+	PhoneHash              *string        `json:"-" gorm:"type:char(64);uniqueIndex:idx_user_tenant_phone_hash,where:deleted_at IS NULL;comment:手机号盲索引"`
+	PhoneVerifiedAt        *time.Time     `json:"phone_verified_at" gorm:"type:timestamp;comment:手机号验证时间"`
+	PasswordHash           *string        `json:"-" gorm:"type:text;comment:密码哈希值"`
+	PasswordChangedAt      *time.Time     `json:"password_changed_at" gorm:"type:timestamp;comment:密码修改时间"`
+	PasswordChangeRequired bool           `json:"password_change_required" gorm:"type:boolean;not null;default:false;comment:是否必须修改密码"`
+	IsActive               bool           `json:"is_active" gorm:"type:boolean;not null;default:true;comment:是否激活"`
+	LastLoginAt            *time.Time     `json:"last_login_at" gorm:"type:timestamp;comment:最后登录时间"`
+	CreatedAt              time.Time      `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt              time.Time      `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+	DeletedAt              gorm.DeletedAt `json:"-" gorm:"type:timestamp;index;comment:删除时间"`
 
 	// 关联关系
-	Profile            *UserProfile          `json:"profile,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:用户详细资料"`
-	WechatAccount      *UserThirdPartyWechat `json:"wechat_account,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:微信账号"`
-	GithubAccount      *UserThirdPartyGithub `json:"github_account,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:Github账号"`
-	QQAccount          *UserThirdPartyQQ     `json:"qq_account,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:QQ账号"`
-	UserRoles          []*UserRole           `json:"user_roles,omitempty" gorm:"foreignKey:UserUUID;references:UUID;comment:用户角色关联"`
-	UserTokens         []*UserToken          `json:"user_tokens,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:用户令牌"`
-	AuthorizationCodes []*AuthorizationCode  `json:"authorization_codes,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:授权码"`
+	Tenant             *Tenant                   `json:"tenant,omitempty" gorm:"foreignKey:TenantUUID;references:UUID;comment:所属租户"`
+	Profile            *UserProfile              `json:"profile,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:用户详细资料"`
+	WechatAccounts     []*UserThirdPartyWechat   `json:"wechat_accounts,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:微信账号(网站应用/公众号/小程序)"`
+	GithubAccount      *UserThirdPartyGithub     `json:"github_account,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:Github账号"`
+	QQAccount          *UserThirdPartyQQ         `json:"qq_account,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:QQ账号"`
+	ExternalIdentities []*UserExternalIdentity   `json:"external_identities,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:通用外部身份(OIDC/OAuth2)"`
+	TOTP               *UserTOTP                 `json:"totp,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:TOTP两步验证配置"`
+	RecoveryCodes      []*UserRecoveryCode       `json:"recovery_codes,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:两步验证恢复码"`
+	Passkeys           []*UserWebAuthnCredential `json:"passkeys,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:通行密钥"`
+	UserRoles          []*UserRole               `json:"user_roles,omitempty" gorm:"foreignKey:UserUUID;references:UUID;comment:用户角色关联"`
+	PasswordHistories  []*UserPasswordHistory    `json:"password_histories,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:历史密码"`
+	UserTokens         []*UserToken              `json:"user_tokens,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:用户令牌"`
+	AuthorizationCodes []*AuthorizationCode      `json:"authorization_codes,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:授权码"`
 }
 
 // BeforeCreate 在创建 User 记录前自动生成新的 UUID（如果当前 UUID 为空）。
@@ -56,3 +76,18 @@ func (u *User) BeforeUpdate(_ *gorm.DB) (err error) {
 	u.UpdatedAt = time.Now()
 	return
 }
+
+// IsEmailVerified 检查用户的邮箱是否已通过验证。
+func (u *User) IsEmailVerified() bool {
+	return u.Email != nil && u.EmailVerifiedAt != nil
+}
+
+// IsPhoneVerified 检查用户的手机号是否已通过验证。
+func (u *User) IsPhoneVerified() bool {
+	return u.Phone != nil && u.PhoneVerifiedAt != nil
+}
+
+// HasPassword 检查用户是否设置了登录密码。
+func (u *User) HasPassword() bool {
+	return u.PasswordHash != nil && *u.PasswordHash != ""
+}
@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserExternalIdentity 表示用户与通用 OIDC/OAuth2 上游身份提供商的绑定实体。
+//
+// 与微信、QQ、Github 等专用绑定表不同，该表不保存提供商特有字段，而是以 Subject 标识上游用户，
+// 并将上游返回的全部声明保存在 RawClaims 中，使管理员无需修改代码即可接入新的身份提供商。
+//
+// 字段说明：
+//   - UUID: 绑定记录的唯一标识符，由 UUID 表示。
+//   - UserUUID: 关联的用户UUID，外键。
+//   - ProviderUUID: 关联的提供商UUID，外键，与 Subject 组成唯一索引。
+//   - Subject: 上游身份提供商中的用户唯一标识（OIDC 的 sub 声明）。
+//   - Email: 上游返回的邮箱地址。
+//   - RawClaims: 上游返回的原始声明，JSON 对象格式。
+//   - AccessToken: 上游访问令牌（加密存储）。
+//   - RefreshToken: 上游刷新令牌（加密存储）。
+//   - IDToken: 上游签发的 ID Token（加密存储）。
+//   - TokenExpiresAt: 上游访问令牌过期时间。
+//   - IsActive: 绑定是否激活，默认为 true。
+//   - FirstBindAt: 首次绑定时间。
+//   - LastLoginAt: 最后一次通过该提供商登录的时间。
+//   - CreatedAt: 创建记录的时间戳。
+//   - UpdatedAt: 最后更新时间戳。
+type UserExternalIdentity struct {
+	UUID           uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:外部身份绑定记录唯一标识符"`
+	UserUUID       uuid.UUID  `json:"user_uuid" gorm:"type:uuid;not null;index;comment:关联用户UUID"`
+	ProviderUUID   uuid.UUID  `json:"provider_uuid" gorm:"type:uuid;not null;uniqueIndex:idx_external_provider_subject;comment:关联提供商UUID"`
+	Subject        string     `json:"subject" gorm:"type:varchar(255);not null;uniqueIndex:idx_external_provider_subject;comment:上游用户唯一标识"`
+	Email          *string    `json:"email" gorm:"type:varchar(100);comment:上游邮箱地址"`
+	RawClaims      *string    `json:"raw_claims" gorm:"type:jsonb;comment:上游原始声明(JSON对象)"`
+	AccessToken    *string    `json:"-" gorm:"type:text;serializer:encrypted;comment:上游访问令牌(加密)"`
+	RefreshToken   *string    `json:"-" gorm:"type:text;serializer:encrypted;comment:上游刷新令牌(加密)"`
+	IDToken        *string    `json:"-" gorm:"type:text;serializer:encrypted;comment:上游ID Token(加密)"`
+	TokenExpiresAt *time.Time `json:"token_expires_at" gorm:"type:timestamp;comment:访问令牌过期时间"`
+	IsActive       bool       `json:"is_active" gorm:"type:boolean;not null;default:true;comment:是否激活"`
+	FirstBindAt    time.Time  `json:"first_bind_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:首次绑定时间"`
+	LastLoginAt    *time.Time `json:"last_login_at" gorm:"type:timestamp;comment:最后登录时间"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+
+	// 关联关系
+	User     *User               `json:"user,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联用户"`
+	Provider *ThirdPartyProvider `json:"provider,omitempty" gorm:"foreignKey:ProviderUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联提供商"`
+}
+
+// BeforeCreate 在创建 UserExternalIdentity 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (uei *UserExternalIdentity) BeforeCreate(_ *gorm.DB) (err error) {
+	if uei.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		uei.UUID = newUUID
+	}
+	if uei.FirstBindAt.IsZero() {
+		uei.FirstBindAt = time.Now()
+	}
+	return
+}
+
+// BeforeUpdate 在更新 UserExternalIdentity 记录前自动更新 UpdatedAt 字段。
+func (uei *UserExternalIdentity) BeforeUpdate(_ *gorm.DB) (err error) {
+	uei.UpdatedAt = time.Now()
+	return
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserImportError 表示批量导入用户时导入失败的一行及其原因。
+//
+// 字段说明：
+//   - UUID: 记录的唯一标识符，由 UUID 表示。
+//   - JobUUID: 所属的导入任务UUID，外键。
+//   - RowNumber: 失败的行号：CSV 文件为数据行的行号（列名行为第 1 行），JSON 文件为数组下标加 1。
+//   - Username: 该行的用户名，无法读取时为空。
+//   - Message: 导入失败的原因。
+//   - CreatedAt: 创建记录的时间戳。
+type UserImportError struct {
+	UUID      uuid.UUID `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:导入失败记录唯一标识符"`
+	JobUUID   uuid.UUID `json:"job_uuid" gorm:"type:uuid;not null;index;comment:所属导入任务UUID"`
+	RowNumber int       `json:"row_number" gorm:"type:integer;not null;comment:行号"`
+	Username  *string   `json:"username" gorm:"type:varchar(255);comment:用户名"`
+	Message   string    `json:"message" gorm:"type:text;not null;comment:失败原因"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+}
+
+// BeforeCreate 在创建 UserImportError 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (uie *UserImportError) BeforeCreate(_ *gorm.DB) (err error) {
+	if uie.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		uie.UUID = newUUID
+	}
+	return
+}
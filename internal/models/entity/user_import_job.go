@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserImportJob 表示管理后台批量导入用户的后台任务，记录导入进度与结果。
+//
+// 字段说明：
+//   - UUID: 导入任务的唯一标识符，由 UUID 表示。
+//   - TenantUUID: 导入任务所属的租户UUID，用户被导入到该租户。
+//   - CreatedBy: 发起导入的管理员UUID。
+//   - FileName: 上传的文件名。
+//   - Format: 文件格式，csv 或 json。
+//   - Status: 任务状态，pending、running、completed 或 failed。
+//   - Total: 文件中的用户总数。
+//   - Processed: 已处理的用户数。
+//   - Succeeded: 导入成功的用户数。
+//   - Failed: 导入失败的用户数，失败原因见 Errors。
+//   - Message: 任务中断的原因，任务正常结束时为空。
+//   - StartedAt: 开始导入的时间。
+//   - FinishedAt: 任务结束的时间。
+//   - CreatedAt: 创建记录的时间戳。
+//   - UpdatedAt: 最后更新时间戳，任务运行期间随进度更新。
+type UserImportJob struct {
+	UUID       uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:导入任务唯一标识符"`
+	TenantUUID uuid.UUID  `json:"tenant_uuid" gorm:"type:uuid;not null;index;comment:所属租户UUID"`
+	CreatedBy  *uuid.UUID `json:"created_by" gorm:"type:uuid;comment:发起导入的管理员UUID"`
+	FileName   string     `json:"file_name" gorm:"type:varchar(255);not null;comment:上传的文件名"`
+	Format     string     `json:"format" gorm:"type:varchar(10);not null;comment:文件格式"`
+	Status     string     `json:"status" gorm:"type:varchar(20);not null;comment:任务状态"`
+	Total      int        `json:"total" gorm:"type:integer;not null;default:0;comment:用户总数"`
+	Processed  int        `json:"processed" gorm:"type:integer;not null;default:0;comment:已处理用户数"`
+	Succeeded  int        `json:"succeeded" gorm:"type:integer;not null;default:0;comment:导入成功用户数"`
+	Failed     int        `json:"failed" gorm:"type:integer;not null;default:0;comment:导入失败用户数"`
+	Message    *string    `json:"message" gorm:"type:text;comment:任务中断原因"`
+	StartedAt  *time.Time `json:"started_at" gorm:"type:timestamp;comment:开始时间"`
+	FinishedAt *time.Time `json:"finished_at" gorm:"type:timestamp;comment:结束时间"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+
+	// 关联关系
+	Tenant *Tenant            `json:"tenant,omitempty" gorm:"foreignKey:TenantUUID;references:UUID;constraint:OnDelete:CASCADE;comment:所属租户"`
+	Errors []*UserImportError `json:"errors,omitempty" gorm:"foreignKey:JobUUID;references:UUID;constraint:OnDelete:CASCADE;comment:导入失败的行"`
+}
+
+// BeforeCreate 在创建 UserImportJob 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (uij *UserImportJob) BeforeCreate(_ *gorm.DB) (err error) {
+	if uij.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		uij.UUID = newUUID
+	}
+	return
+}
+
+// BeforeUpdate 在更新 UserImportJob 记录前自动更新 UpdatedAt 字段。
+func (uij *UserImportJob) BeforeUpdate(_ *gorm.DB) (err error) {
+	uij.UpdatedAt = time.Now()
+	return
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserPasswordHistory 表示用户曾经使用过的密码，用于阻止重复使用最近的密码。
+//
+// 字段说明：
+//   - UUID: 历史密码记录的唯一标识符，由 UUID 表示。
+//   - UserUUID: 关联的用户UUID，外键。
+//   - PasswordHash: 被替换的密码哈希值。
+//   - CreatedAt: 密码被替换的时间。
+type UserPasswordHistory struct {
+	UUID         uuid.UUID `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:历史密码唯一标识符"`
+	UserUUID     uuid.UUID `json:"user_uuid" gorm:"type:uuid;not null;index;comment:关联用户UUID"`
+	PasswordHash string    `json:"-" gorm:"type:text;not null;comment:密码哈希值"`
+	CreatedAt    time.Time `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+
+	// 关联关系
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联用户"`
+}
+
+// BeforeCreate 在创建 UserPasswordHistory 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (uph *UserPasswordHistory) BeforeCreate(_ *gorm.DB) (err error) {
+	if uph.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		uph.UUID = newUUID
+	}
+	return
+}
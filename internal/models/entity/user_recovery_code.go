@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserRecoveryCode 表示用户两步验证的一次性恢复码，在无法使用身份验证器时代替动态口令。
+//
+// 字段说明：
+//   - UUID: 恢复码记录的唯一标识符，由 UUID 表示。
+//   - UserUUID: 关联的用户UUID，外键。
+//   - CodeHash: 恢复码的 SHA-256 哈希值，明文只在生成时展示一次。
+//   - UsedAt: 使用时间，为空表示尚未使用。
+//   - CreatedAt: 创建记录的时间戳。
+type UserRecoveryCode struct {
+	UUID      uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:恢复码唯一标识符"`
+	UserUUID  uuid.UUID  `json:"user_uuid" gorm:"type:uuid;not null;index;comment:关联用户UUID"`
+	CodeHash  string     `json:"-" gorm:"type:char(64);not null;comment:恢复码哈希值"`
+	UsedAt    *time.Time `json:"used_at" gorm:"type:timestamp;comment:使用时间"`
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+
+	// 关联关系
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联用户"`
+}
+
+// BeforeCreate 在创建 UserRecoveryCode 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (urc *UserRecoveryCode) BeforeCreate(_ *gorm.DB) (err error) {
+	if urc.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		urc.UUID = newUUID
+	}
+	return
+}
@@ -79,7 +79,7 @@ type UserThirdPartyGithub struct {
 	Followers        int        `json:"followers" gorm:"type:integer;default:0;comment:关注者数量"`
 	Following        int        `json:"following" gorm:"type:integer;default:0;comment:关注的人数量"`
 	HirableAvailable bool       `json:"hirable_available" gorm:"type:boolean;default:false;comment:是否可雇佣"`
-	AccessToken      *string    `json:"-" gorm:"type:text;comment:Github访问令牌(加密)"`
+	AccessToken      *string    `json:"-" gorm:"type:text;serializer:encrypted;comment:Github访问令牌(加密)"`
 	TokenType        *string    `json:"token_type" gorm:"type:varchar(20);default:'bearer';comment:令牌类型"`
 	Scope            *string    `json:"scope" gorm:"type:varchar(200);comment:权限范围"`
 	IsActive         bool       `json:"is_active" gorm:"type:boolean;not null;default:true;comment:是否激活"`
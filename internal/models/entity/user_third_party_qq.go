@@ -82,8 +82,8 @@ type UserThirdPartyQQ struct {
 	VipLevel       int        `json:"vip_level" gorm:"type:smallint;default:0;comment:QQ会员等级"`
 	IsYellowVip    bool       `json:"is_yellow_vip" gorm:"type:boolean;default:false;comment:是否为黄钻会员"`
 	YellowVipLevel int        `json:"yellow_vip_level" gorm:"type:smallint;default:0;comment:黄钻等级"`
-	AccessToken    *string    `json:"-" gorm:"type:text;comment:QQ访问令牌(加密)"`
-	RefreshToken   *string    `json:"-" gorm:"type:text;comment:QQ刷新令牌(加密)"`
+	AccessToken    *string    `json:"-" gorm:"type:text;serializer:encrypted;comment:QQ访问令牌(加密)"`
+	RefreshToken   *string    `json:"-" gorm:"type:text;serializer:encrypted;comment:QQ刷新令牌(加密)"`
 	TokenExpiresAt *time.Time `json:"token_expires_at" gorm:"type:timestamp;comment:访问令牌过期时间"`
 	IsActive       bool       `json:"is_active" gorm:"type:boolean;not null;default:true;comment:是否激活"`
 	FirstBindAt    time.Time  `json:"first_bind_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:首次绑定时间"`
@@ -9,8 +9,8 @@ import (
 // UserThirdPartyWechat 表示用户微信账号绑定实体，存储用户与微信平台账号的绑定关系。
 //
 // 微信登录特有字段：
-//   - UnionID: 微信开放平台唯一标识，用于统一用户身份
-//   - OpenID: 微信公众平台唯一标识，每个公众号下唯一
+//   - UnionID: 微信开放平台唯一标识，用于统一用户身份；网站应用、公众号与小程序的多条绑定记录通过它归属到同一用户
+//   - OpenID: 微信公众平台唯一标识，每个提供商（网站应用/公众号/小程序）下唯一
 //   - SessionKey: 小程序会话密钥（如果是小程序登录）
 //   - City/Province/Country: 微信用户地理位置信息
 //   - Language: 用户语言
@@ -44,10 +44,10 @@ import (
 type UserThirdPartyWechat struct {
 	UUID           uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:微信绑定记录唯一标识符"`
 	UserUUID       uuid.UUID  `json:"user_uuid" gorm:"type:uuid;not null;index;comment:关联用户UUID"`
-	ProviderUUID   uuid.UUID  `json:"provider_uuid" gorm:"type:uuid;not null;index;comment:关联微信提供商UUID"`
-	UnionID        *string    `json:"union_id" gorm:"type:varchar(100);uniqueIndex;comment:微信开放平台唯一标识"`
-	OpenID         string     `json:"open_id" gorm:"type:varchar(100);not null;index;comment:微信公众平台唯一标识"`
-	SessionKey     *string    `json:"-" gorm:"type:varchar(255);comment:小程序会话密钥(加密)"`
+	ProviderUUID   uuid.UUID  `json:"provider_uuid" gorm:"type:uuid;not null;index;uniqueIndex:idx_wechat_provider_open_id;comment:关联微信提供商UUID"`
+	UnionID        *string    `json:"union_id" gorm:"type:varchar(100);index;comment:微信开放平台唯一标识"`
+	OpenID         string     `json:"open_id" gorm:"type:varchar(100);not null;uniqueIndex:idx_wechat_provider_open_id;comment:微信公众平台唯一标识"`
+	SessionKey     *string    `json:"-" gorm:"type:text;serializer:encrypted;comment:小程序会话密钥(加密)"`
 	Nickname       *string    `json:"nickname" gorm:"type:varchar(100);comment:微信用户昵称"`
 	Avatar         *string    `json:"avatar" gorm:"type:varchar(500);comment:微信用户头像"`
 	Gender         int        `json:"gender" gorm:"type:smallint;default:0;comment:性别(0-未知,1-男,2-女)"`
@@ -57,8 +57,8 @@ type UserThirdPartyWechat struct {
 	Language       *string    `json:"language" gorm:"type:varchar(10);default:'zh_CN';comment:用户语言"`
 	Subscribe      bool       `json:"subscribe" gorm:"type:boolean;default:false;comment:是否关注公众号"`
 	SubscribeTime  *time.Time `json:"subscribe_time" gorm:"type:timestamp;comment:关注公众号时间"`
-	AccessToken    *string    `json:"-" gorm:"type:text;comment:微信访问令牌(加密)"`
-	RefreshToken   *string    `json:"-" gorm:"type:text;comment:微信刷新令牌(加密)"`
+	AccessToken    *string    `json:"-" gorm:"type:text;serializer:encrypted;comment:微信访问令牌(加密)"`
+	RefreshToken   *string    `json:"-" gorm:"type:text;serializer:encrypted;comment:微信刷新令牌(加密)"`
 	TokenExpiresAt *time.Time `json:"token_expires_at" gorm:"type:timestamp;comment:访问令牌过期时间"`
 	IsActive       bool       `json:"is_active" gorm:"type:boolean;not null;default:true;comment:是否激活"`
 	FirstBindAt    time.Time  `json:"first_bind_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:首次绑定时间"`
@@ -11,6 +11,8 @@ import (
 // 字段说明：
 //   - UUID: 令牌记录的唯一标识符。
 //   - UserUUID: 关联的用户唯一标识符。
+//   - ApplicationUUID: 令牌签发给的接入应用UUID，为空表示用户直接登录 SSO 获得的令牌。
+//   - SessionID: 签发令牌时所属的 SSO 会话ID，会话结束时其下的令牌一并撤销。
 //   - AccessToken: 访问令牌，用于短期身份验证。
 //   - RefreshToken: 刷新令牌，用于获取新的访问令牌。
 //   - AccessTokenExpiresAt: 访问令牌过期时间。
@@ -25,6 +27,8 @@ import (
 type UserToken struct {
 	UUID                  uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:令牌记录唯一标识符"`
 	UserUUID              uuid.UUID  `json:"user_uuid" gorm:"type:uuid;not null;index;comment:用户唯一标识符"`
+	ApplicationUUID       *uuid.UUID `json:"application_uuid" gorm:"type:uuid;index;comment:接入应用UUID(为空表示SSO自身)"`
+	SessionID             *string    `json:"-" gorm:"type:varchar(64);index;comment:所属SSO会话ID"`
 	AccessToken           string     `json:"access_token" gorm:"type:varchar(255);not null;uniqueIndex;comment:访问令牌"`
 	RefreshToken          string     `json:"refresh_token" gorm:"type:varchar(255);not null;uniqueIndex;comment:刷新令牌"`
 	AccessTokenExpiresAt  time.Time  `json:"access_token_expires_at" gorm:"type:timestamp;not null;comment:访问令牌过期时间"`
@@ -38,7 +42,8 @@ type UserToken struct {
 	UpdatedAt             time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
 
 	// 关联关系
-	User *User `json:"user,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联用户"`
+	User        *User        `json:"user,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联用户"`
+	Application *Application `json:"application,omitempty" gorm:"foreignKey:ApplicationUUID;references:UUID;constraint:OnDelete:CASCADE;comment:接入应用"`
 }
 
 // BeforeCreate 在创建 UserToken 记录前自动生成新的 UUID（如果当前 UUID 为空）。
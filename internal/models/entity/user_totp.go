@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserTOTP 表示用户的 TOTP 两步验证配置，每个用户最多一条。
+//
+// 字段说明：
+//   - UUID: 记录的唯一标识符，由 UUID 表示。
+//   - UserUUID: 关联的用户UUID，外键，唯一。
+//   - Secret: Base32 编码的共享密钥（加密存储）。
+//   - IsEnabled: 是否已启用；登记后需要用一次动态口令确认才会启用。
+//   - LastUsedStep: 最近一次通过校验的时间步，用于拒绝重复使用的口令。
+//   - ConfirmedAt: 确认启用的时间。
+//   - CreatedAt: 创建记录的时间戳。
+//   - UpdatedAt: 最后更新时间戳。
+type UserTOTP struct {
+	UUID         uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:TOTP配置唯一标识符"`
+	UserUUID     uuid.UUID  `json:"user_uuid" gorm:"type:uuid;not null;uniqueIndex;comment:关联用户UUID"`
+	Secret       string     `json:"-" gorm:"type:text;not null;serializer:encrypted;comment:共享密钥(加密)"`
+	IsEnabled    bool       `json:"is_enabled" gorm:"type:boolean;not null;default:false;comment:是否已启用"`
+	LastUsedStep int64      `json:"-" gorm:"type:bigint;not null;default:0;comment:最近一次通过校验的时间步"`
+	ConfirmedAt  *time.Time `json:"confirmed_at" gorm:"type:timestamp;comment:确认启用时间"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+
+	// 关联关系
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联用户"`
+}
+
+// BeforeCreate 在创建 UserTOTP 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (ut *UserTOTP) BeforeCreate(_ *gorm.DB) (err error) {
+	if ut.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		ut.UUID = newUUID
+	}
+	return
+}
+
+// BeforeUpdate 在更新 UserTOTP 记录前自动更新 UpdatedAt 字段。
+func (ut *UserTOTP) BeforeUpdate(_ *gorm.DB) (err error) {
+	ut.UpdatedAt = time.Now()
+	return
+}
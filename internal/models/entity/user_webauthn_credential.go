@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserWebAuthnCredential 表示用户注册的通行密钥（WebAuthn 凭据）实体。
+//
+// 字段说明：
+//   - UUID: 凭据记录的唯一标识符，由 UUID 表示。
+//   - UserUUID: 关联的用户UUID，外键。
+//   - CredentialID: 身份验证器生成的凭据 ID，唯一。
+//   - PublicKey: COSE 编码的凭据公钥。
+//   - AttestationType: 注册时的证明格式，如 "none"、"packed"。
+//   - Transports: 身份验证器支持的传输方式，以逗号分隔，如 "internal,hybrid"。
+//   - AAGUID: 身份验证器型号标识，可用于识别通行密钥的来源。
+//   - SignCount: 签名计数器，用于发现被克隆的身份验证器；同步型通行密钥恒为 0。
+//   - BackupEligible: 凭据是否可以被备份（同步型通行密钥）。
+//   - BackupState: 凭据当前是否已被备份。
+//   - Nickname: 用户为通行密钥设置的名称。
+//   - LastUsedAt: 最后一次使用的时间。
+//   - CreatedAt: 创建记录的时间戳。
+//   - UpdatedAt: 最后更新时间戳。
+type UserWebAuthnCredential struct {
+	UUID            uuid.UUID  `json:"uuid" gorm:"primaryKey;type:uuid;not null;comment:通行密钥唯一标识符"`
+	UserUUID        uuid.UUID  `json:"user_uuid" gorm:"type:uuid;not null;index;comment:关联用户UUID"`
+	CredentialID    []byte     `json:"-" gorm:"type:bytea;not null;uniqueIndex;comment:凭据ID"`
+	PublicKey       []byte     `json:"-" gorm:"type:bytea;not null;comment:凭据公钥(COSE编码)"`
+	AttestationType string     `json:"attestation_type" gorm:"type:varchar(32);not null;comment:证明格式"`
+	Transports      string     `json:"transports" gorm:"type:varchar(100);not null;default:'';comment:传输方式(逗号分隔)"`
+	AAGUID          uuid.UUID  `json:"aaguid" gorm:"column:aaguid;type:uuid;not null;comment:身份验证器型号标识"`
+	SignCount       int64      `json:"-" gorm:"type:bigint;not null;default:0;comment:签名计数器"`
+	BackupEligible  bool       `json:"backup_eligible" gorm:"type:boolean;not null;default:false;comment:是否可备份"`
+	BackupState     bool       `json:"backup_state" gorm:"type:boolean;not null;default:false;comment:是否已备份"`
+	Nickname        string     `json:"nickname" gorm:"type:varchar(64);not null;comment:通行密钥名称"`
+	LastUsedAt      *time.Time `json:"last_used_at" gorm:"type:timestamp;comment:最后使用时间"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:创建时间"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;comment:更新时间"`
+
+	// 关联关系
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserUUID;references:UUID;constraint:OnDelete:CASCADE;comment:关联用户"`
+}
+
+// BeforeCreate 在创建 UserWebAuthnCredential 记录前自动生成新的 UUID（如果当前 UUID 为空）。
+func (uc *UserWebAuthnCredential) BeforeCreate(_ *gorm.DB) (err error) {
+	if uc.UUID == uuid.Nil {
+		newUUID, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		uc.UUID = newUUID
+	}
+	return
+}
+
+// BeforeUpdate 在更新 UserWebAuthnCredential 记录前自动更新 UpdatedAt 字段。
+func (uc *UserWebAuthnCredential) BeforeUpdate(_ *gorm.DB) (err error) {
+	uc.UpdatedAt = time.Now()
+	return
+}
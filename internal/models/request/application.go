@@ -0,0 +1,14 @@
+package request
+
+import "github.com/google/uuid"
+
+// ApplicationAccess 表示修改接入应用访问策略的请求参数，规则以本次请求为准整体替换。
+//
+// Policy 为 role 时 Roles 列出允许登录的角色；为 allowlist 时 Users 与 Groups 列出允许登录的用户与分组，
+// 分组的下级分组成员同样允许登录。与策略无关的列表会被忽略。
+type ApplicationAccess struct {
+	Policy string      `json:"policy" binding:"required,oneof=open role allowlist"`
+	Roles  []uuid.UUID `json:"roles"`
+	Users  []uuid.UUID `json:"users"`
+	Groups []uuid.UUID `json:"groups"`
+}
@@ -0,0 +1,37 @@
+package request
+
+// PasswordLogin 表示账号密码登录的请求参数，Account 可以是用户名或邮箱。
+type PasswordLogin struct {
+	Account  string `json:"account" binding:"required,max=100"`
+	Password string `json:"password" binding:"required,max=128"`
+}
+
+// PasswordChange 表示登录时按要求修改密码的请求参数，Token 为修改密码挑战令牌。
+type PasswordChange struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,max=128"`
+}
+
+// PasswordUpdate 表示已登录用户修改密码的请求参数。
+//
+// 已设置密码时必须提供当前密码；仅通过第三方登录、尚未设置密码的用户可以省略 CurrentPassword 直接设置密码。
+type PasswordUpdate struct {
+	CurrentPassword string `json:"current_password" binding:"max=128"`
+	NewPassword     string `json:"new_password" binding:"required,max=128"`
+}
+
+// MFAChallenge 表示只需携带两步验证挑战令牌的请求参数，如登记身份验证器、开始通行密钥验证。
+type MFAChallenge struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// MFAVerify 表示提交两步验证的请求参数，Code 为动态口令或恢复码。
+type MFAVerify struct {
+	Token string `json:"token" binding:"required"`
+	Code  string `json:"code" binding:"required,max=32"`
+}
+
+// TOTPCode 表示已登录用户管理两步验证时提交的动态口令。
+type TOTPCode struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
@@ -0,0 +1,33 @@
+package request
+
+// Authorize 表示接入应用发起授权的请求参数，以查询参数提交。
+//
+// 需要用户登录时授权请求会暂存在服务端，前端登录页完成登录后携带 Request 重新访问授权地址，
+// 此时其余参数均取自暂存的请求。
+type Authorize struct {
+	Request       string `form:"request" json:"-"`
+	ResponseType  string `form:"response_type" json:"response_type" binding:"required_without=Request"`
+	ApplicationID string `form:"application_id" json:"application_id" binding:"required_without=Request,max=50"`
+	RedirectURI   string `form:"redirect_uri" json:"redirect_uri" binding:"required_without=Request,max=500"`
+	State         string `form:"state" json:"state" binding:"max=512"`
+	Prompt        string `form:"prompt" json:"prompt" binding:"omitempty,oneof=none login"`
+	MaxAge        *int64 `form:"max_age" json:"max_age" binding:"omitempty,min=0"`
+}
+
+// OAuthToken 表示接入应用使用授权码换取令牌的请求参数，以表单提交。
+type OAuthToken struct {
+	GrantType         string `form:"grant_type" binding:"required,oneof=authorization_code"`
+	Code              string `form:"code" binding:"required,max=128"`
+	RedirectURI       string `form:"redirect_uri" binding:"required,max=500"`
+	ApplicationID     string `form:"application_id" binding:"required,max=50"`
+	ApplicationSecret string `form:"application_secret" binding:"required,max=255"`
+}
+
+// Logout 表示结束 SSO 会话的请求参数，接入应用以查询参数或表单提交。
+//
+// 指定 PostLogoutRedirectURI 时必须同时指定 ApplicationID，跳转地址需要已在该应用的回调地址中登记。
+type Logout struct {
+	ApplicationID         string `form:"application_id" json:"application_id" binding:"required_with=PostLogoutRedirectURI,max=50"`
+	PostLogoutRedirectURI string `form:"post_logout_redirect_uri" json:"post_logout_redirect_uri" binding:"max=500"`
+	State                 string `form:"state" json:"state" binding:"max=512"`
+}
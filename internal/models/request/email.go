@@ -0,0 +1,17 @@
+package request
+
+// EmailToken 表示提交邮件中令牌的请求参数。
+type EmailToken struct {
+	Token string `json:"token" binding:"required,max=128"`
+}
+
+// PasswordForgot 表示申请通过邮件重置密码的请求参数。
+type PasswordForgot struct {
+	Email string `json:"email" binding:"required,email,max=100"`
+}
+
+// PasswordReset 表示使用邮件中的令牌重置密码的请求参数。
+type PasswordReset struct {
+	Token    string `json:"token" binding:"required,max=128"`
+	Password string `json:"password" binding:"required,max=128"`
+}
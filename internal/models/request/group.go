@@ -0,0 +1,34 @@
+package request
+
+import "github.com/google/uuid"
+
+// GroupCreate 表示创建分组的请求参数。
+//
+// Name 只能包含小写字母、数字、下划线与短横线，会出现在签发给接入应用的令牌中，创建后不可修改；
+// ParentUUID 为空时创建顶级分组。
+type GroupCreate struct {
+	ParentUUID  *uuid.UUID `json:"parent_uuid"`
+	Name        string     `json:"name" binding:"required,max=50"`
+	DisplayName string     `json:"display_name" binding:"required,max=100"`
+	Description *string    `json:"description" binding:"omitempty,max=255"`
+}
+
+// GroupUpdate 表示修改分组的请求参数，未传入的字段保持不变。
+//
+// 传入 ParentUUID 时将分组移动到该分组之下；MoveToRoot 为 true 时将分组移动为顶级分组，两者不能同时传入。
+type GroupUpdate struct {
+	ParentUUID  *uuid.UUID `json:"parent_uuid"`
+	MoveToRoot  bool       `json:"move_to_root"`
+	DisplayName *string    `json:"display_name" binding:"omitempty,max=100"`
+	Description *string    `json:"description" binding:"omitempty,max=255"`
+}
+
+// GroupMemberAdd 表示将用户加入分组的请求参数。
+type GroupMemberAdd struct {
+	UserUUID uuid.UUID `json:"user_uuid" binding:"required"`
+}
+
+// GroupRoleAssign 表示为分组分配角色的请求参数。
+type GroupRoleAssign struct {
+	RoleUUID uuid.UUID `json:"role_uuid" binding:"required"`
+}
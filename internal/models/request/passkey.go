@@ -0,0 +1,26 @@
+package request
+
+import "encoding/json"
+
+// PasskeyRegister 表示完成通行密钥注册的请求参数。
+//
+// Credential 为浏览器 navigator.credentials.create 返回的凭据序列化后的 JSON；Nickname 为空时使用默认名称。
+type PasskeyRegister struct {
+	Token      string          `json:"token" binding:"required"`
+	Nickname   string          `json:"nickname" binding:"max=64"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+}
+
+// PasskeyAssertion 表示使用通行密钥登录或完成两步验证的请求参数。
+//
+// Token 为开始流程时得到的令牌，两步验证时为两步验证挑战令牌；
+// Credential 为浏览器 navigator.credentials.get 返回的凭据序列化后的 JSON。
+type PasskeyAssertion struct {
+	Token      string          `json:"token" binding:"required"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+}
+
+// PasskeyRename 表示重命名通行密钥的请求参数。
+type PasskeyRename struct {
+	Nickname string `json:"nickname" binding:"required,max=64"`
+}
@@ -0,0 +1,14 @@
+package request
+
+// ProfileUpdate 表示当前登录用户修改个人资料的请求参数，未传入的字段保持不变，传入空字符串表示清空该字段。
+//
+// Gender 取 0（未知）、1（男）或 2（女）；Birthday 的格式为 "2006-01-02"。头像通过上传接口修改。
+type ProfileUpdate struct {
+	Nickname *string `json:"nickname" binding:"omitempty,max=50"`
+	Gender   *int    `json:"gender" binding:"omitempty,oneof=0 1 2"`
+	Birthday *string `json:"birthday" binding:"omitempty,max=10"`
+	Country  *string `json:"country" binding:"omitempty,max=50"`
+	Province *string `json:"province" binding:"omitempty,max=50"`
+	City     *string `json:"city" binding:"omitempty,max=50"`
+	Bio      *string `json:"bio" binding:"omitempty,max=500"`
+}
@@ -0,0 +1,48 @@
+package request
+
+// ClaimMapping 表示通用驱动的声明映射，值为上游用户信息中对应字段的名称，留空使用 OIDC 标准声明。
+type ClaimMapping struct {
+	Subject string `json:"subject,omitempty" binding:"max=100"`
+	Email   string `json:"email,omitempty" binding:"max=100"`
+	Name    string `json:"name,omitempty" binding:"max=100"`
+	Picture string `json:"picture,omitempty" binding:"max=100"`
+}
+
+// ProviderCreate 表示创建第三方提供商的请求参数。
+//
+// 只能创建通用驱动（oidc、oauth2）的提供商，专用驱动的提供商由系统初始化时内置。
+// Code 只能包含小写字母、数字、下划线与短横线，会出现在登录地址中且创建后不可修改。
+type ProviderCreate struct {
+	Name         string        `json:"name" binding:"required,max=50"`
+	Code         string        `json:"code" binding:"required,max=30"`
+	Type         string        `json:"type" binding:"required,oneof=oidc oauth2"`
+	Issuer       *string       `json:"issuer" binding:"omitempty,url,max=500"`
+	ClientID     string        `json:"client_id" binding:"required,max=255"`
+	ClientSecret string        `json:"client_secret" binding:"required"`
+	AuthURL      string        `json:"auth_url" binding:"omitempty,url,max=500"`
+	TokenURL     string        `json:"token_url" binding:"omitempty,url,max=500"`
+	UserInfoURL  string        `json:"user_info_url" binding:"omitempty,url,max=500"`
+	Scope        string        `json:"scope" binding:"max=200"`
+	RedirectURL  string        `json:"redirect_url" binding:"required,url,max=500"`
+	ClaimMapping *ClaimMapping `json:"claim_mapping"`
+	IsEnabled    *bool         `json:"is_enabled"`
+	SortOrder    int           `json:"sort_order"`
+}
+
+// ProviderUpdate 表示修改第三方提供商的请求参数，未传入的字段保持不变。
+//
+// ClientSecret 只写不读，传入时会重新加密保存；Code 与 Type 创建后不可修改。
+type ProviderUpdate struct {
+	Name         *string       `json:"name" binding:"omitempty,max=50"`
+	Issuer       *string       `json:"issuer" binding:"omitempty,url,max=500"`
+	ClientID     *string       `json:"client_id" binding:"omitempty,max=255"`
+	ClientSecret *string       `json:"client_secret"`
+	AuthURL      *string       `json:"auth_url" binding:"omitempty,url,max=500"`
+	TokenURL     *string       `json:"token_url" binding:"omitempty,url,max=500"`
+	UserInfoURL  *string       `json:"user_info_url" binding:"omitempty,url,max=500"`
+	Scope        *string       `json:"scope" binding:"omitempty,max=200"`
+	RedirectURL  *string       `json:"redirect_url" binding:"omitempty,url,max=500"`
+	ClaimMapping *ClaimMapping `json:"claim_mapping"`
+	IsEnabled    *bool         `json:"is_enabled"`
+	SortOrder    *int          `json:"sort_order"`
+}
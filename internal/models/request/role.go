@@ -0,0 +1,35 @@
+package request
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoleCreate 表示创建自定义角色的请求参数。
+//
+// ApplicationUUID 为空时创建全局角色，Name 只能包含大写字母、数字与下划线；
+// 传入时创建属于该接入应用的角色，Name 只能包含小写字母、数字与 _ . : -，如 "billing:viewer"，且不能授予权限。
+// Name 必须以字母开头，创建后不可修改；Permissions 为授予角色的权限代码。
+type RoleCreate struct {
+	ApplicationUUID *uuid.UUID `json:"application_uuid"`
+	Name            string     `json:"name" binding:"required,max=50"`
+	DisplayName     string     `json:"display_name" binding:"required,max=100"`
+	Description     *string    `json:"description" binding:"omitempty,max=255"`
+	Permissions     []string   `json:"permissions" binding:"omitempty,dive,max=100"`
+}
+
+// RoleUpdate 表示修改角色的请求参数，未传入的字段保持不变；传入 Permissions 时以其替换角色的全部权限。
+type RoleUpdate struct {
+	DisplayName *string   `json:"display_name" binding:"omitempty,max=100"`
+	Description *string   `json:"description" binding:"omitempty,max=255"`
+	Permissions *[]string `json:"permissions" binding:"omitempty,dive,max=100"`
+}
+
+// RoleAssign 表示为用户分配角色的请求参数，ExpiresAt 为空表示永不过期。
+//
+// 用户已持有该角色时，以本次请求的过期时间重新分配。
+type RoleAssign struct {
+	UserUUID  uuid.UUID  `json:"user_uuid" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,18 @@
+package request
+
+// SMSCode 表示申请发送短信验证码的请求参数，手机号须为 E.164 格式，如 +8613800138000。
+type SMSCode struct {
+	Phone string `json:"phone" binding:"required,e164"`
+}
+
+// SMSLogin 表示使用短信验证码登录的请求参数。
+type SMSLogin struct {
+	Phone string `json:"phone" binding:"required,e164"`
+	Code  string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// PhoneBind 表示使用短信验证码绑定手机号的请求参数。
+type PhoneBind struct {
+	Phone string `json:"phone" binding:"required,e164"`
+	Code  string `json:"code" binding:"required,len=6,numeric"`
+}
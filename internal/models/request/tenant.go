@@ -0,0 +1,25 @@
+package request
+
+// TenantCreate 表示创建租户的请求参数。
+//
+// Code 只能包含小写字母、数字与短横线，会出现在 "/t/:tenant" 路径与签发者标识中且创建后不可修改；
+// Domain 为空时只能通过路径访问该租户。AdminUsername、AdminEmail 与 AdminPassword 用于创建该租户的首位超级管理员。
+type TenantCreate struct {
+	Code          string  `json:"code" binding:"required,max=50"`
+	Name          string  `json:"name" binding:"required,max=100"`
+	Domain        *string `json:"domain" binding:"omitempty,hostname_rfc1123,max=255"`
+	Issuer        *string `json:"issuer" binding:"omitempty,url,max=500"`
+	AdminUsername string  `json:"admin_username" binding:"required,max=50"`
+	AdminEmail    string  `json:"admin_email" binding:"required,email,max=100"`
+	AdminPassword string  `json:"admin_password" binding:"required,max=128"`
+}
+
+// TenantUpdate 表示修改租户的请求参数，未传入的字段保持不变，Domain 与 Issuer 传入空字符串时清除。
+//
+// 默认租户不能停用。
+type TenantUpdate struct {
+	Name     *string `json:"name" binding:"omitempty,max=100"`
+	Domain   *string `json:"domain" binding:"omitempty,hostname_rfc1123,max=255"`
+	Issuer   *string `json:"issuer" binding:"omitempty,url,max=500"`
+	IsActive *bool   `json:"is_active"`
+}
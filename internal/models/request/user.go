@@ -0,0 +1,67 @@
+package request
+
+import "github.com/google/uuid"
+
+// UserSearch 表示管理后台搜索用户的查询参数。
+//
+// Query 按用户名、邮箱或昵称模糊匹配，传入 E.164 格式的手机号时按手机号精确匹配；
+// Status 为 "active" 或 "inactive" 时只返回启用或停用的用户。
+// 结果按注册时间倒序分页，Cursor 为上一页返回的 next_cursor，为空时从第一页开始；Limit 默认为 20。
+type UserSearch struct {
+	Query  string     `form:"q" binding:"omitempty,max=100"`
+	Status string     `form:"status" binding:"omitempty,oneof=active inactive"`
+	Cursor *uuid.UUID `form:"cursor"`
+	Limit  int        `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// UserStatusUpdate 表示管理员启用或停用账号的请求参数。
+type UserStatusUpdate struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+// UserPasswordSet 表示管理员重置用户密码的请求参数，新密码同样需要满足密码策略。
+//
+// RequireChange 为 true 时，用户下次使用密码登录需要先修改密码，适用于管理员设置的临时密码。
+type UserPasswordSet struct {
+	Password      string `json:"password" binding:"required,max=128"`
+	RequireChange bool   `json:"require_change"`
+}
+
+// UserImport 表示批量导入用户的表单参数，导入文件通过 multipart 字段 file 上传。
+//
+// Format 为 "csv" 或 "json"，为空时按文件扩展名判断。
+type UserImport struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv json"`
+}
+
+// UserImportRow 表示导入文件中的一个用户，CSV 文件的列名与 JSON 文件的字段名相同，未出现的列按空值处理。
+//
+// IsActive 为空时账号默认启用；Gender 取 0（未知）、1（男）或 2（女），Birthday 的格式为 "2006-01-02"。
+// Roles 为分配给用户的全局角色名称，CSV 文件中多个角色以分号分隔。
+// PasswordHash 为旧系统中的密码哈希，支持 bcrypt、argon2id、PBKDF2 与加盐 MD5 格式，用户首次登录成功后按当前配置重新计算；
+// 为空时用户没有密码，需要通过找回密码或由管理员设置密码。
+type UserImportRow struct {
+	Username      string   `json:"username" binding:"required,max=50"`
+	Email         *string  `json:"email" binding:"omitempty,email,max=100"`
+	EmailVerified bool     `json:"email_verified"`
+	Phone         *string  `json:"phone" binding:"omitempty,e164"`
+	PhoneVerified bool     `json:"phone_verified"`
+	IsActive      *bool    `json:"is_active"`
+	Nickname      *string  `json:"nickname" binding:"omitempty,max=50"`
+	Gender        int      `json:"gender" binding:"oneof=0 1 2"`
+	Birthday      *string  `json:"birthday" binding:"omitempty,max=10"`
+	Country       *string  `json:"country" binding:"omitempty,max=50"`
+	Province      *string  `json:"province" binding:"omitempty,max=50"`
+	City          *string  `json:"city" binding:"omitempty,max=50"`
+	Bio           *string  `json:"bio" binding:"omitempty,max=500"`
+	Roles         []string `json:"roles" binding:"omitempty,max=20,dive,required,max=50"`
+	PasswordHash  *string  `json:"password_hash" binding:"omitempty,max=500"`
+}
+
+// UserExport 表示导出用户的查询参数，导出文件的格式与导入文件相同。
+//
+// Format 为 "csv" 或 "json"，默认为 "csv"；IncludePasswordHash 为 true 时导出密码哈希，只有超级管理员可以使用。
+type UserExport struct {
+	Format              string `form:"format" binding:"omitempty,oneof=csv json"`
+	IncludePasswordHash bool   `form:"include_password_hash"`
+}
@@ -0,0 +1,19 @@
+package request
+
+// WechatAuthorize 表示获取微信授权地址的请求参数。
+//
+// Provider 为 "wechat"（网站应用扫码登录）或 "wechat_mp"（公众号网页授权），默认为 "wechat"。
+type WechatAuthorize struct {
+	Provider string `form:"provider"`
+}
+
+// OAuthCallback 表示第三方平台授权完成后回调携带的参数。
+type OAuthCallback struct {
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
+}
+
+// WechatMiniLogin 表示小程序登录的请求参数，Code 为小程序端 wx.login 获取的临时登录凭证。
+type WechatMiniLogin struct {
+	Code string `json:"code" binding:"required"`
+}
@@ -1,17 +1,34 @@
 package router
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/handler"
+	"github.com/bamboo-services/bamboo-sso/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
 
 type router struct {
-	group *gin.RouterGroup
+	group   *gin.RouterGroup
+	handler *handler.Handler
 }
 
+// RegisterRoute 注册全部路由。
+//
+// 路由同时注册在 "api/v1" 与 "api/v1/t/:tenant" 下：前者按请求的 Host 确定租户，未匹配时使用默认租户；
+// 后者按路径中的租户代码确定租户，供没有独立域名的租户使用。
 func RegisterRoute(engine *gin.Engine) {
-	group := engine.Group("api/v1")
+	h := handler.New()
+	for _, group := range []*gin.RouterGroup{
+		engine.Group("api/v1", middleware.Tenant()),
+		engine.Group("api/v1/t/:tenant", middleware.Tenant()),
+	} {
+		r := &router{group: group, handler: h}
 
-	r := &router{group: group}
-
-	// 路由注册
-	r.RouterHealth()
-	r.RouterPublic()
+		// 路由注册
+		r.RouterHealth()
+		r.RouterPublic()
+		r.RouterAuth()
+		r.RouterOAuth()
+		r.RouterUser()
+		r.RouterAdmin()
+	}
 }
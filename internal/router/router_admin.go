@@ -0,0 +1,73 @@
+package router
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/middleware"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+)
+
+// RouterAdmin 注册管理后台相关的路由，每个接口要求当前登录用户的角色被授予对应的查看或管理权限。
+//
+// 路径 "/admin/providers" 下提供第三方提供商的配置管理；
+// 路径 "/admin/lockouts" 下提供因登录失败次数过多而被锁定账号的查看与解除；
+// 路径 "/admin/users" 下提供用户的搜索、查看、启用或停用、重置密码与删除，"/admin/users/:uuid/sign-out" 强制用户在所有设备上退出登录；
+// 路径 "/admin/users/import" 从 CSV 或 JSON 文件批量导入用户，"/admin/users/imports" 下查看导入任务的进度与错误，"/admin/users/export" 导出用户；
+// 路径 "/admin/roles" 下提供角色管理与用户角色分配；
+// 路径 "/admin/groups" 下提供分组管理、分组成员与分组角色的分配；
+// 路径 "/admin/applications/:uuid/access" 查看与修改接入应用的访问策略；
+// 路径 "/admin/permissions" 列出可分配给角色的权限；
+// 路径 "/admin/tenants" 下提供租户管理，只能在默认租户中访问。
+func (r *router) RouterAdmin() {
+	group := r.group.Group("/admin", middleware.Auth())
+
+	{
+		group.GET("/providers", middleware.RequirePermission(constants.PermissionProviderRead), r.handler.ProviderList)
+		group.POST("/providers", middleware.RequirePermission(constants.PermissionProviderWrite), r.handler.ProviderCreate)
+		group.GET("/providers/:uuid", middleware.RequirePermission(constants.PermissionProviderRead), r.handler.ProviderGet)
+		group.PATCH("/providers/:uuid", middleware.RequirePermission(constants.PermissionProviderWrite), r.handler.ProviderUpdate)
+		group.DELETE("/providers/:uuid", middleware.RequirePermission(constants.PermissionProviderWrite), r.handler.ProviderDelete)
+
+		group.GET("/lockouts", middleware.RequirePermission(constants.PermissionUserRead), r.handler.LockoutList)
+		group.DELETE("/lockouts/:uuid", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.LockoutClear)
+
+		group.GET("/users", middleware.RequirePermission(constants.PermissionUserRead), r.handler.UserSearch)
+		group.GET("/users/export", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.UserExport)
+		group.POST("/users/import", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.UserImport)
+		group.GET("/users/imports", middleware.RequirePermission(constants.PermissionUserRead), r.handler.UserImportList)
+		group.GET("/users/imports/:uuid", middleware.RequirePermission(constants.PermissionUserRead), r.handler.UserImportGet)
+		group.GET("/users/:uuid", middleware.RequirePermission(constants.PermissionUserRead), r.handler.UserGet)
+		group.PATCH("/users/:uuid/status", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.UserStatusUpdate)
+		group.POST("/users/:uuid/password", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.UserPasswordSet)
+		group.DELETE("/users/:uuid", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.UserDelete)
+		group.POST("/users/:uuid/sign-out", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.UserSignOut)
+
+		group.GET("/roles", middleware.RequirePermission(constants.PermissionRoleRead), r.handler.RoleList)
+		group.POST("/roles", middleware.RequirePermission(constants.PermissionRoleWrite), r.handler.RoleCreate)
+		group.GET("/roles/:uuid", middleware.RequirePermission(constants.PermissionRoleRead), r.handler.RoleGet)
+		group.PATCH("/roles/:uuid", middleware.RequirePermission(constants.PermissionRoleWrite), r.handler.RoleUpdate)
+		group.DELETE("/roles/:uuid", middleware.RequirePermission(constants.PermissionRoleWrite), r.handler.RoleDelete)
+		group.GET("/roles/:uuid/members", middleware.RequirePermission(constants.PermissionRoleRead), r.handler.RoleMemberList)
+		group.POST("/roles/:uuid/members", middleware.RequirePermission(constants.PermissionRoleWrite), r.handler.RoleMemberAssign)
+		group.DELETE("/roles/:uuid/members/:user_uuid", middleware.RequirePermission(constants.PermissionRoleWrite), r.handler.RoleMemberUnassign)
+
+		group.GET("/groups", middleware.RequirePermission(constants.PermissionUserRead), r.handler.GroupList)
+		group.POST("/groups", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.GroupCreate)
+		group.GET("/groups/:uuid", middleware.RequirePermission(constants.PermissionUserRead), r.handler.GroupGet)
+		group.PATCH("/groups/:uuid", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.GroupUpdate)
+		group.DELETE("/groups/:uuid", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.GroupDelete)
+		group.GET("/groups/:uuid/members", middleware.RequirePermission(constants.PermissionUserRead), r.handler.GroupMemberList)
+		group.POST("/groups/:uuid/members", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.GroupMemberAdd)
+		group.DELETE("/groups/:uuid/members/:user_uuid", middleware.RequirePermission(constants.PermissionUserWrite), r.handler.GroupMemberRemove)
+		group.POST("/groups/:uuid/roles", middleware.RequirePermission(constants.PermissionRoleWrite), r.handler.GroupRoleAssign)
+		group.DELETE("/groups/:uuid/roles/:role_uuid", middleware.RequirePermission(constants.PermissionRoleWrite), r.handler.GroupRoleUnassign)
+
+		group.GET("/applications/:uuid/access", middleware.RequirePermission(constants.PermissionApplicationRead), r.handler.ApplicationAccessGet)
+		group.PUT("/applications/:uuid/access", middleware.RequirePermission(constants.PermissionApplicationWrite), r.handler.ApplicationAccessUpdate)
+
+		group.GET("/permissions", middleware.RequirePermission(constants.PermissionRoleRead), r.handler.PermissionList)
+
+		group.GET("/tenants", middleware.RequirePermission(constants.PermissionSystemRead), r.handler.TenantList)
+		group.POST("/tenants", middleware.RequirePermission(constants.PermissionSystemWrite), r.handler.TenantCreate)
+		group.GET("/tenants/:uuid", middleware.RequirePermission(constants.PermissionSystemRead), r.handler.TenantGet)
+		group.PATCH("/tenants/:uuid", middleware.RequirePermission(constants.PermissionSystemWrite), r.handler.TenantUpdate)
+	}
+}
@@ -0,0 +1,38 @@
+package router
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/middleware"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+)
+
+// RouterAuth 注册登录相关的路由。
+//
+// 路径 "/auth/login" 提供账号密码登录，"/auth/passkey" 提供通行密钥免密登录，"/auth/sms" 提供短信验证码登录，"/auth/logout" 结束 SSO 会话；
+// 路径 "/auth/mfa" 下提供登录流程中的两步验证，包括管理员首次登录时登记身份验证器；
+// 路径 "/auth/email" 与 "/auth/password" 下提供邮箱验证、通过邮件找回密码，以及登录时按要求修改初始或已过期的密码。
+// 提交凭据的接口按登录规则限流，发送验证码与邮件的接口按验证码规则限流。
+func (r *router) RouterAuth() {
+	group := r.group.Group("/auth")
+	login := group.Group("", middleware.RateLimit(constants.RateLimitLogin))
+	code := group.Group("", middleware.RateLimit(constants.RateLimitCode))
+
+	{
+		login.POST("/login", r.handler.PasswordLogin)
+		login.POST("/passkey/begin", r.handler.PasskeyLoginBegin)
+		login.POST("/passkey", r.handler.PasskeyLoginFinish)
+		code.POST("/sms/code", r.handler.SMSLoginCode)
+		login.POST("/sms/login", r.handler.SMSLogin)
+		group.POST("/logout", r.handler.Logout)
+
+		login.POST("/mfa/totp/enroll", r.handler.MFAEnroll)
+		login.POST("/mfa/totp", r.handler.MFAVerifyTOTP)
+		login.POST("/mfa/recovery", r.handler.MFAVerifyRecovery)
+		login.POST("/mfa/passkey/begin", r.handler.MFAPasskeyBegin)
+		login.POST("/mfa/passkey", r.handler.MFAVerifyPasskey)
+
+		login.POST("/email/verify", r.handler.EmailVerify)
+		code.POST("/password/forgot", r.handler.PasswordForgot)
+		login.POST("/password/reset", r.handler.PasswordReset)
+		login.POST("/password/change", r.handler.PasswordChange)
+	}
+}
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/middleware"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+)
+
+// RouterOAuth 注册第三方登录相关的路由。
+//
+// 路径 "/oauth/wechat" 下提供微信扫码登录、公众号网页授权与小程序登录；
+// 路径 "/oauth/:provider" 下提供管理员配置的通用 OIDC/OAuth2 提供商登录；
+// 路径 "/oauth/authorize" 与 "/oauth/token" 供接入应用复用 SSO 会话完成单点登录，"/oauth/logout" 供接入应用发起单点登出。
+// 完成登录的回调接口按登录规则限流，换取令牌的接口按令牌规则限流。
+func (r *router) RouterOAuth() {
+	group := r.group.Group("/oauth")
+
+	{
+		group.GET("/authorize", r.handler.OAuthAuthorize)
+		group.POST("/token", middleware.RateLimit(constants.RateLimitToken), r.handler.OAuthToken)
+		group.GET("/logout", r.handler.OAuthLogout)
+		group.POST("/logout", r.handler.OAuthLogout)
+
+		group.GET("/wechat/authorize", r.handler.WechatAuthorize)
+		group.GET("/wechat/callback", middleware.RateLimit(constants.RateLimitLogin), r.handler.WechatCallback)
+		group.POST("/wechat/mini/login", middleware.RateLimit(constants.RateLimitLogin), r.handler.WechatMiniLogin)
+
+		group.GET("/:provider/authorize", r.handler.ExternalAuthorize)
+		group.GET("/:provider/callback", middleware.RateLimit(constants.RateLimitLogin), r.handler.ExternalCallback)
+	}
+}
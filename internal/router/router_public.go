@@ -2,11 +2,15 @@ package router
 
 // RouterPublic 注册公共访问的路由入口点。
 //
-// 路径 "/public/ping" 可用于提供基本的公共服务，如可达性测试。
+// 路径 "/public/ping" 可用于提供基本的公共服务，如可达性测试；
+// 路径 "/public/tenant" 返回当前请求所属租户的名称与签发者标识；
+// 路径 "/public/providers" 列出登录页可用的第三方登录方式。
 func (r *router) RouterPublic() {
 	group := r.group.Group("/public")
 
 	{
 		group.GET("/ping")
+		group.GET("/tenant", r.handler.TenantCurrent)
+		group.GET("/providers", r.handler.ProviderPublicList)
 	}
 }
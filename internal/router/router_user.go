@@ -0,0 +1,45 @@
+package router
+
+import (
+	"github.com/bamboo-services/bamboo-sso/internal/middleware"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+)
+
+// RouterUser 注册当前登录用户相关的路由，均需要携带有效的访问令牌。
+func (r *router) RouterUser() {
+	group := r.group.Group("/user", middleware.Auth())
+
+	{
+		group.GET("/profile", r.handler.ProfileGet)
+		group.PATCH("/profile", r.handler.ProfileUpdate)
+		group.POST("/profile/avatar", r.handler.ProfileAvatarUpload)
+		group.DELETE("/profile/avatar", r.handler.ProfileAvatarRemove)
+		group.PUT("/password", middleware.RateLimit(constants.RateLimitLogin), r.handler.PasswordUpdate)
+
+		group.GET("/devices", r.handler.DeviceList)
+		group.DELETE("/devices", r.handler.DeviceRevokeOthers)
+		group.DELETE("/devices/:uuid", r.handler.DeviceRevoke)
+
+		group.GET("/bindings", r.handler.BindingList)
+		group.GET("/bindings/:provider/authorize", r.handler.BindingAuthorize)
+		group.POST("/bindings/wechat_mini", r.handler.BindingWechatMini)
+		group.DELETE("/bindings/:uuid", r.handler.BindingUnlink)
+
+		group.GET("/totp", r.handler.TOTPStatus)
+		group.POST("/totp/enroll", r.handler.TOTPEnroll)
+		group.POST("/totp/confirm", middleware.RateLimit(constants.RateLimitLogin), r.handler.TOTPConfirm)
+		group.POST("/totp/recovery-codes", middleware.RateLimit(constants.RateLimitLogin), r.handler.TOTPRecoveryCodes)
+		group.DELETE("/totp", middleware.RateLimit(constants.RateLimitLogin), r.handler.TOTPDisable)
+
+		group.GET("/passkeys", r.handler.PasskeyList)
+		group.POST("/passkeys/register/begin", r.handler.PasskeyRegisterBegin)
+		group.POST("/passkeys/register", r.handler.PasskeyRegisterFinish)
+		group.PATCH("/passkeys/:uuid", r.handler.PasskeyRename)
+		group.DELETE("/passkeys/:uuid", r.handler.PasskeyDelete)
+
+		group.POST("/email/verification", middleware.RateLimit(constants.RateLimitCode), r.handler.EmailSendVerification)
+
+		group.POST("/phone/code", middleware.RateLimit(constants.RateLimitCode), r.handler.PhoneBindCode)
+		group.POST("/phone", middleware.RateLimit(constants.RateLimitLogin), r.handler.PhoneBind)
+	}
+}
@@ -73,7 +73,7 @@ func (i *InitializeData) RoleInit(getEntity ...*entity.Role) {
 // 当应用已存在时，不会重复创建，避免数据库冗余。
 //
 // 方法使用逻辑：
-//   - 首先检查每个应用的名称是否已存在于数据库。
+//   - 首先检查每个应用的名称是否已存在于其所属租户中。
 //   - 若应用不存在，则记录在批量插入列表中以优化数据库操作。
 //   - 最后，统一插入所有需要创建的应用记录以减少数据库压力。
 //
@@ -87,7 +87,7 @@ func (i *InitializeData) ApplicationInit(getEntity ...*entity.Application) {
 	// 检查并创建默认应用
 	for _, appEntity := range getEntity {
 		var app entity.Application
-		if err := db.Where(entity.Application{Name: appEntity.Name}).First(&app).Error; err != nil {
+		if err := db.Where(entity.Application{TenantUUID: appEntity.TenantUUID, Name: appEntity.Name}).First(&app).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				log.Named(xConsts.LogINIT).Sugar().Debugf("应用 %s 不存在，创建默认应用", appEntity.Name)
 				noneAppList = append(noneAppList, appEntity)
@@ -139,3 +139,108 @@ func (i *InitializeData) SystemInit(getEntity ...*entity.System) {
 		db.Create(noneSystemList)
 	}
 }
+
+// ProviderInit 检查并初始化系统中缺失的第三方登录提供商数据。
+//
+// 参数 getEntity 是一组指针，指向需要检测或创建的提供商实体。
+// 如果传入的提供商在数据库中不存在，则会创建默认的提供商记录。
+// 当提供商已存在时，不会重复创建，避免覆盖管理员已填写的配置。
+//
+// 方法使用逻辑：
+//   - 首先检查每个提供商的代码是否已存在于其所属租户中。
+//   - 若提供商不存在，则记录在批量插入列表中以优化数据库操作。
+//   - 最后，统一插入所有需要创建的提供商记录以减少数据库压力。
+//
+// 注意：插入时会写入全部字段，以保证默认未启用的 IsEnabled 不会被数据库默认值覆盖。
+func (i *InitializeData) ProviderInit(getEntity ...*entity.ThirdPartyProvider) {
+	db := i.db
+	log := i.log
+
+	var noneProviderList []*entity.ThirdPartyProvider
+
+	// 检查并创建默认提供商
+	for _, providerEntity := range getEntity {
+		var provider entity.ThirdPartyProvider
+		if err := db.Where(entity.ThirdPartyProvider{TenantUUID: providerEntity.TenantUUID, Code: providerEntity.Code}).First(&provider).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Named(xConsts.LogINIT).Sugar().Debugf("第三方提供商 %s 不存在，创建默认提供商", providerEntity.Code)
+				noneProviderList = append(noneProviderList, providerEntity)
+			} else {
+				log.Named(xConsts.LogINIT).Sugar().Debugf("第三方提供商 %s 已存在，跳过创建", providerEntity.Code)
+			}
+		}
+	}
+
+	// 批量创建提供商「统一插入减少数据库操作压力」
+	if len(noneProviderList) > 0 {
+		db.Select("*").Create(noneProviderList)
+	}
+}
+
+// PermissionInit 检查并初始化系统中缺失的权限数据，返回本次新创建的权限。
+//
+// 参数 getEntity 是一组指针，指向需要检测或创建的权限实体。
+// 如果传入的权限在数据库中不存在，则会创建默认的权限记录。
+// 当权限已存在时，不会重复创建，也不会覆盖管理员修改过的名称与描述。
+//
+// 注意：返回值只包含新创建的权限，调用方据此为默认角色授予新增的权限，避免恢复管理员已撤销的授权。
+func (i *InitializeData) PermissionInit(getEntity ...*entity.Permission) []*entity.Permission {
+	db := i.db
+	log := i.log
+
+	var nonePermissionList []*entity.Permission
+
+	// 检查并创建默认权限
+	for _, permissionEntity := range getEntity {
+		var permission entity.Permission
+		if err := db.Where(entity.Permission{Code: permissionEntity.Code}).First(&permission).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Named(xConsts.LogINIT).Sugar().Debugf("权限 %s 不存在，创建默认权限", permissionEntity.Code)
+				nonePermissionList = append(nonePermissionList, permissionEntity)
+			} else {
+				log.Named(xConsts.LogINIT).Sugar().Debugf("权限 %s 已存在，跳过创建", permissionEntity.Code)
+			}
+		}
+	}
+
+	// 批量创建权限「统一插入减少数据库操作压力」
+	if len(nonePermissionList) > 0 {
+		if err := db.Create(nonePermissionList).Error; err != nil {
+			log.Named(xConsts.LogINIT).Sugar().Errorf("创建默认权限失败: %s", err.Error())
+			return nil
+		}
+	}
+	return nonePermissionList
+}
+
+// RolePermissionInit 为指定名称的角色授予一组权限。
+//
+// 角色不存在时跳过授权；已授予的权限不会重复创建。
+func (i *InitializeData) RolePermissionInit(roleName string, permissions ...*entity.Permission) {
+	db := i.db
+	log := i.log
+
+	if len(permissions) == 0 {
+		return
+	}
+	var role entity.Role
+	if err := db.Where(entity.Role{Name: roleName}).First(&role).Error; err != nil {
+		log.Named(xConsts.LogINIT).Sugar().Debugf("角色 %s 不存在，跳过授权", roleName)
+		return
+	}
+
+	var noneGrantList []*entity.RolePermission
+	for _, permission := range permissions {
+		var count int64
+		db.Model(&entity.RolePermission{}).Where(entity.RolePermission{RoleUUID: role.UUID, PermissionUUID: permission.UUID}).Count(&count)
+		if count == 0 {
+			noneGrantList = append(noneGrantList, &entity.RolePermission{RoleUUID: role.UUID, PermissionUUID: permission.UUID})
+		}
+	}
+
+	// 批量创建授权「统一插入减少数据库操作压力」
+	if len(noneGrantList) > 0 {
+		log.Named(xConsts.LogINIT).Sugar().Debugf("为角色 %s 授予 %d 项默认权限", roleName, len(noneGrantList))
+		db.Create(noneGrantList)
+	}
+}
@@ -0,0 +1,451 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SSO 表示单点登录业务相关的配置，对应配置文件中的 sso 节点。
+//
+// 字段说明：
+//   - Token: 用户令牌相关配置。
+//   - OAuth: 第三方登录与接入应用授权流程相关配置。
+//   - Session: SSO 浏览器会话相关配置。
+//   - Logout: 单点登出通知相关配置。
+//   - Secret: 敏感字段加密相关配置。
+//   - MFA: 两步验证相关配置。
+//   - WebAuthn: 通行密钥相关配置。
+//   - Email: 邮件发送与邮箱验证、找回密码相关配置。
+//   - SMS: 短信验证码相关配置。
+//   - RateLimit: 认证相关接口的限流配置。
+//   - Lockout: 登录失败锁定账号的配置。
+//   - Role: 用户角色分配相关配置。
+//   - Storage: 文件存储相关配置。
+//   - Profile: 用户资料与头像相关配置。
+//   - Password: 密码策略相关配置。
+//   - Import: 批量导入用户相关配置。
+type SSO struct {
+	Token     TokenConfig     `yaml:"token"`
+	OAuth     OAuthConfig     `yaml:"oauth"`
+	Session   SessionConfig   `yaml:"session"`
+	Logout    LogoutConfig    `yaml:"logout"`
+	Secret    SecretConfig    `yaml:"secret"`
+	MFA       MFAConfig       `yaml:"mfa"`
+	WebAuthn  WebAuthnConfig  `yaml:"webauthn"`
+	Email     EmailConfig     `yaml:"email"`
+	SMS       SMSConfig       `yaml:"sms"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Lockout   LockoutConfig   `yaml:"lockout"`
+	Role      RoleConfig      `yaml:"role"`
+	Storage   StorageConfig   `yaml:"storage"`
+	Profile   ProfileConfig   `yaml:"profile"`
+	Password  PasswordConfig  `yaml:"password"`
+	Import    ImportConfig    `yaml:"import"`
+}
+
+// TokenConfig 表示用户令牌的有效期与认证缓存配置。
+type TokenConfig struct {
+	AccessTTL  time.Duration `yaml:"access_ttl"`  // 访问令牌有效期
+	RefreshTTL time.Duration `yaml:"refresh_ttl"` // 刷新令牌有效期
+	CacheTTL   time.Duration `yaml:"cache_ttl"`   // 访问令牌认证结果在 Redis 中的缓存时间，也是更新令牌最近使用时间的最小间隔
+}
+
+// OAuthConfig 表示第三方登录与接入应用授权流程的配置。
+type OAuthConfig struct {
+	Issuer   string        `yaml:"issuer"`    // SSO 对外的签发者标识，通常为服务的外部访问地址，写入登出令牌的 iss；其余租户默认在其后加 "/t/<租户代码>"
+	StateTTL time.Duration `yaml:"state_ttl"` // 登录 state 与等待用户登录的授权请求的有效期
+	CodeTTL  time.Duration `yaml:"code_ttl"`  // 签发给接入应用的授权码的有效期
+}
+
+// SessionConfig 表示 SSO 浏览器会话的配置。
+//
+// 用户在 SSO 登录后获得一个保存在 Redis 中的会话，浏览器通过 HttpOnly Cookie 引用它；
+// 接入应用发起授权时复用该会话，用户无需再次输入凭据。
+type SessionConfig struct {
+	CookieName   string        `yaml:"cookie_name"`   // 会话 Cookie 的名称
+	CookieDomain string        `yaml:"cookie_domain"` // 会话 Cookie 的 Domain 属性，为空时只对当前域名有效
+	Secure       bool          `yaml:"secure"`        // 是否只通过 HTTPS 发送会话 Cookie，生产环境必须开启
+	IdleTTL      time.Duration `yaml:"idle_ttl"`      // 会话的空闲有效期，每次使用后重新计算
+	MaxLifetime  time.Duration `yaml:"max_lifetime"`  // 会话自登录起的最长有效期
+	LoginURL     string        `yaml:"login_url"`     // 前端登录页地址，授权时需要用户登录则携带授权请求编号跳转到该页面
+}
+
+// LogoutConfig 表示单点登出通知的配置。
+//
+// 用户退出 SSO 会话时，登记了后端通道登出地址的接入应用会收到一枚签名的登出令牌；
+// 投递失败的通知按指数退避重试，重试间隔依次为 10 秒、30 秒、90 秒……，令牌有效期应覆盖全部重试。
+type LogoutConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"` // 一条通知最多投递的次数
+	Timeout     time.Duration `yaml:"timeout"`      // 单次投递的请求超时时间
+	TokenTTL    time.Duration `yaml:"token_ttl"`    // 登出令牌的有效期
+}
+
+// RoleConfig 表示用户角色分配的配置。
+//
+// 过期的角色分配在认证时即不再生效，后台任务定期将其停用，使角色成员列表与数据保持一致。
+type RoleConfig struct {
+	ExpiryInterval time.Duration `yaml:"expiry_interval"` // 检查并停用过期角色分配的间隔
+}
+
+// SecretConfig 表示敏感字段加密的密钥环配置。
+//
+// 轮换主密钥时，先在 Keys 中加入新密钥并将 ActiveKey 指向它，执行重新加密命令后再移除旧密钥。
+// MasterKey 是引入密钥环之前的单一主密钥，用于解密不带主密钥 ID 的旧版密文；只配置 MasterKey 时它同时用于加密新数据，
+// 存量数据全部重新加密后才能移除。
+type SecretConfig struct {
+	ActiveKey string            `yaml:"active_key"` // 加密新数据所用的主密钥 ID
+	Keys      map[string]string `yaml:"keys"`       // 主密钥 ID 到 Base64 编码的 32 字节主密钥的映射
+	MasterKey string            `yaml:"master_key"` // 旧版 Base64 编码的 32 字节主密钥
+	IndexKey  string            `yaml:"index_key"`  // Base64 编码的 32 字节盲索引密钥，用于按手机号等加密字段查询
+}
+
+// MFAConfig 表示两步验证的配置。
+type MFAConfig struct {
+	Issuer        string        `yaml:"issuer"`         // 身份验证器中显示的签发者名称
+	ChallengeTTL  time.Duration `yaml:"challenge_ttl"`  // 通过第一步验证后完成两步验证的时限
+	MaxAttempts   int           `yaml:"max_attempts"`   // 一次挑战内允许的最大失败次数，也是已登录用户在 AttemptWindow 内允许的动态口令错误次数
+	AttemptWindow time.Duration `yaml:"attempt_window"` // 已登录用户启用、关闭两步验证或重新生成恢复码时统计动态口令错误次数的时间窗口
+}
+
+// WebAuthnConfig 表示通行密钥（WebAuthn）依赖方的配置。
+//
+// RPID 通常为前端页面所在的域名，通行密钥与之绑定，上线后不能随意修改，否则已注册的通行密钥将全部失效。
+type WebAuthnConfig struct {
+	RPID          string        `yaml:"rp_id"`           // 依赖方 ID，即通行密钥绑定的域名
+	RPDisplayName string        `yaml:"rp_display_name"` // 在浏览器与身份验证器中显示的依赖方名称
+	RPOrigins     []string      `yaml:"rp_origins"`      // 允许发起注册与登录的页面源，如 "https://sso.example.com"
+	CeremonyTTL   time.Duration `yaml:"ceremony_ttl"`    // 注册与登录流程的时限
+}
+
+// EmailConfig 表示邮件发送与邮箱验证、找回密码的配置。
+//
+// 邮件中的链接由 VerifyURL、ResetURL 附加 token 查询参数得到，前端页面取出令牌后调用对应的接口。
+type EmailConfig struct {
+	Driver     string        `yaml:"driver"`      // 发送方式：smtp 通过 SMTP 服务器发送；file 写入本地目录并记录日志，用于本地测试
+	From       string        `yaml:"from"`        // 发件人，如 "Bamboo SSO <no-reply@example.com>"
+	SigningKey string        `yaml:"signing_key"` // 签名邮件令牌的密钥
+	VerifyURL  string        `yaml:"verify_url"`  // 验证邮箱的前端页面地址
+	ResetURL   string        `yaml:"reset_url"`   // 重置密码的前端页面地址
+	VerifyTTL  time.Duration `yaml:"verify_ttl"`  // 验证邮箱链接的有效期
+	ResetTTL   time.Duration `yaml:"reset_ttl"`   // 重置密码链接的有效期
+	FileDir    string        `yaml:"file_dir"`    // file 方式下邮件的写入目录
+	SMTP       SMTPConfig    `yaml:"smtp"`        // smtp 方式下的服务器配置
+}
+
+// SMTPConfig 表示 SMTP 服务器的连接配置。
+type SMTPConfig struct {
+	Host     string `yaml:"host"`     // 服务器地址
+	Port     int    `yaml:"port"`     // 服务器端口
+	Username string `yaml:"username"` // 登录用户名，为空时不进行认证
+	Password string `yaml:"password"` // 登录密码
+	Security string `yaml:"security"` // 连接加密方式：starttls、tls（隐式 TLS）或 none
+}
+
+// SMSConfig 表示短信验证码的配置。
+type SMSConfig struct {
+	Driver      string        `yaml:"driver"`       // 发送方式：log 只将短信内容写入日志，用于本地测试
+	CodeTTL     time.Duration `yaml:"code_ttl"`     // 验证码有效期
+	Cooldown    time.Duration `yaml:"cooldown"`     // 同一手机号两次发送之间的最短间隔
+	MaxAttempts int           `yaml:"max_attempts"` // 一枚验证码允许的最大错误次数
+}
+
+// StorageConfig 表示文件存储的配置，用户上传的头像等文件通过它保存并对外提供访问。
+type StorageConfig struct {
+	Driver string             `yaml:"driver"` // 存储方式：local 保存在本地目录并由 SSO 服务对外提供访问
+	Local  LocalStorageConfig `yaml:"local"`  // local 方式下的目录配置
+}
+
+// LocalStorageConfig 表示本地目录存储的配置。
+//
+// 目录中的文件在 SSO 服务的 Path 路径下对外提供访问，BaseURL 为返回给客户端的文件地址前缀，
+// 服务部署在反向代理之后或使用独立的静态文件域名时需要修改。
+type LocalStorageConfig struct {
+	Dir     string `yaml:"dir"`      // 保存文件的本地目录
+	Path    string `yaml:"path"`     // SSO 服务对外提供文件访问的路径
+	BaseURL string `yaml:"base_url"` // 文件地址前缀，为空时使用签发者标识加 Path
+}
+
+// ProfileConfig 表示用户资料与头像的配置。
+//
+// 上传的头像会被裁剪为正方形并缩放到 AvatarSize 像素，原图超出 AvatarMaxBytes 或 AvatarMaxPixels 时拒绝处理。
+type ProfileConfig struct {
+	AvatarMaxBytes  int64 `yaml:"avatar_max_bytes"`  // 上传头像文件的最大字节数
+	AvatarMaxPixels int   `yaml:"avatar_max_pixels"` // 上传头像的最大像素数（宽×高），避免解码超大图片耗尽内存
+	AvatarSize      int   `yaml:"avatar_size"`       // 保存的头像边长（像素）
+}
+
+// PasswordConfig 表示密码策略的配置，用户设置或修改密码时按此校验。
+//
+// 字符类别分为大写字母、小写字母、数字与其他符号四类；BreachedList 为已泄露密码列表文件，每行一个明文密码
+// 或 40 位十六进制的 SHA-1 摘要（兼容 "摘要:次数" 格式），以 # 开头的行为注释，启动时全部载入内存。
+// 密码超过 MaxAge 未修改或被要求修改时，使用密码登录需要先设置新密码。
+type PasswordConfig struct {
+	MinLength     int           `yaml:"min_length"`     // 最短长度（字符数）
+	MaxLength     int           `yaml:"max_length"`     // 最大长度（字节数），使用 bcrypt 时不超过 72 字节
+	MinClasses    int           `yaml:"min_classes"`    // 至少包含的字符类别数
+	AllowPersonal bool          `yaml:"allow_personal"` // 是否允许密码包含用户名或邮箱
+	History       int           `yaml:"history"`        // 不能与当前密码及最近多少个历史密码相同，负数表示不检查
+	MaxAge        time.Duration `yaml:"max_age"`        // 密码的最长使用期限，0 表示不限制
+	BreachedList  string        `yaml:"breached_list"`  // 已泄露密码列表文件路径，为空表示不检查
+	ChangeTTL     time.Duration `yaml:"change_ttl"`     // 登录时修改密码的时限
+	Hash          HashConfig    `yaml:"hash"`           // 密码哈希
+}
+
+// HashConfig 表示密码哈希的配置，新设置的密码使用 Algorithm 指定的算法与参数计算哈希。
+//
+// 密码哈希以自描述的格式保存（argon2id 为 PHC 字符串，bcrypt 为其自带的 "$2a$" 格式），校验时按哈希自身记录的
+// 算法与参数进行，修改配置后已有的哈希仍然有效；从旧系统导入的 PBKDF2 与加盐 MD5 哈希同样可以校验。
+// 用户使用密码登录成功时，算法或参数与当前配置不同的哈希会被重新计算。
+type HashConfig struct {
+	Algorithm         string `yaml:"algorithm"`          // 哈希算法：argon2id 或 bcrypt
+	BcryptCost        int    `yaml:"bcrypt_cost"`        // bcrypt 的计算成本
+	Argon2Memory      uint32 `yaml:"argon2_memory"`      // argon2id 使用的内存（KiB）
+	Argon2Iterations  uint32 `yaml:"argon2_iterations"`  // argon2id 的迭代次数
+	Argon2Parallelism uint8  `yaml:"argon2_parallelism"` // argon2id 的并行度
+}
+
+// ImportConfig 表示管理后台批量导入用户的配置。
+//
+// 导入文件在请求中完整读取并校验格式，超出 MaxBytes 或 MaxRows 时直接拒绝；逐行创建用户在后台任务中进行。
+type ImportConfig struct {
+	MaxBytes int64 `yaml:"max_bytes"` // 导入文件的最大字节数
+	MaxRows  int   `yaml:"max_rows"`  // 单个导入文件的最大用户数
+}
+
+// RateLimitConfig 表示认证相关接口的限流配置，每类接口使用独立的滑动窗口。
+//
+// 未配置的类别使用默认规则；规则中某一维度的次数为 0 表示不按该维度限流。
+// 按 IP 计数使用的客户端 IP 只从 TrustedProxies 中的反向代理转发的请求头中读取，未配置时使用连接的对端地址，
+// 以免调用方伪造 X-Forwarded-For 绕过限流。
+type RateLimitConfig struct {
+	Login          RateLimitRule `yaml:"login"`           // 登录与两步验证接口
+	Code           RateLimitRule `yaml:"code"`            // 发送短信验证码、验证邮件与重置密码邮件等接口
+	Token          RateLimitRule `yaml:"token"`           // 令牌签发与刷新接口
+	TrustedProxies []string      `yaml:"trusted_proxies"` // 受信任的反向代理的 IP 或 CIDR，如 "10.0.0.0/8"
+}
+
+// RateLimitRule 表示一类接口的限流规则，在同一时间窗口内分别按客户端 IP、账号与接入应用计数。
+//
+// 按接入应用计数只作用于接入应用使用密钥认证的接口，即令牌接口，以认证通过的应用计数，调用方无法通过省略或更换应用标识符绕过。
+type RateLimitRule struct {
+	Window      time.Duration `yaml:"window"`      // 滑动窗口长度
+	IP          int           `yaml:"ip"`          // 同一 IP 在窗口内允许的最大请求数
+	Account     int           `yaml:"account"`     // 同一账号（用户名、邮箱、手机号或令牌接口的应用标识符）在窗口内允许的最大请求数
+	Application int           `yaml:"application"` // 同一接入应用在窗口内允许的最大请求数
+}
+
+// LockoutConfig 表示登录失败锁定账号的配置。
+//
+// 账号在 Window 内累计失败 Threshold 次后被锁定 Duration，此后每次锁定时长翻倍，直至 MaxDuration；
+// 超过 ResetAfter 未再被锁定时，锁定时长重新从 Duration 开始计算。Threshold 为负数表示不锁定账号。
+type LockoutConfig struct {
+	Threshold   int           `yaml:"threshold"`    // 触发锁定的失败次数
+	Window      time.Duration `yaml:"window"`       // 统计失败次数的时间窗口
+	Duration    time.Duration `yaml:"duration"`     // 首次锁定的时长
+	MaxDuration time.Duration `yaml:"max_duration"` // 锁定时长的上限
+	ResetAfter  time.Duration `yaml:"reset_after"`  // 锁定时长递增的记忆时间
+}
+
+// LoadSSO 从指定的配置文件中读取 sso 节点并填充默认值。
+//
+// 配置文件中缺失的字段会使用默认值：访问令牌 2 小时、刷新令牌 30 天、认证缓存 1 分钟、state 10 分钟、授权码 10 分钟、
+// 会话 Cookie 名为 sso_session、空闲 7 天且最长 30 天、登出通知最多投递 6 次且每次超时 5 秒、登出令牌 1 小时、
+// 两步验证挑战 5 分钟内最多失败 5 次、已登录用户 15 分钟内动态口令最多错误 5 次、通行密钥流程 5 分钟、邮件写入本地目录、
+// 验证邮箱链接 24 小时、重置密码链接 30 分钟、短信验证码 5 分钟内最多错误 5 次且 60 秒内不能重复发送、
+// 15 分钟内登录失败 5 次锁定账号 5 分钟且每次翻倍至最长 24 小时、文件保存在本地 data/uploads 目录并在 /uploads 下提供访问、
+// 头像不超过 5 MiB 与 4000 万像素且保存为 256 像素、密码 8 至 128 字节（bcrypt 为 72 字节）且至少包含两类字符、
+// 不能与最近 5 个密码相同、使用 argon2id 计算哈希、登录时 10 分钟内完成修改密码、导入文件不超过 10 MiB 与 10000 个用户；
+// 各类接口的默认限流规则见 applyDefault。
+func LoadSSO(path string) (*SSO, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		SSO SSO `yaml:"sso"`
+	}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	sso := &file.SSO
+	sso.applyDefault()
+	return sso, nil
+}
+
+// applyDefault 为未配置的字段填充默认值。
+func (s *SSO) applyDefault() {
+	if s.Token.AccessTTL <= 0 {
+		s.Token.AccessTTL = 2 * time.Hour
+	}
+	if s.Token.RefreshTTL <= 0 {
+		s.Token.RefreshTTL = 30 * 24 * time.Hour
+	}
+	if s.Token.CacheTTL <= 0 {
+		s.Token.CacheTTL = time.Minute
+	}
+	if s.OAuth.Issuer == "" {
+		s.OAuth.Issuer = "http://localhost:2233"
+	}
+	if s.OAuth.StateTTL <= 0 {
+		s.OAuth.StateTTL = 10 * time.Minute
+	}
+	if s.OAuth.CodeTTL <= 0 {
+		s.OAuth.CodeTTL = 10 * time.Minute
+	}
+	if s.Session.CookieName == "" {
+		s.Session.CookieName = "sso_session"
+	}
+	if s.Session.IdleTTL <= 0 {
+		s.Session.IdleTTL = 7 * 24 * time.Hour
+	}
+	if s.Session.MaxLifetime <= 0 {
+		s.Session.MaxLifetime = 30 * 24 * time.Hour
+	}
+	if s.Logout.MaxAttempts <= 0 {
+		s.Logout.MaxAttempts = 6
+	}
+	if s.Logout.Timeout <= 0 {
+		s.Logout.Timeout = 5 * time.Second
+	}
+	if s.Logout.TokenTTL <= 0 {
+		s.Logout.TokenTTL = time.Hour
+	}
+	if s.Role.ExpiryInterval <= 0 {
+		s.Role.ExpiryInterval = time.Minute
+	}
+	if s.MFA.Issuer == "" {
+		s.MFA.Issuer = "Bamboo SSO"
+	}
+	if s.MFA.ChallengeTTL <= 0 {
+		s.MFA.ChallengeTTL = 5 * time.Minute
+	}
+	if s.MFA.MaxAttempts <= 0 {
+		s.MFA.MaxAttempts = 5
+	}
+	if s.MFA.AttemptWindow <= 0 {
+		s.MFA.AttemptWindow = 15 * time.Minute
+	}
+	if s.WebAuthn.RPDisplayName == "" {
+		s.WebAuthn.RPDisplayName = s.MFA.Issuer
+	}
+	if s.WebAuthn.CeremonyTTL <= 0 {
+		s.WebAuthn.CeremonyTTL = 5 * time.Minute
+	}
+	if s.Email.Driver == "" {
+		s.Email.Driver = "file"
+	}
+	if s.Email.FileDir == "" {
+		s.Email.FileDir = "logs/mail"
+	}
+	if s.Email.VerifyTTL <= 0 {
+		s.Email.VerifyTTL = 24 * time.Hour
+	}
+	if s.Email.ResetTTL <= 0 {
+		s.Email.ResetTTL = 30 * time.Minute
+	}
+	if s.Email.SMTP.Security == "" {
+		s.Email.SMTP.Security = "starttls"
+	}
+	if s.SMS.Driver == "" {
+		s.SMS.Driver = "log"
+	}
+	if s.SMS.CodeTTL <= 0 {
+		s.SMS.CodeTTL = 5 * time.Minute
+	}
+	if s.SMS.Cooldown <= 0 {
+		s.SMS.Cooldown = time.Minute
+	}
+	if s.SMS.MaxAttempts <= 0 {
+		s.SMS.MaxAttempts = 5
+	}
+	if s.RateLimit.Login.Window <= 0 {
+		s.RateLimit.Login = RateLimitRule{Window: time.Minute, IP: 30, Account: 10}
+	}
+	if s.RateLimit.Code.Window <= 0 {
+		s.RateLimit.Code = RateLimitRule{Window: 10 * time.Minute, IP: 20, Account: 5}
+	}
+	if s.RateLimit.Token.Window <= 0 {
+		s.RateLimit.Token = RateLimitRule{Window: time.Minute, IP: 120, Account: 60, Application: 1200}
+	}
+	if s.Lockout.Threshold == 0 {
+		s.Lockout.Threshold = 5
+	}
+	if s.Lockout.Window <= 0 {
+		s.Lockout.Window = 15 * time.Minute
+	}
+	if s.Lockout.Duration <= 0 {
+		s.Lockout.Duration = 5 * time.Minute
+	}
+	if s.Lockout.MaxDuration < s.Lockout.Duration {
+		s.Lockout.MaxDuration = max(24*time.Hour, s.Lockout.Duration)
+	}
+	if s.Lockout.ResetAfter <= 0 {
+		s.Lockout.ResetAfter = 24 * time.Hour
+	}
+	if s.Storage.Driver == "" {
+		s.Storage.Driver = "local"
+	}
+	if s.Storage.Local.Dir == "" {
+		s.Storage.Local.Dir = "data/uploads"
+	}
+	if s.Storage.Local.Path == "" {
+		s.Storage.Local.Path = "/uploads"
+	}
+	if s.Storage.Local.BaseURL == "" {
+		s.Storage.Local.BaseURL = strings.TrimRight(s.OAuth.Issuer, "/") + s.Storage.Local.Path
+	}
+	if s.Profile.AvatarMaxBytes <= 0 {
+		s.Profile.AvatarMaxBytes = 5 << 20
+	}
+	if s.Profile.AvatarMaxPixels <= 0 {
+		s.Profile.AvatarMaxPixels = 40_000_000
+	}
+	if s.Profile.AvatarSize <= 0 {
+		s.Profile.AvatarSize = 256
+	}
+	if s.Password.MinLength <= 0 {
+		s.Password.MinLength = 8
+	}
+	if s.Password.Hash.Algorithm == "" {
+		s.Password.Hash.Algorithm = "argon2id"
+	}
+	if s.Password.Hash.BcryptCost <= 0 {
+		s.Password.Hash.BcryptCost = 10
+	}
+	if s.Password.Hash.Argon2Memory == 0 {
+		s.Password.Hash.Argon2Memory = 64 * 1024
+	}
+	if s.Password.Hash.Argon2Iterations == 0 {
+		s.Password.Hash.Argon2Iterations = 3
+	}
+	if s.Password.Hash.Argon2Parallelism == 0 {
+		s.Password.Hash.Argon2Parallelism = 4
+	}
+	maxLength := 128
+	if s.Password.Hash.Algorithm == "bcrypt" {
+		maxLength = 72
+	}
+	if s.Password.MaxLength <= 0 || s.Password.MaxLength > maxLength {
+		s.Password.MaxLength = maxLength
+	}
+	if s.Password.MinClasses <= 0 {
+		s.Password.MinClasses = 2
+	}
+	if s.Password.History == 0 {
+		s.Password.History = 5
+	}
+	if s.Password.ChangeTTL <= 0 {
+		s.Password.ChangeTTL = 10 * time.Minute
+	}
+	if s.Import.MaxBytes <= 0 {
+		s.Import.MaxBytes = 10 << 20
+	}
+	if s.Import.MaxRows <= 0 {
+		s.Import.MaxRows = 10000
+	}
+}
@@ -0,0 +1,77 @@
+package constants
+
+// AuditLog.Action 的取值。
+const (
+	AuditBindingLink   = "binding.link"   // 绑定第三方账号
+	AuditBindingUnlink = "binding.unlink" // 解绑第三方账号
+
+	AuditProviderCreate = "provider.create" // 创建第三方提供商
+	AuditProviderUpdate = "provider.update" // 修改第三方提供商配置
+	AuditProviderDelete = "provider.delete" // 删除第三方提供商
+
+	AuditTOTPEnable       = "totp.enable"            // 启用 TOTP 两步验证
+	AuditTOTPDisable      = "totp.disable"           // 关闭 TOTP 两步验证
+	AuditRecoveryGenerate = "totp.recovery_generate" // 重新生成恢复码
+
+	AuditPasskeyRegister = "passkey.register" // 注册通行密钥
+	AuditPasskeyRename   = "passkey.rename"   // 重命名通行密钥
+	AuditPasskeyDelete   = "passkey.delete"   // 删除通行密钥
+
+	AuditEmailVerify     = "user.email_verify"    // 验证邮箱
+	AuditPasswordReset   = "user.password_reset"  // 通过邮件重置密码
+	AuditPasswordChange  = "user.password_change" // 修改密码，包括登录时按要求修改
+	AuditPhoneBind       = "user.phone_bind"      // 绑定并验证手机号
+	AuditLockoutClear    = "user.lockout_clear"   // 管理员解除账号锁定
+	AuditUserSignOut     = "user.sign_out"        // 管理员强制用户在所有设备上退出登录
+	AuditUserActivate    = "user.activate"        // 管理员启用账号
+	AuditUserDeactivate  = "user.deactivate"      // 管理员停用账号
+	AuditUserPasswordSet = "user.password_set"    // 管理员重置用户密码
+	AuditUserDelete      = "user.delete"          // 管理员删除账号
+	AuditUserImport      = "user.import"          // 管理员批量导入用户
+	AuditUserExport      = "user.export"          // 管理员导出用户
+
+	AuditProfileUpdate = "profile.update"        // 修改个人资料
+	AuditAvatarUpdate  = "profile.avatar_update" // 上传头像
+	AuditAvatarRemove  = "profile.avatar_remove" // 移除头像
+
+	AuditDeviceRevoke       = "device.revoke"        // 撤销一台登录设备
+	AuditDeviceRevokeOthers = "device.revoke_others" // 撤销当前设备以外的全部登录设备
+
+	AuditRoleCreate   = "role.create"   // 创建自定义角色
+	AuditRoleUpdate   = "role.update"   // 修改角色信息或权限
+	AuditRoleDelete   = "role.delete"   // 删除自定义角色
+	AuditRoleAssign   = "role.assign"   // 为用户分配角色
+	AuditRoleUnassign = "role.unassign" // 收回用户的角色
+
+	AuditApplicationAccess = "application.access" // 修改接入应用的访问策略
+
+	AuditGroupCreate       = "group.create"        // 创建分组
+	AuditGroupUpdate       = "group.update"        // 修改分组信息或上级分组
+	AuditGroupDelete       = "group.delete"        // 删除分组
+	AuditGroupMemberAdd    = "group.member_add"    // 将用户加入分组
+	AuditGroupMemberRemove = "group.member_remove" // 将用户移出分组
+	AuditGroupRoleAssign   = "group.role_assign"   // 为分组分配角色
+	AuditGroupRoleUnassign = "group.role_unassign" // 收回分组的角色
+
+	AuditTenantCreate = "tenant.create" // 创建租户
+	AuditTenantUpdate = "tenant.update" // 修改租户信息或停用租户
+)
+
+// AuditLog.ResourceType 的取值。
+const (
+	ResourceWechat           = "wechat"            // 微信绑定
+	ResourceGithub           = "github"            // Github 绑定
+	ResourceQQ               = "qq"                // QQ 绑定
+	ResourceExternalIdentity = "external_identity" // 通用外部身份绑定
+	ResourceProvider         = "provider"          // 第三方提供商
+	ResourceTOTP             = "totp"              // TOTP 两步验证配置
+	ResourcePasskey          = "passkey"           // 通行密钥
+	ResourceUser             = "user"              // 用户
+	ResourceUserProfile      = "user_profile"      // 用户资料
+	ResourceUserToken        = "user_token"        // 用户令牌（登录设备）
+	ResourceUserImportJob    = "user_import_job"   // 批量导入用户任务
+	ResourceRole             = "role"              // 角色
+	ResourceApplication      = "application"       // 接入应用
+	ResourceGroup            = "group"             // 分组
+	ResourceTenant           = "tenant"            // 租户
+)
@@ -0,0 +1,37 @@
+package constants
+
+// /oauth/authorize 的 prompt 参数取值。
+const (
+	PromptNone  = "none"  // 不与用户交互，没有可用的会话时直接返回 login_required
+	PromptLogin = "login" // 要求用户重新输入凭据，即使已有可用的会话
+)
+
+// 授权失败时通过回调地址返回给接入应用的 error 参数取值。
+const (
+	AuthorizeErrorInvalidRequest          = "invalid_request"           // 请求参数错误
+	AuthorizeErrorUnsupportedResponseType = "unsupported_response_type" // 不支持的 response_type
+	AuthorizeErrorLoginRequired           = "login_required"            // prompt=none 时需要用户登录
+	AuthorizeErrorAccessDenied            = "access_denied"             // 用户不在接入应用的访问策略允许范围内
+)
+
+// AuthorizationLog.FailureReason 的取值。
+const (
+	AuthorizeFailureCodeInvalid     = "code_invalid"     // 授权码不存在、已过期或已被使用
+	AuthorizeFailureRedirectInvalid = "redirect_invalid" // 回调地址与签发授权码时不一致
+	AuthorizeFailureUserInactive    = "user_inactive"    // 用户已被停用
+	AuthorizeFailureAccessDenied    = "access_denied"    // 用户不在接入应用的访问策略允许范围内
+)
+
+// Application.AccessPolicy 的取值。
+const (
+	AccessPolicyOpen      = "open"      // 所有有效用户都可以登录
+	AccessPolicyRole      = "role"      // 只有持有指定角色之一的用户可以登录
+	AccessPolicyAllowlist = "allowlist" // 只有被列入名单的用户或名单中分组（含下级分组）的成员可以登录
+)
+
+// ApplicationAccess.SubjectType 的取值。
+const (
+	AccessSubjectRole  = "role"  // 角色，用于 role 策略
+	AccessSubjectUser  = "user"  // 用户，用于 allowlist 策略
+	AccessSubjectGroup = "group" // 分组，用于 allowlist 策略
+)
@@ -0,0 +1,18 @@
+package constants
+
+// 请求上下文中注入的键名，与 bamboo-base 的 xConsts.ContextDatabase 等键配合使用。
+const (
+	ContextLogger          = "sso_logger"      // 日志记录器实例
+	ContextSSOConfig       = "sso_config"      // SSO 业务配置实例
+	ContextWebAuthn        = "sso_webauthn"    // 通行密钥依赖方实例
+	ContextMailer          = "sso_mailer"      // 邮件发送器实例
+	ContextSMSSender       = "sso_sms"         // 短信发送器实例
+	ContextLogout          = "sso_logout"      // 后端通道登出通知发送器实例
+	ContextStorage         = "sso_storage"     // 文件存储实例
+	ContextPasswordPolicy  = "sso_password"    // 密码策略实例
+	ContextTenant          = "sso_tenant"      // 当前请求所属的租户，由租户中间件写入
+	ContextUser            = "sso_user"        // 当前登录用户，由认证中间件写入
+	ContextUserToken       = "sso_token"       // 当前请求使用的用户令牌，由认证中间件写入
+	ContextUserRoles       = "sso_roles"       // 当前登录用户持有的有效角色名称，由认证中间件写入
+	ContextUserPermissions = "sso_permissions" // 当前登录用户的有效角色被授予的权限代码，由认证中间件写入
+)
@@ -0,0 +1,15 @@
+package constants
+
+// UserImportJob.Format 的取值，同时是导出用户时支持的文件格式。
+const (
+	ImportFormatCSV  = "csv"  // CSV 文件，首行为列名
+	ImportFormatJSON = "json" // JSON 数组，每个元素为一个用户
+)
+
+// UserImportJob.Status 的取值。
+const (
+	ImportStatusPending   = "pending"   // 等待处理
+	ImportStatusRunning   = "running"   // 正在导入
+	ImportStatusCompleted = "completed" // 全部行已处理完毕，部分行可能失败
+	ImportStatusFailed    = "failed"    // 任务因错误中断
+)
@@ -0,0 +1,70 @@
+package constants
+
+// LoginLog.LoginType 的取值。
+const (
+	LoginTypePassword   = "password"    // 账号密码登录
+	LoginTypeThirdParty = "third_party" // 第三方平台登录
+	LoginTypePasskey    = "passkey"     // 通行密钥登录
+	LoginTypeSMS        = "sms"         // 短信验证码登录
+)
+
+// ThirdPartyProvider.Code 的取值。
+const (
+	ProviderWechat     = "wechat"      // 微信开放平台网站应用（扫码登录）
+	ProviderWechatMP   = "wechat_mp"   // 微信公众号网页授权
+	ProviderWechatMini = "wechat_mini" // 微信小程序
+)
+
+// ThirdPartyProvider.Type 的取值。
+const (
+	ProviderTypeOIDC   = "oidc"   // 通用 OIDC 驱动，通过服务发现获取端点
+	ProviderTypeOAuth2 = "oauth2" // 通用 OAuth2 驱动，端点与声明映射由管理员配置
+	ProviderTypeWechat = "wechat" // 微信专用驱动
+)
+
+// 系统内置角色名称。
+const (
+	RoleSuperAdmin = "SUPER_ADMIN"
+	RoleAdmin      = "ADMIN"
+	RoleUser       = "USER"
+)
+
+// LoginLog.FailureReason 的取值。
+const (
+	FailureThirdPartyError = "third_party_error" // 第三方平台接口调用失败
+	FailureUserInactive    = "user_inactive"     // 用户已被停用
+	FailureBindingInactive = "binding_inactive"  // 第三方绑定已被停用
+	FailureUserNotFound    = "user_not_found"    // 账号不存在
+	FailurePasswordInvalid = "password_invalid"  // 密码错误
+	FailureTOTPInvalid     = "totp_invalid"      // 两步验证动态口令错误
+	FailureRecoveryInvalid = "recovery_invalid"  // 两步验证恢复码错误
+	FailurePasskeyInvalid  = "passkey_invalid"   // 通行密钥验证失败
+	FailureSMSCodeInvalid  = "sms_code_invalid"  // 短信验证码错误或已过期
+	FailureAccountLocked   = "account_locked"    // 登录失败次数过多，账号已被临时锁定
+)
+
+// 限流的接口类别，对应 config.RateLimitConfig 中的规则。
+const (
+	RateLimitLogin = "login" // 登录与两步验证接口
+	RateLimitCode  = "code"  // 发送验证码与邮件的接口
+	RateLimitToken = "token" // 令牌签发与刷新接口
+)
+
+// 两步验证可用的方式，对应 dto.MFAChallenge.Methods。
+const (
+	MFAMethodTOTP     = "totp"     // 身份验证器动态口令
+	MFAMethodRecovery = "recovery" // 一次性恢复码
+	MFAMethodPasskey  = "passkey"  // 通行密钥
+)
+
+// 登录时必须修改密码的原因，对应 dto.PasswordChallenge.Reason。
+const (
+	PasswordChangeRequired = "required" // 初始化或由管理员设置的密码，必须修改后才能使用
+	PasswordChangeExpired  = "expired"  // 密码已超过最长使用期限
+)
+
+// 第三方授权回调的处理方式，对应 dto.OAuthCallback.Action。
+const (
+	OAuthActionLogin = "login" // 使用第三方身份登录
+	OAuthActionLink  = "link"  // 为已登录用户绑定第三方身份
+)
@@ -0,0 +1,24 @@
+package constants
+
+// 系统内置的权限代码，格式为 "资源:操作"；read 允许查看，write 允许创建、修改与删除。
+const (
+	PermissionUserRead         = "user:read"         // 查看用户、登录设备与锁定状态
+	PermissionUserWrite        = "user:write"        // 管理用户、强制退出登录与解除锁定
+	PermissionApplicationRead  = "application:read"  // 查看接入应用与授权日志
+	PermissionApplicationWrite = "application:write" // 管理接入应用
+	PermissionRoleRead         = "role:read"         // 查看角色与权限
+	PermissionRoleWrite        = "role:write"        // 管理角色、角色权限与用户角色
+	PermissionProviderRead     = "provider:read"     // 查看第三方登录提供商
+	PermissionProviderWrite    = "provider:write"    // 管理第三方登录提供商
+	PermissionSystemRead       = "system:read"       // 查看系统配置与审计日志
+	PermissionSystemWrite      = "system:write"      // 修改系统配置
+)
+
+// 权限分类，对应权限代码中的资源部分。
+const (
+	PermissionCategoryUser        = "user"        // 用户管理
+	PermissionCategoryApplication = "application" // 接入应用管理
+	PermissionCategoryRole        = "role"        // 角色管理
+	PermissionCategoryProvider    = "provider"    // 第三方登录提供商管理
+	PermissionCategorySystem      = "system"      // 系统管理
+)
@@ -0,0 +1,21 @@
+package constants
+
+// Redis 键名格式，统一使用 "sso:" 前缀区分业务。
+const (
+	RedisOAuthState     = "sso:oauth:state:%s"     // 第三方登录 state，值为授权上下文 JSON
+	RedisMFAChallenge   = "sso:mfa:challenge:%s"   // 两步验证挑战，值为通过第一步验证的登录上下文 JSON
+	RedisPasswordChange = "sso:password:change:%s" // 登录时修改密码的挑战，值为通过验证的登录上下文 JSON
+	RedisTOTPAttempts   = "sso:totp:attempts:%s"   // 已登录用户管理两步验证时的动态口令错误次数，按用户UUID区分
+	RedisWebAuthn       = "sso:webauthn:%s"        // 通行密钥注册与登录流程，值为流程上下文 JSON
+	RedisEmailToken     = "sso:email:%s:%s"        // 邮件令牌，按用途区分，值为令牌上下文 JSON
+	RedisSMSCode        = "sso:sms:code:%s:%s"     // 短信验证码，按用途与对象区分，值为验证码摘要与错误次数的哈希
+	RedisSMSCooldown    = "sso:sms:cooldown:%s"    // 短信发送冷却，按手机号盲索引区分
+	RedisRateLimit      = "sso:ratelimit:%s:%s:%s" // 限流滑动窗口，按接口类别、维度与计数对象区分，值为请求时间的有序集合
+	RedisLockout        = "sso:lockout:%s"         // 账号锁定状态，按用户UUID区分，值为锁定状态 JSON
+	RedisSession        = "sso:session:%s"         // SSO 会话，按会话ID（会话 Cookie 的摘要）区分，值为会话 JSON
+	RedisUserSessions   = "sso:session:user:%s"    // 用户的 SSO 会话ID集合
+	RedisAuthorize      = "sso:authorize:%s"       // 等待用户登录的接入应用授权请求，值为请求参数 JSON
+	RedisLogoutQueue    = "sso:logout:queue"       // 待投递的后端通道登出通知，分值为下次投递时间的有序集合
+	RedisAuthCache      = "sso:auth:%s"            // 访问令牌的认证缓存，按访问令牌的摘要区分，值为加密的用户、令牌与角色
+	RedisUserAuthCache  = "sso:auth:user:%s"       // 用户的认证缓存键集合，用户信息、令牌或角色变更时据此清除缓存
+)
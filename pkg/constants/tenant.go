@@ -0,0 +1,4 @@
+package constants
+
+// TenantDefault 是默认租户的代码，升级前已有的数据归属默认租户，未匹配到其他租户的请求同样使用默认租户。
+const TenantDefault = "default"
@@ -0,0 +1,181 @@
+package logout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	pollInterval = time.Second      // 检查到期通知的间隔
+	pollBatch    = 100              // 每次取出的到期通知数量上限
+	retryBase    = 10 * time.Second // 首次重试的等待时间，此后每次重试乘以 3
+)
+
+// errRejected 表示接入应用明确拒绝了登出通知，重试也不会成功。
+var errRejected = errors.New("接入应用拒绝了登出通知")
+
+// Delivery 表示一条等待投递的后端通道登出通知。
+//
+// 字段说明：
+//   - ID: 通知编号，使内容相同的通知在队列中互不覆盖。
+//   - URL: 接入应用的后端通道登出地址。
+//   - Token: 已签名的登出令牌。
+//   - Attempts: 已投递的次数。
+type Delivery struct {
+	ID       string `json:"id"`
+	URL      string `json:"url"`
+	Token    string `json:"token"`
+	Attempts int    `json:"attempts"`
+}
+
+// Sender 在后台向接入应用投递后端通道登出通知。
+//
+// 通知保存在 Redis 的有序集合中，分值为下次投递的时间，多个服务实例可以同时运行 Run，
+// 每条通知只会被其中一个实例取出。投递失败时按指数退避重新放回队列，达到最大次数或被接入应用拒绝后放弃。
+type Sender struct {
+	rdb    *redis.Client
+	cfg    *config.LogoutConfig
+	client *http.Client
+	log    *zap.Logger
+}
+
+// New 创建后端通道登出通知的发送器。
+func New(rdb *redis.Client, cfg *config.LogoutConfig, log *zap.Logger) *Sender {
+	return &Sender{
+		rdb:    rdb,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		log:    log.Named("LOGOUT"),
+	}
+}
+
+// Enqueue 将登出通知加入队列，由 Run 在后台立即投递。
+func (s *Sender) Enqueue(ctx context.Context, logoutURL, token string) error {
+	return s.schedule(ctx, &Delivery{ID: utility.RandomToken(12), URL: logoutURL, Token: token}, time.Now())
+}
+
+// Run 持续投递到期的登出通知，直到 ctx 被取消。
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.poll(ctx); err != nil && ctx.Err() == nil {
+				s.log.Error("读取登出通知队列失败", zap.Error(err))
+			}
+		}
+	}
+}
+
+// poll 取出到期的通知并发投递，等待本批全部完成后返回。
+func (s *Sender) poll(ctx context.Context) error {
+	members, err := s.rdb.ZRangeByScore(ctx, constants.RedisLogoutQueue, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Count: pollBatch,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, member := range members {
+		// 只有成功移除通知的实例负责投递
+		removed, err := s.rdb.ZRem(ctx, constants.RedisLogoutQueue, member).Result()
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			continue
+		}
+		var delivery Delivery
+		if err := jsoniter.UnmarshalFromString(member, &delivery); err != nil {
+			s.log.Error("丢弃无法解析的登出通知", zap.Error(err))
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.deliver(ctx, &delivery)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// deliver 投递一条通知，失败时视情况重新放回队列。
+func (s *Sender) deliver(ctx context.Context, delivery *Delivery) {
+	err := s.post(ctx, delivery)
+	if err == nil {
+		return
+	}
+	delivery.Attempts++
+	if errors.Is(err, errRejected) || delivery.Attempts >= s.cfg.MaxAttempts {
+		s.log.Error("投递登出通知失败，放弃重试",
+			zap.String("url", delivery.URL), zap.Int("attempts", delivery.Attempts), zap.Error(err))
+		return
+	}
+
+	wait := retryBase
+	for range delivery.Attempts - 1 {
+		wait *= 3
+	}
+	s.log.Warn("投递登出通知失败，稍后重试",
+		zap.String("url", delivery.URL), zap.Int("attempts", delivery.Attempts), zap.Duration("wait", wait), zap.Error(err))
+	// 服务正在停止时 ctx 已被取消，改用独立的上下文放回队列，避免通知丢失
+	if err := s.schedule(context.WithoutCancel(ctx), delivery, time.Now().Add(wait)); err != nil {
+		s.log.Error("登出通知放回队列失败", zap.String("url", delivery.URL), zap.Error(err))
+	}
+}
+
+// post 以表单参数 logout_token 向接入应用提交登出令牌。
+//
+// 接入应用返回 2xx 表示处理成功；返回 4xx 表示拒绝该令牌，不再重试；其余情况均会重试。
+func (s *Sender) post(ctx context.Context, delivery *Delivery) error {
+	body := url.Values{"logout_token": {delivery.Token}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errRejected, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: 状态码 %d", errRejected, resp.StatusCode)
+	default:
+		return fmt.Errorf("接入应用返回状态码 %d", resp.StatusCode)
+	}
+}
+
+// schedule 将通知放入队列，在 due 时刻之后投递。
+func (s *Sender) schedule(ctx context.Context, delivery *Delivery, due time.Time) error {
+	member, err := jsoniter.MarshalToString(delivery)
+	if err != nil {
+		return err
+	}
+	return s.rdb.ZAdd(ctx, constants.RedisLogoutQueue, redis.Z{Score: float64(due.UnixMilli()), Member: member}).Err()
+}
@@ -0,0 +1,49 @@
+package logout
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// EventBackchannelLogout 是登出令牌 events 声明中标识后端通道登出事件的成员名。
+const EventBackchannelLogout = "http://schemas.openid.net/event/backchannel-logout"
+
+// tokenHeader 是登出令牌的 JOSE 头部，使用接入应用的应用密钥以 HS256 签名。
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"logout+jwt"}`))
+
+// Claims 表示 OpenID Connect 后端通道登出令牌的声明。
+//
+// 字段说明：
+//   - Issuer: 签发者，即 SSO 的外部访问地址。
+//   - Audience: 接收令牌的接入应用的应用标识符。
+//   - IssuedAt: 签发时间的 Unix 秒数。
+//   - ExpiresAt: 过期时间的 Unix 秒数。
+//   - ID: 令牌的唯一编号，接入应用可据此拒绝重放的令牌。
+//   - Subject: 退出登录的用户UUID。
+//   - SessionID: 被结束的 SSO 会话ID，与签发令牌时返回的 sid 一致。
+//   - Events: 固定包含 EventBackchannelLogout 一个成员。
+type Claims struct {
+	Issuer    string              `json:"iss"`
+	Audience  string              `json:"aud"`
+	IssuedAt  int64               `json:"iat"`
+	ExpiresAt int64               `json:"exp"`
+	ID        string              `json:"jti"`
+	Subject   string              `json:"sub,omitempty"`
+	SessionID string              `json:"sid,omitempty"`
+	Events    map[string]struct{} `json:"events"`
+}
+
+// Sign 使用接入应用的应用密钥签名登出令牌，返回 JWS 紧凑序列化格式的令牌。
+func Sign(key string, claims *Claims) (string, error) {
+	payload, err := jsoniter.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
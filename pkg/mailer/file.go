@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/utility"
+	"go.uber.org/zap"
+)
+
+// File 将邮件以 .eml 文件写入本地目录并记录日志，用于本地开发与测试，不会真正发出邮件。
+type File struct {
+	from *mail.Address
+	dir  string
+	log  *zap.Logger
+}
+
+// NewFile 创建写入本地目录的邮件发送器，目录不存在时自动创建。
+func NewFile(from, dir string, log *zap.Logger) (*File, error) {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("发件人地址无效: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &File{from: address, dir: dir, log: log.Named("MAIL")}, nil
+}
+
+// Send 将邮件写入文件，并在日志中记录收件人、主题与文件路径。
+func (f *File) Send(_ context.Context, message *Message) error {
+	body, err := build(f.from, message)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), utility.RandomToken(6))
+	path := filepath.Join(f.dir, name)
+	if err := os.WriteFile(path, body, 0o640); err != nil {
+		return err
+	}
+	f.log.Info("邮件已写入文件", zap.String("to", message.To), zap.String("subject", message.Subject), zap.String("path", path))
+	return nil
+}
@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+	"go.uber.org/zap"
+)
+
+// Message 表示一封待发送的邮件，Text 与 HTML 为同一内容的纯文本与 HTML 版本。
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer 是邮件发送器的统一接口，具体的发送方式由配置中的 driver 决定。
+type Mailer interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// New 根据配置创建邮件发送器，log 供 file 方式记录邮件的写入位置。
+func New(cfg *config.EmailConfig, log *zap.Logger) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTP(cfg.From, &cfg.SMTP)
+	case "file":
+		return NewFile(cfg.From, cfg.FileDir, log)
+	default:
+		return nil, fmt.Errorf("不支持的邮件发送方式 %q", cfg.Driver)
+	}
+}
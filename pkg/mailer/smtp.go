@@ -0,0 +1,108 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+)
+
+// SMTP 通过 SMTP 服务器发送邮件，每封邮件使用一个新的连接。
+type SMTP struct {
+	from *mail.Address
+	cfg  config.SMTPConfig
+}
+
+// NewSMTP 创建 SMTP 邮件发送器，from 为发件人地址。
+func NewSMTP(from string, cfg *config.SMTPConfig) (*SMTP, error) {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("发件人地址无效: %w", err)
+	}
+	if cfg.Host == "" || cfg.Port <= 0 {
+		return nil, fmt.Errorf("未配置 SMTP 服务器地址")
+	}
+	switch cfg.Security {
+	case "starttls", "tls", "none":
+	default:
+		return nil, fmt.Errorf("不支持的 SMTP 加密方式 %q", cfg.Security)
+	}
+	return &SMTP{from: address, cfg: *cfg}, nil
+}
+
+// Send 连接 SMTP 服务器并发送邮件。
+func (s *SMTP) Send(ctx context.Context, message *Message) error {
+	body, err := build(s.from, message)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return err
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.cfg.Security == "starttls" {
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(body); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dial 建立到 SMTP 服务器的连接，隐式 TLS 方式下直接建立 TLS 连接。
+func (s *SMTP) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	var conn net.Conn
+	var err error
+	if s.cfg.Security == "tls" {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: s.cfg.Host}}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	htmlTemplate "html/template"
+	"text/template"
+)
+
+// 邮件模板名称，对应 templates 目录下的同名 .tmpl 文件。
+const (
+	TemplateVerifyEmail   = "verify_email"   // 验证邮箱
+	TemplateResetPassword = "reset_password" // 重置密码
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var (
+	// textTemplates 用于渲染主题与纯文本正文。
+	textTemplates = template.Must(template.ParseFS(templateFS, "templates/*.tmpl"))
+	// htmlTemplates 用于渲染 HTML 正文，对数据进行上下文相关的转义。
+	htmlTemplates = htmlTemplate.Must(htmlTemplate.ParseFS(templateFS, "templates/*.tmpl"))
+)
+
+// Render 使用指定模板渲染一封发往 to 的邮件。
+//
+// 每个模板文件需要定义 "<名称>.subject"、"<名称>.text" 与 "<名称>.html" 三个模板，
+// 分别作为邮件主题、纯文本正文与 HTML 正文。
+func Render(name, to string, data any) (*Message, error) {
+	var subject, text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&subject, name+".subject", data); err != nil {
+		return nil, err
+	}
+	if err := textTemplates.ExecuteTemplate(&text, name+".text", data); err != nil {
+		return nil, err
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return nil, err
+	}
+	return &Message{
+		To:      to,
+		Subject: string(bytes.TrimSpace(subject.Bytes())),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
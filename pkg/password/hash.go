@@ -0,0 +1,235 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// AlgorithmArgon2id 表示 argon2id 算法，哈希以 PHC 字符串保存，如 "$argon2id$v=19$m=65536,t=3,p=4$<盐>$<摘要>"。
+	AlgorithmArgon2id = "argon2id"
+	// AlgorithmBcrypt 表示 bcrypt 算法，哈希以其自带的 "$2a$<成本>$<盐与摘要>" 格式保存。
+	AlgorithmBcrypt = "bcrypt"
+)
+
+const (
+	// argon2SaltLength 是 argon2id 使用的盐长度（字节）。
+	argon2SaltLength = 16
+	// argon2KeyLength 是 argon2id 输出的摘要长度（字节）。
+	argon2KeyLength = 32
+)
+
+// 哈希参数的上限，超出上限的哈希视为格式错误，以免导入或被篡改的哈希使一次登录耗尽服务器的内存或 CPU。
+// 配置的参数同样不能超出上限。
+const (
+	argon2MaxMemory      = 256 * 1024 // argon2id 的最大内存开销（KiB）
+	argon2MaxIterations  = 16         // argon2id 的最大迭代次数
+	argon2MaxParallelism = 16         // argon2id 的最大并行度
+	argon2MaxSaltLength  = 64         // argon2id 的最大盐长度（字节）
+	argon2MaxKeyLength   = 64         // argon2id 的最大摘要长度（字节）
+	bcryptMaxCost        = 16         // bcrypt 的最大计算成本
+)
+
+// scheme 是一种密码哈希算法，负责识别、计算与校验该算法的哈希。
+type scheme interface {
+	// name 返回算法名称。
+	name() string
+	// identify 判断哈希是否属于该算法。
+	identify(encoded string) bool
+	// valid 判断该算法的哈希格式与参数是否有效。
+	valid(encoded string) bool
+	// hash 按当前配置计算密码的哈希，只用于校验的旧系统算法返回错误。
+	hash(password string) (string, error)
+	// verify 校验密码与哈希是否匹配，并返回哈希的参数是否与当前配置不同；哈希格式错误时视为不匹配。
+	verify(encoded, password string) (match, outdated bool)
+}
+
+// newSchemes 根据配置创建全部支持的哈希算法，并返回新密码使用的算法。
+//
+// 新密码只能使用 argon2id 或 bcrypt；从旧系统导入的 PBKDF2 与加盐 MD5 哈希只用于校验，登录成功后重新计算。
+func newSchemes(cfg *config.HashConfig) ([]scheme, scheme, error) {
+	if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcryptMaxCost {
+		return nil, nil, fmt.Errorf("bcrypt 的计算成本应在 %d 到 %d 之间", bcrypt.MinCost, bcryptMaxCost)
+	}
+	if cfg.Argon2Memory == 0 || cfg.Argon2Memory > argon2MaxMemory {
+		return nil, nil, fmt.Errorf("argon2id 的内存开销应在 1 到 %d KiB 之间", argon2MaxMemory)
+	}
+	if cfg.Argon2Iterations == 0 || cfg.Argon2Iterations > argon2MaxIterations {
+		return nil, nil, fmt.Errorf("argon2id 的迭代次数应在 1 到 %d 之间", argon2MaxIterations)
+	}
+	if cfg.Argon2Parallelism == 0 || cfg.Argon2Parallelism > argon2MaxParallelism {
+		return nil, nil, fmt.Errorf("argon2id 的并行度应在 1 到 %d 之间", argon2MaxParallelism)
+	}
+	hashers := []scheme{
+		&argon2Scheme{memory: cfg.Argon2Memory, iterations: cfg.Argon2Iterations, parallelism: cfg.Argon2Parallelism},
+		&bcryptScheme{cost: cfg.BcryptCost},
+	}
+	for _, s := range hashers {
+		if s.name() == cfg.Algorithm {
+			return append(hashers, &pbkdf2Scheme{}, &md5Scheme{}), s, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("不支持的密码哈希算法: %s", cfg.Algorithm)
+}
+
+// Recognize 判断哈希是否为支持的算法且格式有效，用于校验从旧系统导入的密码哈希。
+func (p *Policy) Recognize(encoded string) bool {
+	for _, s := range p.schemes {
+		if s.identify(encoded) {
+			return s.valid(encoded)
+		}
+	}
+	return false
+}
+
+// Hash 使用配置的算法计算新密码的哈希。
+func (p *Policy) Hash(password string) (string, error) {
+	return p.hasher.hash(password)
+}
+
+// Verify 校验密码与哈希是否匹配，哈希可以是任一支持的算法。
+//
+// 密码匹配且哈希的算法或参数与当前配置不同时 rehash 为 true，调用方应使用 Hash 重新计算并保存；
+// 无法识别的哈希视为不匹配。
+func (p *Policy) Verify(encoded, password string) (match, rehash bool) {
+	for _, s := range p.schemes {
+		if !s.identify(encoded) {
+			continue
+		}
+		match, outdated := s.verify(encoded, password)
+		return match, match && (outdated || s != p.hasher)
+	}
+	return false, false
+}
+
+// VerifyDummy 使用预先计算的哈希完成一次耗时相同的校验，用于账号不存在时使响应耗时与账号存在时接近。
+func (p *Policy) VerifyDummy(password string) {
+	p.Verify(p.dummy, password)
+}
+
+// bcryptScheme 是 bcrypt 算法。
+type bcryptScheme struct {
+	cost int
+}
+
+func (b *bcryptScheme) name() string {
+	return AlgorithmBcrypt
+}
+
+func (b *bcryptScheme) identify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (b *bcryptScheme) valid(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err == nil && cost <= bcryptMaxCost && len(encoded) == 60
+}
+
+func (b *bcryptScheme) hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (b *bcryptScheme) verify(encoded, password string) (bool, bool) {
+	if !b.valid(encoded) || bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return true, err != nil || cost != b.cost
+}
+
+// argon2Scheme 是 argon2id 算法。
+type argon2Scheme struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+func (a *argon2Scheme) name() string {
+	return AlgorithmArgon2id
+}
+
+func (a *argon2Scheme) identify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (a *argon2Scheme) valid(encoded string) bool {
+	_, ok := parseArgon2(encoded)
+	return ok
+}
+
+func (a *argon2Scheme) hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.iterations, a.memory, a.parallelism, argon2KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, a.memory, a.iterations, a.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (a *argon2Scheme) verify(encoded, password string) (bool, bool) {
+	parsed, ok := parseArgon2(encoded)
+	if !ok {
+		return false, false
+	}
+	computed := argon2.IDKey([]byte(password), parsed.salt, parsed.iterations, parsed.memory, parsed.parallelism, uint32(len(parsed.key)))
+	if subtle.ConstantTimeCompare(computed, parsed.key) != 1 {
+		return false, false
+	}
+	outdated := parsed.memory != a.memory || parsed.iterations != a.iterations || parsed.parallelism != a.parallelism ||
+		len(parsed.salt) != argon2SaltLength || len(parsed.key) != argon2KeyLength
+	return true, outdated
+}
+
+// argon2Hash 是解析后的 argon2id 哈希。
+type argon2Hash struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// parseArgon2 解析 "$argon2id$v=19$m=65536,t=3,p=4$<盐>$<摘要>" 格式的哈希，盐与摘要为不带填充的标准 Base64。
+//
+// 参数、盐或摘要的长度超出上限时视为格式错误。
+func parseArgon2(encoded string) (*argon2Hash, bool) {
+	// 按 "$" 切分后第一段为空
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return nil, false
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, false
+	}
+	var parsed argon2Hash
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &parsed.memory, &parsed.iterations, &parsed.parallelism); err != nil {
+		return nil, false
+	}
+	if parsed.memory == 0 || parsed.iterations == 0 || parsed.parallelism == 0 ||
+		parsed.memory > argon2MaxMemory || parsed.iterations > argon2MaxIterations || parsed.parallelism > argon2MaxParallelism {
+		return nil, false
+	}
+	var err error
+	if parsed.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil || len(parsed.salt) > argon2MaxSaltLength {
+		return nil, false
+	}
+	if parsed.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(parsed.key) == 0 || len(parsed.key) > argon2MaxKeyLength {
+		return nil, false
+	}
+	return &parsed, true
+}
@@ -0,0 +1,152 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+)
+
+const testPassword = "Secret#2024"
+
+// newTestPolicy 创建使用指定算法的密码策略，哈希参数取最小值以加快测试。
+func newTestPolicy(t *testing.T, algorithm string) *Policy {
+	t.Helper()
+	policy, err := New(&config.PasswordConfig{
+		MaxLength: 128,
+		Hash: config.HashConfig{
+			Algorithm:         algorithm,
+			BcryptCost:        4,
+			Argon2Memory:      1024,
+			Argon2Iterations:  1,
+			Argon2Parallelism: 1,
+		},
+	})
+	if err != nil {
+		t.Fatalf("New(%s): %v", algorithm, err)
+	}
+	return policy
+}
+
+func TestHashAndVerify(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmArgon2id, AlgorithmBcrypt} {
+		t.Run(algorithm, func(t *testing.T) {
+			policy := newTestPolicy(t, algorithm)
+			encoded, err := policy.Hash(testPassword)
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if !policy.Recognize(encoded) {
+				t.Errorf("Recognize(%q) = false, want true", encoded)
+			}
+			if match, rehash := policy.Verify(encoded, testPassword); !match || rehash {
+				t.Errorf("Verify(correct) = (%v, %v), want (true, false)", match, rehash)
+			}
+			if match, _ := policy.Verify(encoded, "wrong-password"); match {
+				t.Error("Verify(wrong) = true, want false")
+			}
+		})
+	}
+}
+
+func TestVerifyRehashesOtherAlgorithm(t *testing.T) {
+	encoded, err := newTestPolicy(t, AlgorithmBcrypt).Hash(testPassword)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if match, rehash := newTestPolicy(t, AlgorithmArgon2id).Verify(encoded, testPassword); !match || !rehash {
+		t.Errorf("Verify = (%v, %v), want (true, true)", match, rehash)
+	}
+}
+
+func TestVerifyLegacy(t *testing.T) {
+	policy := newTestPolicy(t, AlgorithmArgon2id)
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"django pbkdf2_sha256", "pbkdf2_sha256$1000$abcsalt$a1dW9EQhNEkNK0X173L7RsC5IjMLZ2qU4tLhpZQpqVY="},
+		{"passlib pbkdf2-sha512", "$pbkdf2-sha512$2000$MDEyMzQ1Njc4OWFiY2RlZg$FZbRGmum.BvfV3OSX1f5zcHBcMUDiGFjnUGrV1PN90Qt1qeskdCVNtF1moAI.I3UFJWK0ifHNlD0Vm68qqCXLw"},
+		{"passlib pbkdf2 sha1", "$pbkdf2$2000$MDEyMzQ1Njc4OWFiY2RlZg$zSITaIlBqpVnxX4uDnmqnNmqylA"},
+		{"md5 prefix", "$md5-salted$prefix$xy$d1029bb4416a727fcf6aaccdf44509f4"},
+		{"md5 suffix", "$md5-salted$suffix$xy$dee63f89d5d903d62333dc36e9e889f5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !policy.Recognize(tt.encoded) {
+				t.Errorf("Recognize = false, want true")
+			}
+			// 旧系统的哈希校验通过后总是需要重新计算
+			if match, rehash := policy.Verify(tt.encoded, testPassword); !match || !rehash {
+				t.Errorf("Verify(correct) = (%v, %v), want (true, true)", match, rehash)
+			}
+			if match, _ := policy.Verify(tt.encoded, "wrong-password"); match {
+				t.Error("Verify(wrong) = true, want false")
+			}
+		})
+	}
+}
+
+func TestRecognizeBounds(t *testing.T) {
+	policy := newTestPolicy(t, AlgorithmArgon2id)
+	const (
+		salt = "c2FsdHNhbHRzYWx0c2FsdA"
+		key  = "aGFzaGhhc2hoYXNoaGFzaGhhc2hoYXNoaGFzaGhhc2g"
+	)
+	bcryptBody := "abcdefghijklmnopqrstuu" + "abcdefghijklmnopqrstuvwxyz01234"
+	tests := []struct {
+		name    string
+		encoded string
+		want    bool
+	}{
+		{"argon2id within bounds", "$argon2id$v=19$m=65536,t=3,p=4$" + salt + "$" + key, true},
+		{"argon2id memory too large", "$argon2id$v=19$m=4294967295,t=3,p=4$" + salt + "$" + key, false},
+		{"argon2id iterations too large", "$argon2id$v=19$m=65536,t=1000,p=4$" + salt + "$" + key, false},
+		{"argon2id parallelism too large", "$argon2id$v=19$m=65536,t=3,p=200$" + salt + "$" + key, false},
+		{"argon2id key too long", "$argon2id$v=19$m=65536,t=3,p=4$" + salt + "$" + strings.Repeat("A", 100), false},
+		{"bcrypt within bounds", "$2a$10$" + bcryptBody, true},
+		{"bcrypt cost too large", "$2a$31$" + bcryptBody, false},
+		{"pbkdf2 iterations too large", "pbkdf2_sha256$2147483647$abcsalt$a1dW9EQhNEkNK0X173L7RsC5IjMLZ2qU4tLhpZQpqVY=", false},
+		{"pbkdf2 key longer than digest", "$pbkdf2$2000$MDEyMzQ1Njc4OWFiY2RlZg$" + key, false},
+		{"pbkdf2 unknown digest", "pbkdf2_md5$1000$abcsalt$a1dW9EQhNEkNK0X173L7RsC5IjMLZ2qU4tLhpZQpqVY=", false},
+		{"md5 invalid digest", "$md5-salted$prefix$xy$zz", false},
+		{"md5 unknown position", "$md5-salted$middle$xy$d1029bb4416a727fcf6aaccdf44509f4", false},
+		{"unknown scheme", "plain-text-password", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Recognize(tt.encoded); got != tt.want {
+				t.Errorf("Recognize = %v, want %v", got, tt.want)
+			}
+			if !tt.want {
+				// 超出上限的哈希不能进入计算，直接视为不匹配
+				if match, _ := policy.Verify(tt.encoded, testPassword); match {
+					t.Error("Verify = true, want false")
+				}
+			}
+		})
+	}
+}
+
+func TestNewRejectsInvalidHashConfig(t *testing.T) {
+	valid := config.HashConfig{Algorithm: AlgorithmArgon2id, BcryptCost: 10, Argon2Memory: 65536, Argon2Iterations: 3, Argon2Parallelism: 4}
+	tests := []struct {
+		name   string
+		modify func(cfg *config.HashConfig)
+	}{
+		{"unknown algorithm", func(cfg *config.HashConfig) { cfg.Algorithm = "md5" }},
+		{"bcrypt cost too large", func(cfg *config.HashConfig) { cfg.BcryptCost = bcryptMaxCost + 1 }},
+		{"argon2id memory too large", func(cfg *config.HashConfig) { cfg.Argon2Memory = argon2MaxMemory + 1 }},
+		{"argon2id iterations too large", func(cfg *config.HashConfig) { cfg.Argon2Iterations = argon2MaxIterations + 1 }},
+		{"argon2id parallelism too large", func(cfg *config.HashConfig) { cfg.Argon2Parallelism = argon2MaxParallelism + 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			if _, err := New(&config.PasswordConfig{MaxLength: 128, Hash: cfg}); err == nil {
+				t.Error("New = nil error, want error")
+			}
+		})
+	}
+}
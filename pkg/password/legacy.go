@@ -0,0 +1,186 @@
+package password
+
+import (
+	"crypto/md5"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// errVerifyOnly 表示旧系统的哈希算法只用于校验，不能用于计算新密码的哈希。
+var errVerifyOnly = errors.New("该哈希算法只用于校验从旧系统导入的密码")
+
+// adaptedBase64 是 passlib 使用的 Base64 编码，以 "." 代替 "+" 且不带填充。
+var adaptedBase64 = base64.NewEncoding("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789./").WithPadding(base64.NoPadding)
+
+const (
+	// pbkdf2MaxIterations 是 PBKDF2 的最大迭代次数，超出上限的哈希视为格式错误，以免一次登录耗尽服务器的 CPU。
+	pbkdf2MaxIterations = 2_000_000
+	// pbkdf2MaxSaltLength 是 PBKDF2 的最大盐长度（字节）。
+	pbkdf2MaxSaltLength = 64
+)
+
+// pbkdf2Digests 是 PBKDF2 支持的摘要算法。
+var pbkdf2Digests = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// pbkdf2Scheme 是从旧系统导入的 PBKDF2 哈希，支持以下两种格式：
+//   - passlib："$pbkdf2-sha256$<迭代次数>$<盐>$<摘要>"，盐与摘要使用 passlib 的 Base64 编码；SHA-1 的前缀为 "$pbkdf2$"，
+//     SHA-512 的前缀为 "$pbkdf2-sha512$"。
+//   - Django："pbkdf2_sha256$<迭代次数>$<盐>$<摘要>"，盐为原文，摘要使用标准 Base64 编码；也支持 "pbkdf2_sha1"。
+type pbkdf2Scheme struct{}
+
+// pbkdf2Hash 是解析后的 PBKDF2 哈希。
+type pbkdf2Hash struct {
+	digest     func() hash.Hash
+	iterations int
+	salt       []byte
+	key        []byte
+}
+
+func (p *pbkdf2Scheme) name() string {
+	return "pbkdf2"
+}
+
+func (p *pbkdf2Scheme) identify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$pbkdf2$") || strings.HasPrefix(encoded, "$pbkdf2-") || strings.HasPrefix(encoded, "pbkdf2_")
+}
+
+func (p *pbkdf2Scheme) valid(encoded string) bool {
+	_, ok := parsePBKDF2(encoded)
+	return ok
+}
+
+func (p *pbkdf2Scheme) hash(string) (string, error) {
+	return "", errVerifyOnly
+}
+
+func (p *pbkdf2Scheme) verify(encoded, password string) (bool, bool) {
+	parsed, ok := parsePBKDF2(encoded)
+	if !ok {
+		return false, false
+	}
+	computed, err := pbkdf2.Key(parsed.digest, password, parsed.salt, parsed.iterations, len(parsed.key))
+	if err != nil || subtle.ConstantTimeCompare(computed, parsed.key) != 1 {
+		return false, false
+	}
+	return true, true
+}
+
+// parsePBKDF2 解析 passlib 或 Django 格式的 PBKDF2 哈希。
+//
+// 迭代次数或盐的长度超出上限，或摘要长于摘要算法的输出长度（每多一段输出都要重新完成全部迭代）时视为格式错误。
+func parsePBKDF2(encoded string) (*pbkdf2Hash, bool) {
+	var digestName string
+	var salt, key []byte
+	var err error
+	parts := strings.Split(encoded, "$")
+	switch {
+	case strings.HasPrefix(encoded, "$"):
+		// passlib 格式按 "$" 切分后第一段为空
+		if len(parts) != 5 {
+			return nil, false
+		}
+		digestName = "sha1"
+		if parts[1] != "pbkdf2" {
+			name, ok := strings.CutPrefix(parts[1], "pbkdf2-")
+			if !ok {
+				return nil, false
+			}
+			digestName = name
+		}
+		if salt, err = adaptedBase64.DecodeString(parts[3]); err != nil {
+			return nil, false
+		}
+		if key, err = adaptedBase64.DecodeString(parts[4]); err != nil {
+			return nil, false
+		}
+	default:
+		if len(parts) != 4 {
+			return nil, false
+		}
+		name, ok := strings.CutPrefix(parts[0], "pbkdf2_")
+		if !ok {
+			return nil, false
+		}
+		digestName = name
+		salt = []byte(parts[2])
+		if key, err = base64.StdEncoding.DecodeString(parts[3]); err != nil {
+			return nil, false
+		}
+	}
+
+	digest, ok := pbkdf2Digests[digestName]
+	if !ok {
+		return nil, false
+	}
+	iterations, err := strconv.Atoi(parts[len(parts)-3])
+	if err != nil || iterations <= 0 || iterations > pbkdf2MaxIterations {
+		return nil, false
+	}
+	if len(salt) == 0 || len(salt) > pbkdf2MaxSaltLength || len(key) == 0 || len(key) > digest().Size() {
+		return nil, false
+	}
+	return &pbkdf2Hash{digest: digest, iterations: iterations, salt: salt, key: key}, true
+}
+
+// md5Scheme 是从旧系统导入的加盐 MD5 哈希，格式为 "$md5-salted$<prefix|suffix>$<盐>$<摘要>"：
+// prefix 表示对 "盐+密码" 计算 MD5，suffix 表示对 "密码+盐" 计算 MD5；盐为原文且不能包含 "$"，摘要为 32 位十六进制字符。
+type md5Scheme struct{}
+
+func (m *md5Scheme) name() string {
+	return "md5-salted"
+}
+
+func (m *md5Scheme) identify(encoded string) bool {
+	return strings.HasPrefix(encoded, "$md5-salted$")
+}
+
+func (m *md5Scheme) valid(encoded string) bool {
+	_, _, _, ok := parseMD5(encoded)
+	return ok
+}
+
+func (m *md5Scheme) hash(string) (string, error) {
+	return "", errVerifyOnly
+}
+
+func (m *md5Scheme) verify(encoded, password string) (bool, bool) {
+	prefix, salt, digest, ok := parseMD5(encoded)
+	if !ok {
+		return false, false
+	}
+	input := password + salt
+	if prefix {
+		input = salt + password
+	}
+	computed := md5.Sum([]byte(input))
+	if subtle.ConstantTimeCompare(computed[:], digest) != 1 {
+		return false, false
+	}
+	return true, true
+}
+
+// parseMD5 解析加盐 MD5 哈希，返回盐是否位于密码之前、盐与摘要。
+func parseMD5(encoded string) (bool, string, []byte, bool) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 || parts[2] != "prefix" && parts[2] != "suffix" || parts[3] == "" {
+		return false, "", nil, false
+	}
+	digest, err := hex.DecodeString(parts[4])
+	if err != nil || len(digest) != md5.Size {
+		return false, "", nil, false
+	}
+	return parts[2] == "prefix", parts[3], digest, true
+}
@@ -0,0 +1,175 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+)
+
+// minPersonalLength 是检查密码是否包含用户名或邮箱时，用户名与邮箱前缀的最短长度，过短的片段容易误判。
+const minPersonalLength = 3
+
+// Policy 是密码策略，校验用户设置的新密码是否满足长度、字符类别、个人信息与已泄露密码列表的要求，
+// 并负责计算与校验密码哈希。
+//
+// 历史密码的比对需要查询数据库，由调用方根据 History 完成。
+type Policy struct {
+	cfg      *config.PasswordConfig
+	breached map[[sha1.Size]byte]struct{}
+	schemes  []scheme
+	hasher   scheme
+	dummy    string
+}
+
+// New 根据配置创建密码策略，配置了已泄露密码列表时从文件中载入。
+//
+// 哈希算法或参数无效时返回错误。
+func New(cfg *config.PasswordConfig) (*Policy, error) {
+	schemes, hasher, err := newSchemes(&cfg.Hash)
+	if err != nil {
+		return nil, err
+	}
+	dummy, err := hasher.hash("bamboo-sso-dummy-password")
+	if err != nil {
+		return nil, err
+	}
+	policy := &Policy{cfg: cfg, schemes: schemes, hasher: hasher, dummy: dummy}
+	if cfg.BreachedList != "" {
+		breached, err := loadBreached(cfg.BreachedList)
+		if err != nil {
+			return nil, err
+		}
+		policy.breached = breached
+	}
+	return policy, nil
+}
+
+// Breached 返回载入的已泄露密码数量。
+func (p *Policy) Breached() int {
+	return len(p.breached)
+}
+
+// History 返回新密码不能重复使用的历史密码数量，0 表示不检查。
+func (p *Policy) History() int {
+	return max(p.cfg.History, 0)
+}
+
+// Expired 判断在 changedAt 设置的密码是否已超过最长使用期限。
+func (p *Policy) Expired(changedAt time.Time) bool {
+	return p.cfg.MaxAge > 0 && time.Since(changedAt) > p.cfg.MaxAge
+}
+
+// Check 校验新密码是否满足策略，personal 为不能出现在密码中的个人信息，如用户名与邮箱。
+//
+// 返回的错误均为面向用户的提示，说明密码不满足的要求。
+func (p *Policy) Check(password string, personal ...string) error {
+	if utf8.RuneCountInString(password) < p.cfg.MinLength {
+		return fmt.Errorf("密码至少需要 %d 个字符", p.cfg.MinLength)
+	}
+	if len(password) > p.cfg.MaxLength {
+		return fmt.Errorf("密码不能超过 %d 个字节", p.cfg.MaxLength)
+	}
+	if classes := countClasses(password); classes < p.cfg.MinClasses {
+		return fmt.Errorf("密码至少需要包含大写字母、小写字母、数字与符号中的 %d 类", p.cfg.MinClasses)
+	}
+	if !p.cfg.AllowPersonal {
+		lower := strings.ToLower(password)
+		for _, value := range personal {
+			for _, fragment := range personalFragments(value) {
+				if strings.Contains(lower, fragment) {
+					return errors.New("密码不能包含用户名或邮箱")
+				}
+			}
+		}
+	}
+	if _, ok := p.breached[sha1.Sum([]byte(password))]; ok {
+		return errors.New("该密码已出现在公开泄露的密码中，请更换其他密码")
+	}
+	return nil
+}
+
+// countClasses 统计密码包含的字符类别数：大写字母、小写字母、数字与其他符号。
+func countClasses(password string) int {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	count := 0
+	for _, present := range []bool{upper, lower, digit, symbol} {
+		if present {
+			count++
+		}
+	}
+	return count
+}
+
+// personalFragments 返回个人信息中需要检查的片段：完整的值，以及邮箱 @ 之前的部分，均转为小写。
+func personalFragments(value string) []string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	var fragments []string
+	if utf8.RuneCountInString(value) >= minPersonalLength {
+		fragments = append(fragments, value)
+	}
+	if local, _, ok := strings.Cut(value, "@"); ok && utf8.RuneCountInString(local) >= minPersonalLength {
+		fragments = append(fragments, local)
+	}
+	return fragments
+}
+
+// loadBreached 从文件中载入已泄露密码列表，统一保存为 SHA-1 摘要。
+func loadBreached(path string) (map[[sha1.Size]byte]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	breached := make(map[[sha1.Size]byte]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if digest, ok := parseSHA1(line); ok {
+			breached[digest] = struct{}{}
+			continue
+		}
+		breached[sha1.Sum([]byte(line))] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return breached, nil
+}
+
+// parseSHA1 解析 "摘要" 或 "摘要:次数" 格式的一行，摘要为 40 位十六进制字符，不区分大小写。
+func parseSHA1(line string) ([sha1.Size]byte, bool) {
+	var digest [sha1.Size]byte
+	value, _, _ := strings.Cut(line, ":")
+	if len(value) != hex.EncodedLen(sha1.Size) {
+		return digest, false
+	}
+	if _, err := hex.Decode(digest[:], []byte(value)); err != nil {
+		return digest, false
+	}
+	return digest, true
+}
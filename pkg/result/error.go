@@ -0,0 +1,47 @@
+package result
+
+import "net/http"
+
+// Error 表示可直接返回给调用方的业务错误。
+//
+// 字段说明：
+//   - Status: HTTP 状态码。
+//   - Code: 业务错误码。
+//   - Message: 错误描述信息。
+//   - Data: 附加的错误数据，可选字段。
+type Error struct {
+	Status  int
+	Code    int
+	Message string
+	Data    any
+}
+
+// Error 实现 error 接口。
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// WithMessage 返回一个替换了错误描述的新错误，原错误保持不变。
+func (e *Error) WithMessage(message string) *Error {
+	newErr := *e
+	newErr.Message = message
+	return &newErr
+}
+
+// WithData 返回一个携带附加数据的新错误，原错误保持不变。
+func (e *Error) WithData(data any) *Error {
+	newErr := *e
+	newErr.Data = data
+	return &newErr
+}
+
+var (
+	ErrParameter    = &Error{Status: http.StatusBadRequest, Code: 40000, Message: "参数错误"}
+	ErrUnauthorized = &Error{Status: http.StatusUnauthorized, Code: 40100, Message: "未登录或登录已失效"}
+	ErrForbidden    = &Error{Status: http.StatusForbidden, Code: 40300, Message: "没有访问权限"}
+	ErrNotFound     = &Error{Status: http.StatusNotFound, Code: 40400, Message: "资源不存在"}
+	ErrConflict     = &Error{Status: http.StatusConflict, Code: 40900, Message: "资源冲突"}
+	ErrTooMany      = &Error{Status: http.StatusTooManyRequests, Code: 42900, Message: "操作过于频繁，请稍后再试"}
+	ErrThirdParty   = &Error{Status: http.StatusBadGateway, Code: 50200, Message: "第三方服务调用失败"}
+	ErrServer       = &Error{Status: http.StatusInternalServerError, Code: 50000, Message: "服务器内部错误"}
+)
@@ -0,0 +1,46 @@
+package result
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Result 表示接口统一的响应结构。
+//
+// 字段说明：
+//   - Code: 业务状态码，成功时为 200。
+//   - Message: 响应描述信息。
+//   - Data: 响应数据，可选字段。
+type Result struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// Success 以统一结构输出成功响应。
+func Success(c *gin.Context, message string, data any) {
+	c.JSON(http.StatusOK, Result{
+		Code:    http.StatusOK,
+		Message: message,
+		Data:    data,
+	})
+}
+
+// Fail 以统一结构输出失败响应并终止后续处理。
+//
+// 当 err 为 *Error 时使用其携带的状态码与信息；否则视为服务器内部错误，
+// 原始错误信息只写入 gin 的错误列表，不直接暴露给调用方。
+func Fail(c *gin.Context, err error) {
+	var getErr *Error
+	if !errors.As(err, &getErr) {
+		_ = c.Error(err)
+		getErr = ErrServer
+	}
+	c.AbortWithStatusJSON(getErr.Status, Result{
+		Code:    getErr.Code,
+		Message: getErr.Message,
+		Data:    getErr.Data,
+	})
+}
@@ -0,0 +1,42 @@
+package secret
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+var (
+	// blindIndexKey 保存计算盲索引所用的密钥，由 UseBlindIndexKey 在启动时设置。
+	blindIndexKey atomic.Pointer[[]byte]
+	// errNoBlindIndexKey 表示尚未通过 UseBlindIndexKey 设置盲索引密钥。
+	errNoBlindIndexKey = errors.New("未设置盲索引密钥")
+)
+
+// UseBlindIndexKey 设置计算盲索引所用的 Base64 编码的 32 字节密钥，须在计算任何盲索引之前调用。
+func UseBlindIndexKey(encoded string) error {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != keySize {
+		return fmt.Errorf("盲索引密钥必须是 Base64 编码的 %d 字节密钥", keySize)
+	}
+	blindIndexKey.Store(&key)
+	return nil
+}
+
+// BlindIndex 计算值的盲索引，即以独立密钥计算的 HMAC-SHA256 摘要（十六进制）。
+//
+// 加密字段的密文每次都不同，不能用于查询条件与唯一索引；需要等值查询的加密字段可以另设一列保存其盲索引。
+// 盲索引密钥不支持像主密钥一样轮换，修改后需要重新计算全部盲索引。
+func BlindIndex(value string) (string, error) {
+	key := blindIndexKey.Load()
+	if key == nil {
+		return "", errNoBlindIndexKey
+	}
+	mac := hmac.New(sha256.New, *key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,209 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// prefix 是信封加密密文的前缀，用于区分密文与历史遗留的明文。
+	prefix = "enc:v2:"
+	// legacyPrefix 是不带主密钥 ID 的旧版密文前缀，这类密文使用旧版主密钥解密。
+	legacyPrefix = "enc:v1:"
+	// sealedPrefix 是各版本密文共有的前缀，无法识别版本的密文视为格式错误而不是明文。
+	sealedPrefix = "enc:"
+	// legacyKeyID 是只配置了旧版主密钥时，旧版主密钥在密钥环中的 ID。
+	legacyKeyID = "legacy"
+	// keySize 是主密钥与数据密钥的长度，对应 AES-256。
+	keySize = 32
+)
+
+// keyIDPattern 限定主密钥 ID 的字符集，密钥 ID 会以明文形式写入密文头部。
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// ErrMalformed 表示密文格式错误或无法用密钥环中的主密钥解密。
+var ErrMalformed = errors.New("密文格式错误或主密钥不匹配")
+
+// Keyring 使用信封加密保护敏感字段，并支持主密钥轮换。
+//
+// 每次加密都会生成一把随机的数据密钥，用它以 AES-256-GCM 加密明文，再用当前启用的主密钥加密数据密钥；
+// 主密钥只用于加解密数据密钥，不直接接触业务数据。密文格式为：
+//
+//	enc:v2:<主密钥 ID>:Base64URL(加密后的数据密钥 | 数据 nonce | 数据密文)
+//
+// 解密时按密文中的主密钥 ID 选择密钥，因此轮换期间新旧主密钥需要同时保留在密钥环中，
+// 待存量数据全部改用新密钥重新加密后才能移除旧密钥。
+//
+// 引入密钥环之前写入的密文格式为 "enc:v1:<负载>"，不带主密钥 ID，使用旧版主密钥解密；
+// 执行重新加密命令后，这类密文会全部改用当前启用的主密钥加密。
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+	legacy cipher.AEAD
+}
+
+// NewKeyring 根据主密钥 ID 与 Base64 编码的 32 字节主密钥创建密钥环，active 为加密新数据所用的主密钥 ID。
+//
+// legacy 为引入密钥环之前使用的旧版主密钥，用于解密 "enc:v1:" 格式的密文，没有这类密文时可以为空；
+// 未配置任何主密钥 ID 时，旧版主密钥同时作为 ID 为 "legacy" 的启用主密钥，以便升级后无需修改配置即可继续使用。
+func NewKeyring(active string, keys map[string]string, legacy string) (*Keyring, error) {
+	keyring := &Keyring{active: active, keys: make(map[string]cipher.AEAD, len(keys)+1)}
+	if legacy != "" {
+		aead, err := newMasterKey(legacy)
+		if err != nil {
+			return nil, fmt.Errorf("旧版主密钥%w", err)
+		}
+		keyring.legacy = aead
+		if active == "" && len(keys) == 0 {
+			keyring.active = legacyKeyID
+			keyring.keys[legacyKeyID] = aead
+			return keyring, nil
+		}
+	}
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("启用的主密钥 %q 不在密钥列表中", active)
+	}
+
+	for id, encoded := range keys {
+		if !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("主密钥 ID %q 只能包含字母、数字、下划线与短横线", id)
+		}
+		aead, err := newMasterKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("主密钥 %q %w", id, err)
+		}
+		keyring.keys[id] = aead
+	}
+	return keyring, nil
+}
+
+// Seal 使用当前启用的主密钥加密明文并返回带前缀的密文，空字符串原样返回。
+func (k *Keyring) Seal(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := seal(k.keys[k.active], dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(data, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return prefix + k.active + ":" + base64.RawURLEncoding.EncodeToString(append(wrappedKey, sealed...)), nil
+}
+
+// Open 解密 Seal 生成的密文，以及引入密钥环之前写入的 "enc:v1:" 格式的密文。
+//
+// 不带密文前缀的值视为启用加密前写入的明文，原样返回，以便存量数据平滑过渡。
+func (k *Keyring) Open(stored string) (string, error) {
+	var master cipher.AEAD
+	var encoded string
+	if id, rest, ok := parse(stored); ok {
+		if master, ok = k.keys[id]; !ok {
+			return "", fmt.Errorf("密钥环中不存在主密钥 %q", id)
+		}
+		encoded = rest
+	} else if rest, ok := strings.CutPrefix(stored, legacyPrefix); ok {
+		if k.legacy == nil {
+			return "", errors.New("密文使用旧版主密钥加密，但未配置旧版主密钥")
+		}
+		master, encoded = k.legacy, rest
+	} else if strings.HasPrefix(stored, sealedPrefix) {
+		return "", ErrMalformed
+	} else {
+		return stored, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	wrappedSize := master.NonceSize() + keySize + master.Overhead()
+	if len(raw) < wrappedSize {
+		return "", ErrMalformed
+	}
+	dataKey, err := open(master, raw[:wrappedSize])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(data, raw[wrappedSize:])
+	if err != nil {
+		return "", ErrMalformed
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation 判断存储的值是否需要重新加密：非空的明文、旧版密文，或使用了非当前启用主密钥加密的密文。
+func (k *Keyring) NeedsRotation(stored string) bool {
+	if stored == "" {
+		return false
+	}
+	id, _, ok := parse(stored)
+	return !ok || id != k.active
+}
+
+// parse 拆分当前格式密文中的主密钥 ID 与负载，值不是当前格式的密文时 ok 为 false。
+func parse(value string) (id, encoded string, ok bool) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return "", "", false
+	}
+	return strings.Cut(rest, ":")
+}
+
+// newMasterKey 解码 Base64 编码的主密钥并创建 AES-GCM 实例。
+func newMasterKey(encoded string) (cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("必须是 Base64 编码的 %d 字节密钥", keySize)
+	}
+	return newGCM(key)
+}
+
+// newGCM 使用给定密钥创建 AES-GCM 实例。
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal 使用随机 nonce 加密数据，返回 nonce 与密文的拼接结果。
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open 拆分 nonce 与密文并解密。
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
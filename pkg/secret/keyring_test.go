@@ -0,0 +1,160 @@
+package secret
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const (
+	testKeyDev   = "ZGV2LW9ubHktbWFzdGVyLWtleS1yZXBsYWNlLW1lISE="
+	testKeyOther = "YW5vdGhlci1tYXN0ZXIta2V5LWZvci10ZXN0aW5nISE="
+)
+
+// sealLegacy 以引入密钥环之前的格式 "enc:v1:<负载>" 加密明文。
+func sealLegacy(t *testing.T, masterKey, plaintext string) string {
+	t.Helper()
+	master, err := newMasterKey(masterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		t.Fatal(err)
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrappedKey, err := seal(master, dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := seal(data, []byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return legacyPrefix + base64.RawURLEncoding.EncodeToString(append(wrappedKey, sealed...))
+}
+
+func TestKeyringSealOpen(t *testing.T) {
+	keyring, err := NewKeyring("dev", map[string]string{"dev": testKeyDev}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := keyring.Seal("client-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, prefix+"dev:") {
+		t.Errorf("Seal = %q, want prefix %q", sealed, prefix+"dev:")
+	}
+	if plaintext, err := keyring.Open(sealed); err != nil || plaintext != "client-secret" {
+		t.Errorf("Open = (%q, %v), want (%q, nil)", plaintext, err, "client-secret")
+	}
+	if keyring.NeedsRotation(sealed) {
+		t.Error("NeedsRotation(active) = true, want false")
+	}
+	if empty, err := keyring.Seal(""); err != nil || empty != "" {
+		t.Errorf("Seal(\"\") = (%q, %v), want (\"\", nil)", empty, err)
+	}
+}
+
+func TestKeyringOpen(t *testing.T) {
+	legacy := sealLegacy(t, testKeyDev, "client-secret")
+	old, err := NewKeyring("old", map[string]string{"old": testKeyOther}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := old.Seal("client-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		active   string
+		keys     map[string]string
+		legacy   string
+		stored   string
+		want     string
+		wantErr  bool
+		rotation bool
+	}{
+		{name: "plaintext passes through", active: "dev", keys: map[string]string{"dev": testKeyDev}, stored: "plain", want: "plain", rotation: true},
+		{name: "legacy ciphertext with master key", active: "dev", keys: map[string]string{"dev": testKeyDev}, legacy: testKeyDev, stored: legacy, want: "client-secret", rotation: true},
+		{name: "legacy ciphertext with only master key", legacy: testKeyDev, stored: legacy, want: "client-secret", rotation: true},
+		{name: "legacy ciphertext without master key", active: "dev", keys: map[string]string{"dev": testKeyDev}, stored: legacy, wantErr: true, rotation: true},
+		{name: "legacy ciphertext with wrong master key", active: "dev", keys: map[string]string{"dev": testKeyDev}, legacy: testKeyOther, stored: legacy, wantErr: true, rotation: true},
+		{name: "ciphertext sealed by previous key", active: "dev", keys: map[string]string{"dev": testKeyDev, "old": testKeyOther}, stored: rotated, want: "client-secret", rotation: true},
+		{name: "ciphertext sealed by removed key", active: "dev", keys: map[string]string{"dev": testKeyDev}, stored: rotated, wantErr: true, rotation: true},
+		{name: "unknown version", active: "dev", keys: map[string]string{"dev": testKeyDev}, stored: "enc:v9:payload", wantErr: true, rotation: true},
+		{name: "corrupted payload", active: "dev", keys: map[string]string{"dev": testKeyDev}, stored: prefix + "dev:!!!", wantErr: true, rotation: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring, err := NewKeyring(tt.active, tt.keys, tt.legacy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := keyring.Open(tt.stored)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Open error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Open = %q, want %q", got, tt.want)
+			}
+			if rotation := keyring.NeedsRotation(tt.stored); rotation != tt.rotation {
+				t.Errorf("NeedsRotation = %v, want %v", rotation, tt.rotation)
+			}
+		})
+	}
+}
+
+func TestKeyringOnlyLegacyKeySealsWithLegacyID(t *testing.T) {
+	keyring, err := NewKeyring("", nil, testKeyDev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := keyring.Seal("client-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, prefix+legacyKeyID+":") {
+		t.Errorf("Seal = %q, want prefix %q", sealed, prefix+legacyKeyID+":")
+	}
+}
+
+func TestNewKeyringRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		active string
+		keys   map[string]string
+		legacy string
+	}{
+		{name: "no keys", active: "dev"},
+		{name: "active key missing", active: "new", keys: map[string]string{"dev": testKeyDev}},
+		{name: "invalid key id", active: "dev:1", keys: map[string]string{"dev:1": testKeyDev}},
+		{name: "short key", active: "dev", keys: map[string]string{"dev": base64.StdEncoding.EncodeToString([]byte("short"))}},
+		{name: "invalid legacy key", active: "dev", keys: map[string]string{"dev": testKeyDev}, legacy: "not-base64"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewKeyring(tt.active, tt.keys, tt.legacy); err == nil {
+				t.Error("NewKeyring = nil error, want error")
+			}
+		})
+	}
+}
+
+func TestOpenMalformedReturnsErrMalformed(t *testing.T) {
+	keyring, err := NewKeyring("dev", map[string]string{"dev": testKeyDev}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keyring.Open("enc:v9:payload"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Open error = %v, want ErrMalformed", err)
+	}
+}
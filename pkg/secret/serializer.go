@@ -0,0 +1,118 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName 是加密字段在 gorm 标签中使用的序列化器名称，如 `gorm:"type:text;serializer:encrypted"`。
+const SerializerName = "encrypted"
+
+var (
+	// current 保存加密字段序列化器使用的密钥环，由 Use 在启动时设置。
+	current atomic.Pointer[Keyring]
+	// errNoKeyring 表示尚未通过 Use 设置密钥环。
+	errNoKeyring = errors.New("未设置密钥环")
+)
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// Use 设置加密字段序列化器使用的密钥环，须在访问任何加密字段之前调用。
+func Use(keyring *Keyring) {
+	current.Store(keyring)
+}
+
+// Current 返回当前使用的密钥环，未设置时返回 nil。
+func Current() *Keyring {
+	return current.Load()
+}
+
+// Serializer 是加密字段的 gorm 序列化器，支持 string 与 *string 类型的字段。
+//
+// 写入时使用当前启用的主密钥加密，读取时按密文中的主密钥 ID 解密；
+// 由于每次加密的结果都不同，加密字段不能用于查询条件与唯一索引。
+type Serializer struct{}
+
+// Scan 解密数据库中的值并写入实体字段。
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+	fieldValue := reflect.New(field.FieldType).Elem()
+	if dbValue != nil {
+		var stored string
+		switch value := dbValue.(type) {
+		case string:
+			stored = value
+		case []byte:
+			stored = string(value)
+		default:
+			return fmt.Errorf("加密字段 %s 的数据库值类型不受支持: %T", field.Name, dbValue)
+		}
+
+		keyring, err := keyringFor(field)
+		if err != nil {
+			return err
+		}
+		plaintext, err := keyring.Open(stored)
+		if err != nil {
+			return fmt.Errorf("解密字段 %s 失败: %w", field.Name, err)
+		}
+		if err := setString(fieldValue, plaintext); err != nil {
+			return fmt.Errorf("加密字段 %s: %w", field.Name, err)
+		}
+	}
+	field.ReflectValueOf(ctx, dst).Set(fieldValue)
+	return nil
+}
+
+// Value 加密实体字段的值以写入数据库，nil 指针写入 NULL。
+func (Serializer) Value(_ context.Context, field *schema.Field, _ reflect.Value, fieldValue any) (any, error) {
+	var plaintext string
+	switch value := fieldValue.(type) {
+	case string:
+		plaintext = value
+	case *string:
+		if value == nil {
+			return nil, nil
+		}
+		plaintext = *value
+	default:
+		return nil, fmt.Errorf("加密字段 %s 的类型不受支持: %T", field.Name, fieldValue)
+	}
+
+	keyring, err := keyringFor(field)
+	if err != nil {
+		return nil, err
+	}
+	return keyring.Seal(plaintext)
+}
+
+// keyringFor 获取当前密钥环，未设置时返回错误而不是以明文写入。
+func keyringFor(field *schema.Field) (*Keyring, error) {
+	keyring := current.Load()
+	if keyring == nil {
+		return nil, fmt.Errorf("加密字段 %s: %w", field.Name, errNoKeyring)
+	}
+	return keyring, nil
+}
+
+// setString 将明文写入 string 或 *string 类型的反射值。
+func setString(target reflect.Value, plaintext string) error {
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(plaintext)
+	case reflect.Pointer:
+		if target.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("不支持的字段类型 %s", target.Type())
+		}
+		target.Set(reflect.ValueOf(&plaintext))
+	default:
+		return fmt.Errorf("不支持的字段类型 %s", target.Type())
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package sms
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Log 只将短信内容写入日志而不真正发送，用于本地开发与测试。
+type Log struct {
+	log *zap.Logger
+}
+
+// NewLog 创建写入日志的短信发送器。
+func NewLog(log *zap.Logger) *Log {
+	return &Log{log: log.Named("SMS")}
+}
+
+// Send 将收件手机号、模板与参数写入日志。
+func (l *Log) Send(_ context.Context, phone, template string, params map[string]string) error {
+	l.log.Info("短信已写入日志", zap.String("phone", phone), zap.String("template", template), zap.Any("params", params))
+	return nil
+}
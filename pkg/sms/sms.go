@@ -0,0 +1,32 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+	"go.uber.org/zap"
+)
+
+// 短信模板名称，由具体的发送器映射为短信服务商的模板。
+const (
+	TemplateLogin = "login" // 登录验证码，参数 code
+	TemplateBind  = "bind"  // 绑定手机号验证码，参数 code
+)
+
+// Sender 是短信发送器的统一接口，具体的发送方式由配置中的 driver 决定。
+//
+// 国内短信服务商通常要求使用预先审核的模板，因此接口以模板名称与参数描述短信内容，而不是直接传入正文。
+type Sender interface {
+	Send(ctx context.Context, phone, template string, params map[string]string) error
+}
+
+// New 根据配置创建短信发送器。
+func New(cfg *config.SMSConfig, log *zap.Logger) (Sender, error) {
+	switch cfg.Driver {
+	case "log":
+		return NewLog(log), nil
+	default:
+		return nil, fmt.Errorf("不支持的短信发送方式 %q", cfg.Driver)
+	}
+}
@@ -2,7 +2,14 @@ package startup
 
 import (
 	xInit "github.com/bamboo-services/bamboo-base-go/init"
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+	"github.com/bamboo-services/bamboo-sso/pkg/logout"
+	"github.com/bamboo-services/bamboo-sso/pkg/mailer"
+	"github.com/bamboo-services/bamboo-sso/pkg/password"
+	"github.com/bamboo-services/bamboo-sso/pkg/sms"
+	"github.com/bamboo-services/bamboo-sso/pkg/storage"
 	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 	"sync"
@@ -12,6 +19,14 @@ type reg struct {
 	serv *xInit.Reg    // 服务实例，提供必要的依赖和配置
 	db   *gorm.DB      // 数据库连接实例，用于与数据库进行交互
 	rdb  *redis.Client // Redis 客户端实例，用于与 Redis 数据库进行交互
+	sso  *config.SSO   // SSO 业务配置，提供令牌与第三方登录相关的参数
+
+	webAuthn *webauthn.WebAuthn // 通行密钥依赖方实例，根据业务配置创建
+	password *password.Policy   // 密码策略，根据业务配置创建
+	mailer   mailer.Mailer      // 邮件发送器，根据业务配置创建
+	sms      sms.Sender         // 短信发送器，根据业务配置创建
+	storage  storage.Storage    // 文件存储，根据业务配置创建
+	logout   *logout.Sender     // 后端通道登出通知发送器，在 Redis 就绪后创建并在后台运行
 }
 
 // New 创建一个新的 reg 实例并初始化其必要的依赖项。输入参数 serv 必须是有效的 *xInit.Reg 实例。
@@ -25,6 +40,13 @@ func New(serv *xInit.Reg) *reg {
 func Register(serv *xInit.Reg) *gin.Engine {
 	reg := New(serv)
 
+	// 读取业务配置并设置受信任的反向代理，创建邮件与短信发送器、文件存储
+	reg.ConfigStartup()
+	reg.ProxyStartup()
+	reg.MailerStartup()
+	reg.SMSStartup()
+	reg.StorageStartup()
+
 	wg := sync.WaitGroup{}
 	wg.Add(2)
 
@@ -34,6 +56,10 @@ func Register(serv *xInit.Reg) *gin.Engine {
 
 	wg.Wait()
 
+	// 启动依赖数据库与 Redis 的后台任务
+	reg.LogoutStartup()
+	reg.RoleExpiryStartup()
+
 	// 注册上下文
 	reg.ContextRegister()
 
@@ -0,0 +1,65 @@
+package startup
+
+import (
+	xConsts "github.com/bamboo-services/bamboo-base-go/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+	"github.com/bamboo-services/bamboo-sso/pkg/password"
+	"github.com/bamboo-services/bamboo-sso/pkg/secret"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"go.uber.org/zap"
+)
+
+// configPath 是业务配置文件的路径，与基础配置共用同一个文件。
+const configPath = "configs/config.yaml"
+
+// ConfigStartup 读取 SSO 业务配置，并据此设置加密字段使用的密钥环与盲索引密钥、创建通行密钥依赖方实例与密码策略。
+// 如果配置文件读取或解析失败，或密钥、通行密钥配置无效，或密码哈希配置无效、已泄露密码列表无法读取，函数将会因 panic 终止程序。
+func (r *reg) ConfigStartup() {
+	r.serv.Logger.Named(xConsts.LogINIT).Info("读取 SSO 业务配置")
+
+	sso, err := config.LoadSSO(configPath)
+	if err != nil {
+		panic("[CONFIG] 读取 SSO 业务配置失败: " + err.Error())
+	}
+	r.sso = sso
+
+	keyring, err := secret.NewKeyring(sso.Secret.ActiveKey, sso.Secret.Keys, sso.Secret.MasterKey)
+	if err != nil {
+		panic("[CONFIG] 敏感字段加密密钥环无效: " + err.Error())
+	}
+	secret.Use(keyring)
+	if err := secret.UseBlindIndexKey(sso.Secret.IndexKey); err != nil {
+		panic("[CONFIG] 盲索引密钥无效: " + err.Error())
+	}
+
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          sso.WebAuthn.RPID,
+		RPDisplayName: sso.WebAuthn.RPDisplayName,
+		RPOrigins:     sso.WebAuthn.RPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: sso.WebAuthn.CeremonyTTL, TimeoutUVD: sso.WebAuthn.CeremonyTTL},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: sso.WebAuthn.CeremonyTTL, TimeoutUVD: sso.WebAuthn.CeremonyTTL},
+		},
+	})
+	if err != nil {
+		panic("[CONFIG] 通行密钥配置无效: " + err.Error())
+	}
+	r.webAuthn = webAuthn
+
+	policy, err := password.New(&sso.Password)
+	if err != nil {
+		panic("[CONFIG] 创建密码策略失败: " + err.Error())
+	}
+	if sso.Password.BreachedList != "" {
+		r.serv.Logger.Named(xConsts.LogINIT).Info("已载入已泄露密码列表", zap.Int("count", policy.Breached()))
+	}
+	r.password = policy
+}
+
+// ProxyStartup 按业务配置设置受信任的反向代理，只有来自这些代理的请求才从 X-Forwarded-For 等请求头中读取客户端 IP。
+// 未配置时不信任任何代理，客户端 IP 即连接的对端地址；配置无效时，函数将会因 panic 终止程序。
+func (r *reg) ProxyStartup() {
+	if err := r.serv.Serve.SetTrustedProxies(r.sso.RateLimit.TrustedProxies); err != nil {
+		panic("[CONFIG] 受信任的反向代理配置无效: " + err.Error())
+	}
+}
@@ -2,6 +2,7 @@ package startup
 
 import (
 	xConsts "github.com/bamboo-services/bamboo-base-go/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
 	"github.com/gin-gonic/gin"
 )
 
@@ -23,9 +24,17 @@ func (r *reg) ContextRegister() {
 	r.serv.Serve.Use(handler.handlerContext)
 }
 
-// handlerContext 将数据库和 Redis 客户端实例绑定到请求上下文中以便后续处理使用。
+// handlerContext 将数据库、Redis 客户端、日志、业务配置、通行密钥依赖方实例、密码策略、邮件、短信、登出通知发送器与文件存储绑定到请求上下文中以便后续处理使用。
 func (h *handler) handlerContext(c *gin.Context) {
 	c.Set(xConsts.ContextDatabase, h.reg.db)
 	c.Set(xConsts.ContextRedisClient, h.reg.rdb)
+	c.Set(constants.ContextLogger, h.reg.serv.Logger)
+	c.Set(constants.ContextSSOConfig, h.reg.sso)
+	c.Set(constants.ContextWebAuthn, h.reg.webAuthn)
+	c.Set(constants.ContextPasswordPolicy, h.reg.password)
+	c.Set(constants.ContextMailer, h.reg.mailer)
+	c.Set(constants.ContextSMSSender, h.reg.sms)
+	c.Set(constants.ContextLogout, h.reg.logout)
+	c.Set(constants.ContextStorage, h.reg.storage)
 	c.Next()
 }
@@ -7,6 +7,8 @@ import (
 	xUtil "github.com/bamboo-services/bamboo-base-go/utility"
 	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
 	"github.com/bamboo-services/bamboo-sso/pkg/config"
+	"github.com/bamboo-services/bamboo-sso/pkg/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/password"
 	jsoniter "github.com/json-iterator/go"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -15,19 +17,34 @@ import (
 )
 
 var tableEntity = []interface{}{
+	&entity.Tenant{},
 	&entity.Role{},
+	&entity.Permission{},
+	&entity.RolePermission{},
 	&entity.User{},
 	&entity.UserProfile{},
 	&entity.UserRole{},
+	&entity.Group{},
+	&entity.GroupMember{},
+	&entity.GroupRole{},
 	&entity.UserToken{},
 	&entity.ThirdPartyProvider{},
 	&entity.UserThirdPartyWechat{},
 	&entity.UserThirdPartyGithub{},
 	&entity.UserThirdPartyQQ{},
+	&entity.UserExternalIdentity{},
+	&entity.UserTOTP{},
+	&entity.UserRecoveryCode{},
+	&entity.UserPasswordHistory{},
+	&entity.UserImportJob{},
+	&entity.UserImportError{},
+	&entity.UserWebAuthnCredential{},
 	&entity.Application{},
+	&entity.ApplicationAccess{},
 	&entity.AuthorizationCode{},
 	&entity.LoginLog{},
 	&entity.AuthorizationLog{},
+	&entity.AuditLog{},
 	&entity.System{},
 }
 
@@ -36,44 +53,51 @@ var tableEntity = []interface{}{
 // 字段说明：
 // - reg: 注册器实例，提供服务、数据库和缓存相关的功能。
 // - init: 基础数据初始化实例，用于配置初始化数据库所需的数据。
+// - tenant: 默认租户，由 PrepareTenant 创建，其余基础数据归属该租户。
+// - policy: 密码策略，用于计算与校验超级管理员的初始密码。
 type prepare struct {
-	db   *gorm.DB               // db 是数据库连接实例，用于执行数据库操作
-	init *config.InitializeData // init 是初始化数据实例，用于准备基础数据
+	db     *gorm.DB               // db 是数据库连接实例，用于执行数据库操作
+	init   *config.InitializeData // init 是初始化数据实例，用于准备基础数据
+	tenant *entity.Tenant         // tenant 是默认租户，其余基础数据归属该租户
+	policy *password.Policy       // policy 是密码策略，用于计算与校验超级管理员的初始密码
 }
 
-// DatabaseStartup 初始化数据库连接并配置为服务的主数据库实例。
+// DatabaseStartup 初始化数据库连接并配置为服务的主数据库实例，随后迁移数据库表并初始化基础数据。
 // 如果数据库连接失败，函数将会因 panic 终止程序。
 func (r *reg) DatabaseStartup() {
-	r.serv.Logger.Named(xConsts.LogINIT).Info("初始化 PgSQL 数据库连接")
 	getConfig := r.serv.Config
-
-	// 数据库连接
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s  TimeZone=Asia/Shanghai",
-		getConfig.Database.Host,
-		getConfig.Database.Port,
-		getConfig.Database.User,
-		getConfig.Database.Pass,
-		getConfig.Database.Name,
-	)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		NamingStrategy: schema.NamingStrategy{
-			TablePrefix:   xUtil.DefaultIfBlank(getConfig.Database.Prefix, "xlf_"), // 表前缀
-			SingularTable: true,                                                    // 使用单数表名
-		},
-	})
-	if err != nil {
-		panic("[DB] 数据库连接失败: " + err.Error())
-	}
+	db := r.connectDatabase()
 
 	// 自动迁移数据库表
-	err = db.AutoMigrate(tableEntity...)
+	err := db.AutoMigrate(tableEntity...)
 	if err != nil {
 		panic("[DB] 数据库自动迁移失败: " + err.Error())
 	} else {
 		r.serv.Logger.Named(xConsts.LogINIT).Debug("数据库自动迁移成功")
 	}
 
+	// 角色名称由全局唯一改为在所属租户或应用内唯一，用户名、邮箱、手机号、提供商代码与分组名称改为在所属租户内唯一，
+	// 移除旧版本按单个字段创建的唯一索引
+	legacyIndexes := []struct {
+		model interface{}
+		name  string
+	}{
+		{&entity.Role{}, "idx_" + db.NamingStrategy.TableName("Role") + "_name"},
+		{&entity.Role{}, "idx_role_global_name"},
+		{&entity.User{}, "idx_" + db.NamingStrategy.TableName("User") + "_username"},
+		{&entity.User{}, "idx_" + db.NamingStrategy.TableName("User") + "_email"},
+		{&entity.User{}, "idx_" + db.NamingStrategy.TableName("User") + "_phone_hash"},
+		{&entity.ThirdPartyProvider{}, "idx_" + db.NamingStrategy.TableName("ThirdPartyProvider") + "_code"},
+		{&entity.Group{}, "idx_" + db.NamingStrategy.TableName("Group") + "_name"},
+	}
+	for _, legacy := range legacyIndexes {
+		if db.Migrator().HasIndex(legacy.model, legacy.name) {
+			if err := db.Migrator().DropIndex(legacy.model, legacy.name); err != nil {
+				panic(fmt.Sprintf("[DB] 移除旧索引 %s 失败: %s", legacy.name, err.Error()))
+			}
+		}
+	}
+
 	// 检查是否启用 Debug 模式
 	if getConfig.Xlf.Debug {
 		r.serv.Logger.Named(xConsts.LogINIT).Debug("数据库连接开启 Debug 模式")
@@ -81,16 +105,18 @@ func (r *reg) DatabaseStartup() {
 	}
 
 	// 初始化基础数据
-	getPrepare := &prepare{db: db, init: config.New(db, r.serv.Logger)}
+	getPrepare := &prepare{db: db, init: config.New(db, r.serv.Logger), policy: r.password}
+	getPrepare.PrepareTenant()
 
 	// 使用 WaitGroup 并发执数据的初始化
 	wg := sync.WaitGroup{}
-	wg.Add(4)
+	wg.Add(5)
 	done := make(chan int, 3)
 
-	go func() { defer wg.Done(); getPrepare.PrepareRole(); done <- 0 }()
+	go func() { defer wg.Done(); getPrepare.PrepareRole(); getPrepare.PreparePermission(); done <- 0 }()
 	go func() { defer wg.Done(); getPrepare.PrepareApplication(); done <- 0 }()
 	go func() { defer wg.Done(); getPrepare.PrepareSystem(); done <- 0 }()
+	go func() { defer wg.Done(); getPrepare.PrepareProvider() }()
 	go func() {
 		defer wg.Done()
 		for i := 0; i < 3; i++ {
@@ -105,6 +131,66 @@ func (r *reg) DatabaseStartup() {
 	r.db = db
 }
 
+// connectDatabase 只建立 PgSQL 数据库连接，不迁移数据库表也不初始化基础数据，供维护命令使用。
+// 如果数据库连接失败，函数将会因 panic 终止程序。
+func (r *reg) connectDatabase() *gorm.DB {
+	r.serv.Logger.Named(xConsts.LogINIT).Info("初始化 PgSQL 数据库连接")
+	getConfig := r.serv.Config
+
+	// 数据库连接
+	dsn := fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s  TimeZone=Asia/Shanghai",
+		getConfig.Database.Host,
+		getConfig.Database.Port,
+		getConfig.Database.User,
+		getConfig.Database.Pass,
+		getConfig.Database.Name,
+	)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{
+			TablePrefix:   xUtil.DefaultIfBlank(getConfig.Database.Prefix, "xlf_"), // 表前缀
+			SingularTable: true,                                                    // 使用单数表名
+		},
+	})
+	if err != nil {
+		panic("[DB] 数据库连接失败: " + err.Error())
+	}
+	return db
+}
+
+// PrepareTenant 初始化默认租户，并将升级前没有所属租户的数据归入默认租户。
+//
+// 默认租户的代码为 constants.TenantDefault，不存在时创建；用户、接入应用、第三方提供商、分组与自定义角色中
+// 没有所属租户的记录会被归入默认租户，内置角色由全部租户共用，保持没有所属租户。
+// 其余基础数据归属默认租户，必须最先调用；若初始化失败会触发 panic。
+func (p *prepare) PrepareTenant() {
+	var tenant entity.Tenant
+	err := p.db.Where(&entity.Tenant{Code: constants.TenantDefault}).First(&tenant).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tenant = entity.Tenant{Code: constants.TenantDefault, Name: "默认租户"}
+		err = p.db.Create(&tenant).Error
+	}
+	if err != nil {
+		panic(fmt.Sprintf("[DB] 初始化默认租户失败: %s", err.Error()))
+	}
+	p.tenant = &tenant
+
+	err = p.db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&entity.User{}, &entity.Application{}, &entity.ThirdPartyProvider{}, &entity.Group{}} {
+			if err := tx.Model(model).Where("tenant_uuid IS NULL").Update("tenant_uuid", tenant.UUID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&entity.Role{}).
+			Where("tenant_uuid IS NULL").
+			Where("application_uuid IS NOT NULL OR name NOT IN ?", []string{constants.RoleSuperAdmin, constants.RoleAdmin, constants.RoleUser}).
+			Update("tenant_uuid", tenant.UUID).Error
+	})
+	if err != nil {
+		panic(fmt.Sprintf("[DB] 将已有数据归入默认租户失败: %s", err.Error()))
+	}
+}
+
 // PrepareRole 初始化系统的默认角色数据。
 //
 // 调用此方法时，将在角色表中检查是否存在预定义的角色。若角色不存在，则创建以下默认角色：
@@ -124,6 +210,41 @@ func (p *prepare) PrepareRole() {
 	)
 }
 
+// PreparePermission 初始化系统的默认权限数据，并为内置角色授予新增的权限。
+//
+// 调用此方法时，将在权限表中检查是否存在预定义的权限，为用户、接入应用、角色、第三方提供商与系统管理
+// 分别创建查看（read）与管理（write）两项权限。新创建的权限会授予以下角色：
+// - "SUPER_ADMIN": 授予全部权限；超级管理员在权限校验时本就不受限制，授权记录用于在管理后台展示。
+// - "ADMIN": 授予除角色管理与系统配置修改以外的全部权限。
+// 已存在的权限不会重新授予，管理员撤销的授权在重启后不会恢复。必须在 PrepareRole 之后调用。
+func (p *prepare) PreparePermission() {
+	permission := func(code, name, category, description string) *entity.Permission {
+		return &entity.Permission{Code: code, Name: name, Category: category, Description: &description}
+	}
+
+	created := p.init.PermissionInit(
+		permission(constants.PermissionUserRead, "查看用户", constants.PermissionCategoryUser, "查看用户信息、用户分组、登录设备与账号锁定状态"),
+		permission(constants.PermissionUserWrite, "管理用户", constants.PermissionCategoryUser, "创建、修改与停用用户，管理用户分组，强制退出登录与解除账号锁定"),
+		permission(constants.PermissionApplicationRead, "查看应用", constants.PermissionCategoryApplication, "查看接入应用配置与授权日志"),
+		permission(constants.PermissionApplicationWrite, "管理应用", constants.PermissionCategoryApplication, "创建、修改与停用接入应用"),
+		permission(constants.PermissionRoleRead, "查看角色", constants.PermissionCategoryRole, "查看角色、角色权限与用户角色"),
+		permission(constants.PermissionRoleWrite, "管理角色", constants.PermissionCategoryRole, "创建与修改角色，分配角色权限与用户角色"),
+		permission(constants.PermissionProviderRead, "查看第三方登录", constants.PermissionCategoryProvider, "查看第三方登录提供商配置"),
+		permission(constants.PermissionProviderWrite, "管理第三方登录", constants.PermissionCategoryProvider, "创建、修改与删除第三方登录提供商"),
+		permission(constants.PermissionSystemRead, "查看系统", constants.PermissionCategorySystem, "查看系统配置、租户与审计日志"),
+		permission(constants.PermissionSystemWrite, "管理系统", constants.PermissionCategorySystem, "修改系统配置，创建与停用租户"),
+	)
+
+	var adminPermissions []*entity.Permission
+	for _, permission := range created {
+		if permission.Code != constants.PermissionRoleWrite && permission.Code != constants.PermissionSystemWrite {
+			adminPermissions = append(adminPermissions, permission)
+		}
+	}
+	p.init.RolePermissionInit(constants.RoleSuperAdmin, created...)
+	p.init.RolePermissionInit(constants.RoleAdmin, adminPermissions...)
+}
+
 // PrepareApplication 初始化系统的默认应用数据。
 //
 // 调用此方法时，将在应用表中检查是否存在预定义的应用数据。若应用不存在，则创建以下默认应用：
@@ -162,6 +283,7 @@ func (p *prepare) PrepareApplication() {
 
 	p.init.ApplicationInit(
 		&entity.Application{
+			TenantUUID:        p.tenant.UUID,
 			Name:              "默认应用",
 			Description:       &defaultDesc,
 			ApplicationID:     "10000",
@@ -170,6 +292,7 @@ func (p *prepare) PrepareApplication() {
 			AllowedOrigins:    &defaultApplicationAllowedOriginsJson,
 		},
 		&entity.Application{
+			TenantUUID:        p.tenant.UUID,
 			Name:              "测试应用",
 			Description:       &demoDesc,
 			ApplicationID:     "10001",
@@ -193,23 +316,72 @@ func (p *prepare) PrepareSystem() {
 	)
 }
 
-// PrepareSuperAdmin 创建系统超级管理员账户并初始化相关角色关联关系。
+// PrepareProvider 初始化系统的默认第三方登录提供商数据。
+//
+// 调用此方法时，将在提供商表中检查是否存在预定义的提供商。若提供商不存在，则创建以下默认提供商：
+// - "wechat": 微信开放平台网站应用，用于 PC 端扫码登录。
+// - "wechat_mp": 微信公众号网页授权，用于微信内置浏览器登录。
+// - "wechat_mini": 微信小程序登录。
+// 默认提供商归属默认租户，未填写 AppID 与密钥，创建后处于未启用状态，需要管理员补充配置后启用。
+func (p *prepare) PrepareProvider() {
+	p.init.ProviderInit(
+		&entity.ThirdPartyProvider{
+			TenantUUID:  p.tenant.UUID,
+			Name:        "微信扫码登录",
+			Code:        constants.ProviderWechat,
+			Type:        constants.ProviderTypeWechat,
+			AuthURL:     "https://open.weixin.qq.com/connect/qrconnect",
+			TokenURL:    "https://api.weixin.qq.com/sns/oauth2/access_token",
+			UserInfoURL: "https://api.weixin.qq.com/sns/userinfo",
+			Scope:       "snsapi_login",
+			RedirectURL: "http://localhost:2233/api/v1/oauth/wechat/callback",
+			SortOrder:   10,
+		},
+		&entity.ThirdPartyProvider{
+			TenantUUID:  p.tenant.UUID,
+			Name:        "微信公众号",
+			Code:        constants.ProviderWechatMP,
+			Type:        constants.ProviderTypeWechat,
+			AuthURL:     "https://open.weixin.qq.com/connect/oauth2/authorize",
+			TokenURL:    "https://api.weixin.qq.com/sns/oauth2/access_token",
+			UserInfoURL: "https://api.weixin.qq.com/sns/userinfo",
+			Scope:       "snsapi_userinfo",
+			RedirectURL: "http://localhost:2233/api/v1/oauth/wechat/callback",
+			SortOrder:   11,
+		},
+		&entity.ThirdPartyProvider{
+			TenantUUID:  p.tenant.UUID,
+			Name:        "微信小程序",
+			Code:        constants.ProviderWechatMini,
+			Type:        constants.ProviderTypeWechat,
+			AuthURL:     "https://api.weixin.qq.com/sns/jscode2session",
+			TokenURL:    "https://api.weixin.qq.com/sns/jscode2session",
+			UserInfoURL: "https://api.weixin.qq.com/sns/jscode2session",
+			SortOrder:   12,
+		},
+	)
+}
+
+// PrepareSuperAdmin 在默认租户中创建系统超级管理员账户并初始化相关角色关联关系。
 // 如果系统中不存在超级管理员配置，则生成默认的超级管理员用户和角色。
+// 默认超级管理员使用公开的初始密码，首次登录时必须修改；升级前创建、仍在使用初始密码的超级管理员同样要求修改。
 // 若初始化失败或相关依赖数据不存在，会触发 panic。
 func (p *prepare) PrepareSuperAdmin() {
 	var getSystemInfo entity.System
 	result := p.db.Where(&entity.System{Key: "system.admin.super"}).First(&getSystemInfo)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		// 用户密码
-		password, err := xUtil.EncryptPasswordString("super_admin")
+		// 用户密码，使用配置的密码哈希算法计算
+		passwordHash, err := p.policy.Hash("super_admin")
 		if err != nil {
 			panic(fmt.Sprintf("[DB] 创建超级管理员用户失败: %s", err.Error()))
 		}
 		// 创建超级管理员用户
 		var superAdmin = entity.User{
-			Username:     "super_admin",
-			Email:        "super_admin@x-lf.com",
-			PasswordHash: password,
+			TenantUUID:             p.tenant.UUID,
+			Username:               "super_admin",
+			Email:                  xUtil.Ptr("super_admin@x-lf.com"),
+			PasswordHash:           &passwordHash,
+			PasswordChangeRequired: true,
 		}
 		if err := p.db.Create(&superAdmin).Error; err != nil {
 			panic(fmt.Sprintf("[DB] 创建超级管理员用户失败: %s", err.Error()))
@@ -236,5 +408,24 @@ func (p *prepare) PrepareSuperAdmin() {
 		if err := p.db.Create(&systemConfig).Error; err != nil {
 			panic(fmt.Sprintf("[DB] 创建系统配置失败: %s", err.Error()))
 		}
+		return
+	}
+	if result.Error != nil || getSystemInfo.Value == nil {
+		return
+	}
+
+	// 仍在使用初始密码的超级管理员，下次登录时必须修改密码
+	var superAdmin entity.User
+	if err := p.db.First(&superAdmin, "uuid = ?", *getSystemInfo.Value).Error; err != nil {
+		return
+	}
+	if superAdmin.PasswordChangeRequired || !superAdmin.HasPassword() {
+		return
+	}
+	if match, _ := p.policy.Verify(*superAdmin.PasswordHash, "super_admin"); !match {
+		return
+	}
+	if err := p.db.Model(&superAdmin).Update("password_change_required", true).Error; err != nil {
+		panic(fmt.Sprintf("[DB] 更新超级管理员失败: %s", err.Error()))
 	}
 }
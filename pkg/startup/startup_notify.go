@@ -0,0 +1,45 @@
+package startup
+
+import (
+	"context"
+
+	xConsts "github.com/bamboo-services/bamboo-base-go/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/logout"
+	"github.com/bamboo-services/bamboo-sso/pkg/mailer"
+	"github.com/bamboo-services/bamboo-sso/pkg/sms"
+)
+
+// MailerStartup 根据业务配置创建邮件发送器。
+// 如果发送方式或发件人配置无效，函数将会因 panic 终止程序。
+func (r *reg) MailerStartup() {
+	r.serv.Logger.Named(xConsts.LogINIT).Info("初始化邮件发送器")
+
+	if r.sso.Email.SigningKey == "" {
+		panic("[MAIL] 未配置邮件令牌签名密钥")
+	}
+	m, err := mailer.New(&r.sso.Email, r.serv.Logger)
+	if err != nil {
+		panic("[MAIL] 初始化邮件发送器失败: " + err.Error())
+	}
+	r.mailer = m
+}
+
+// SMSStartup 根据业务配置创建短信发送器。
+// 如果发送方式配置无效，函数将会因 panic 终止程序。
+func (r *reg) SMSStartup() {
+	r.serv.Logger.Named(xConsts.LogINIT).Info("初始化短信发送器")
+
+	sender, err := sms.New(&r.sso.SMS, r.serv.Logger)
+	if err != nil {
+		panic("[SMS] 初始化短信发送器失败: " + err.Error())
+	}
+	r.sms = sender
+}
+
+// LogoutStartup 创建后端通道登出通知发送器，并在后台持续投递队列中的通知。
+func (r *reg) LogoutStartup() {
+	r.serv.Logger.Named(xConsts.LogINIT).Info("启动登出通知发送器")
+
+	r.logout = logout.New(r.rdb, &r.sso.Logout, r.serv.Logger)
+	go r.logout.Run(context.Background())
+}
@@ -0,0 +1,131 @@
+package startup
+
+import (
+	"database/sql"
+	"fmt"
+
+	xInit "github.com/bamboo-services/bamboo-base-go/init"
+	"github.com/bamboo-services/bamboo-sso/pkg/secret"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// reencryptBatchSize 是重新加密时每批读取的记录数。
+const reencryptBatchSize = 500
+
+// Reencrypt 使用当前启用的主密钥重新加密所有实体中的加密字段，用于主密钥轮换。
+//
+// 加密字段通过 gorm 标签中的 serializer:encrypted 自动识别；已使用当前主密钥加密的值会被跳过，
+// 启用加密前写入的明文会被加密。更新时以原值作为条件，避免覆盖执行期间被业务写入的新值。
+// 作为维护命令只建立数据库连接并读取密钥环，不迁移数据库表也不初始化基础数据。执行失败时，函数将会因 panic 终止程序。
+func Reencrypt(serv *xInit.Reg) {
+	r := New(serv)
+	r.ConfigStartup()
+	r.db = r.connectDatabase()
+
+	log := r.serv.Logger.Named("SECRET")
+	keyring := secret.Current()
+	for _, model := range tableEntity {
+		count, err := reencryptTable(r.db, keyring, model)
+		if err != nil {
+			panic("[SECRET] 重新加密失败: " + err.Error())
+		}
+		if count > 0 {
+			log.Info("重新加密完成", zap.String("table", fmt.Sprintf("%T", model)), zap.Int("rows", count))
+		}
+	}
+	log.Info("全部加密字段已使用当前主密钥加密")
+}
+
+// reencryptTable 重新加密一张表中的加密字段，返回被更新的记录数。
+//
+// 读取与写入均直接操作原始列值，不经过加密字段的序列化器。
+func reencryptTable(db *gorm.DB, keyring *secret.Keyring, model any) (int, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return 0, err
+	}
+	var columns []string
+	for _, field := range stmt.Schema.Fields {
+		if field.TagSettings["SERIALIZER"] == secret.SerializerName {
+			columns = append(columns, field.DBName)
+		}
+	}
+	if len(columns) == 0 || stmt.Schema.PrioritizedPrimaryField == nil {
+		return 0, nil
+	}
+	table := stmt.Schema.Table
+	primary := stmt.Schema.PrioritizedPrimaryField.DBName
+
+	updated := 0
+	last := ""
+	for {
+		query := db.Table(table).Select(append([]string{primary}, columns...)).Order(primary).Limit(reencryptBatchSize)
+		if last != "" {
+			query = query.Where(primary+" > ?", last)
+		}
+		rows, err := query.Rows()
+		if err != nil {
+			return updated, err
+		}
+
+		batch := 0
+		for rows.Next() {
+			var key string
+			values := make([]sql.NullString, len(columns))
+			dest := []any{&key}
+			for i := range values {
+				dest = append(dest, &values[i])
+			}
+			if err := rows.Scan(dest...); err != nil {
+				_ = rows.Close()
+				return updated, err
+			}
+			batch++
+			last = key
+
+			ok, err := reencryptRow(db, keyring, table, primary, key, columns, values)
+			if err != nil {
+				_ = rows.Close()
+				return updated, fmt.Errorf("%s(%s): %w", table, key, err)
+			}
+			if ok {
+				updated++
+			}
+		}
+		if err := rows.Close(); err != nil {
+			return updated, err
+		}
+		if batch < reencryptBatchSize {
+			return updated, nil
+		}
+	}
+}
+
+// reencryptRow 重新加密一条记录中需要轮换的字段，记录无需更新或已被并发修改时返回 false。
+func reencryptRow(db *gorm.DB, keyring *secret.Keyring, table, primary, key string, columns []string, values []sql.NullString) (bool, error) {
+	changes := map[string]any{}
+	query := db.Table(table).Where(primary+" = ?", key)
+	for i, column := range columns {
+		stored := values[i]
+		if !stored.Valid || !keyring.NeedsRotation(stored.String) {
+			continue
+		}
+		plaintext, err := keyring.Open(stored.String)
+		if err != nil {
+			return false, err
+		}
+		sealed, err := keyring.Seal(plaintext)
+		if err != nil {
+			return false, err
+		}
+		changes[column] = sealed
+		query = query.Where(column+" = ?", stored.String)
+	}
+	if len(changes) == 0 {
+		return false, nil
+	}
+
+	tx := query.UpdateColumns(changes)
+	return tx.RowsAffected > 0, tx.Error
+}
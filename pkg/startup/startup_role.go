@@ -0,0 +1,45 @@
+package startup
+
+import (
+	"context"
+	"time"
+
+	xConsts "github.com/bamboo-services/bamboo-base-go/constants"
+	"github.com/bamboo-services/bamboo-sso/internal/models/entity"
+	"go.uber.org/zap"
+)
+
+// RoleExpiryStartup 在后台定期停用已过期的用户角色分配。
+//
+// 过期的分配在认证时已被忽略，认证缓存的有效期也不会超过角色的过期时间，停用只是让数据与实际状态一致；
+// 多个服务实例同时执行时结果相同，无需加锁。
+func (r *reg) RoleExpiryStartup() {
+	r.serv.Logger.Named(xConsts.LogINIT).Info("启动角色过期检查任务")
+
+	go r.expireUserRoles(context.Background())
+}
+
+// expireUserRoles 每隔 ExpiryInterval 停用一次已过期的用户角色分配，直到 ctx 被取消。
+func (r *reg) expireUserRoles(ctx context.Context) {
+	log := r.serv.Logger.Named("ROLE")
+	ticker := time.NewTicker(r.sso.Role.ExpiryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			expired := r.db.WithContext(ctx).Model(&entity.UserRole{}).
+				Where("is_active = ? AND expires_at <= ?", true, now).
+				Updates(map[string]any{"is_active": false, "updated_at": now})
+			if expired.Error != nil {
+				log.Error("停用过期的角色分配失败", zap.Error(expired.Error))
+				continue
+			}
+			if expired.RowsAffected > 0 {
+				log.Info("已停用过期的角色分配", zap.Int64("count", expired.RowsAffected))
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package startup
+
+import (
+	xConsts "github.com/bamboo-services/bamboo-base-go/constants"
+	"github.com/bamboo-services/bamboo-sso/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// StorageStartup 根据业务配置创建文件存储；使用本地目录存储时，在配置的路径下对外提供目录中的文件。
+// 如果存储方式配置无效或目录无法创建，函数将会因 panic 终止程序。
+func (r *reg) StorageStartup() {
+	r.serv.Logger.Named(xConsts.LogINIT).Info("初始化文件存储")
+
+	s, err := storage.New(&r.sso.Storage)
+	if err != nil {
+		panic("[STORAGE] 初始化文件存储失败: " + err.Error())
+	}
+	if local, ok := s.(*storage.Local); ok {
+		r.serv.Serve.StaticFS(r.sso.Storage.Local.Path, gin.Dir(local.Dir(), false))
+	}
+	r.storage = s
+}
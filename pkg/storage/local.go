@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local 将文件保存在本地目录中，由 SSO 服务以静态文件的方式对外提供访问，适用于单实例部署。
+type Local struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocal 创建保存在本地目录中的文件存储，baseURL 为该目录对外提供访问的地址前缀；目录不存在时自动创建。
+func NewLocal(dir, baseURL string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &Local{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// Dir 返回保存文件的本地目录。
+func (l *Local) Dir() string {
+	return l.dir
+}
+
+// Put 将文件写入本地目录，先写入临时文件再重命名，避免读取到写入一半的文件。
+func (l *Local) Put(_ context.Context, key string, body []byte, _ string) (string, error) {
+	target, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return "", err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(temp.Name()) }()
+	if _, err := temp.Write(body); err != nil {
+		_ = temp.Close()
+		return "", err
+	}
+	if err := temp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(temp.Name(), 0o640); err != nil {
+		return "", err
+	}
+	if err := os.Rename(temp.Name(), target); err != nil {
+		return "", err
+	}
+	return l.baseURL + "/" + key, nil
+}
+
+// Delete 删除本地目录中的文件。
+func (l *Local) Delete(_ context.Context, key string) error {
+	target, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Key 从文件地址中去掉地址前缀得到存储键。
+func (l *Local) Key(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, l.baseURL+"/")
+	if !ok || !fs.ValidPath(key) {
+		return "", false
+	}
+	return key, true
+}
+
+// path 将存储键转换为本地目录中的文件路径，拒绝包含 ".." 等可能跳出目录的存储键。
+func (l *Local) path(key string) (string, error) {
+	if !fs.ValidPath(key) || key == "." || path.Clean(key) != key {
+		return "", fmt.Errorf("存储键无效: %q", key)
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bamboo-services/bamboo-sso/pkg/config"
+)
+
+// Storage 是文件存储的统一接口，具体的存储方式由配置中的 driver 决定。
+//
+// 文件以存储键标识，如 "avatars/<用户UUID>/<随机串>.png"，存储后可以通过返回的地址公开访问。
+type Storage interface {
+	// Put 保存文件并返回可以公开访问的地址，存储键已存在时覆盖原文件。
+	Put(ctx context.Context, key string, body []byte, contentType string) (string, error)
+	// Delete 删除文件，文件不存在时不返回错误。
+	Delete(ctx context.Context, key string) error
+	// Key 从 Put 返回的地址还原存储键，地址不属于该存储时返回 false。
+	Key(url string) (string, bool)
+}
+
+// New 根据配置创建文件存储。
+func New(cfg *config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocal(cfg.Local.Dir, cfg.Local.BaseURL)
+	default:
+		return nil, fmt.Errorf("不支持的文件存储方式 %q", cfg.Driver)
+	}
+}
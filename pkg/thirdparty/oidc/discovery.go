@@ -0,0 +1,73 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// discoveryTTL 是服务发现结果与 JWKS 公钥在内存中的缓存时长。
+const discoveryTTL = time.Hour
+
+// cacheEntry 表示一条带过期时间的缓存记录。
+type cacheEntry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+var (
+	discoveryCache sync.Map // issuer -> cacheEntry[Endpoints]
+	discoveryHTTP  = &http.Client{Timeout: 10 * time.Second}
+)
+
+// Discover 通过 issuer 的 /.well-known/openid-configuration 获取上游端点地址。
+//
+// 结果会在内存中缓存一小时；返回的 issuer 必须与传入值一致，防止被引导到其他签发者。
+func Discover(ctx context.Context, issuer string) (Endpoints, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	if cached, ok := discoveryCache.Load(issuer); ok {
+		entry := cached.(cacheEntry[Endpoints])
+		if time.Now().Before(entry.expiresAt) {
+			return entry.value, nil
+		}
+	}
+
+	var endpoints Endpoints
+	if err := getJSON(ctx, issuer+"/.well-known/openid-configuration", &endpoints); err != nil {
+		return Endpoints{}, err
+	}
+	if strings.TrimSuffix(endpoints.Issuer, "/") != issuer {
+		return Endpoints{}, fmt.Errorf("服务发现返回的 issuer 不匹配: %s", endpoints.Issuer)
+	}
+	if endpoints.AuthURL == "" || endpoints.TokenURL == "" {
+		return Endpoints{}, errors.New("服务发现结果缺少授权或令牌端点")
+	}
+
+	discoveryCache.Store(issuer, cacheEntry[Endpoints]{value: endpoints, expiresAt: time.Now().Add(discoveryTTL)})
+	return endpoints, nil
+}
+
+// getJSON 发起 GET 请求并将 JSON 响应解析到 out 中。
+func getJSON(ctx context.Context, target string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := discoveryHTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("请求 %s 失败: %s", target, resp.Status)
+	}
+	return jsoniter.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,159 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// jwksCache 缓存各 JWKS 地址下的 RSA 公钥，键为 jwks_uri。
+var jwksCache sync.Map // jwksURL -> cacheEntry[map[string]*rsa.PublicKey]
+
+// jwk 表示 JWKS 中的一把公钥，目前只使用 RSA 公钥。
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// VerifyIDToken 校验上游签发的 ID Token 并返回其中的声明。
+//
+// 校验内容：
+//   - 签名算法为 RS256，且能用 JWKS 中对应 kid 的公钥验证通过。
+//   - iss 与服务发现得到的 issuer 一致，aud 包含本客户端的 ClientID。
+//   - exp 未过期，nonce 与发起授权时生成的值一致。
+func (cli *Client) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (map[string]any, error) {
+	if cli.Endpoints.JWKSURL == "" {
+		return nil, errors.New("未配置 jwks_uri，无法校验 ID Token")
+	}
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("ID Token 格式错误")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("不支持的 ID Token 签名算法: %s", header.Alg)
+	}
+
+	publicKey, err := cli.publicKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("ID Token 签名校验失败")
+	}
+
+	claims := map[string]any{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if iss, _ := claims["iss"].(string); strings.TrimSuffix(iss, "/") != strings.TrimSuffix(cli.Endpoints.Issuer, "/") {
+		return nil, errors.New("ID Token 签发者不匹配")
+	}
+	if !audienceContains(claims["aud"], cli.ClientID) {
+		return nil, errors.New("ID Token 受众不匹配")
+	}
+	if exp, ok := claims["exp"].(json.Number); !ok || !notExpired(exp) {
+		return nil, errors.New("ID Token 已过期")
+	}
+	if got, _ := claims["nonce"].(string); nonce != "" && got != nonce {
+		return nil, errors.New("ID Token nonce 不匹配")
+	}
+	return claims, nil
+}
+
+// publicKey 获取指定 kid 的公钥，缓存中找不到时会重新拉取 JWKS 以适应上游轮换密钥。
+func (cli *Client) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	if cached, ok := jwksCache.Load(cli.Endpoints.JWKSURL); ok {
+		entry := cached.(cacheEntry[map[string]*rsa.PublicKey])
+		if key, ok := entry.value[kid]; ok && time.Now().Before(entry.expiresAt) {
+			return key, nil
+		}
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, cli.Endpoints.JWKSURL, &set); err != nil {
+		return nil, err
+	}
+	keys := map[string]*rsa.PublicKey{}
+	for _, key := range set.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(key.N)
+		e, errE := base64.RawURLEncoding.DecodeString(key.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[key.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	jwksCache.Store(cli.Endpoints.JWKSURL, cacheEntry[map[string]*rsa.PublicKey]{value: keys, expiresAt: time.Now().Add(discoveryTTL)})
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("JWKS 中不存在 kid 为 %s 的公钥", kid)
+	}
+	return key, nil
+}
+
+// decodeSegment 解码 JWT 的一个 Base64URL 段并解析为 JSON。
+func decodeSegment(segment string, out any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := jsoniter.NewDecoder(strings.NewReader(string(raw)))
+	decoder.UseNumber()
+	return decoder.Decode(out)
+}
+
+// audienceContains 判断 aud 声明（字符串或字符串数组）中是否包含指定的客户端ID。
+func audienceContains(aud any, clientID string) bool {
+	switch value := aud.(type) {
+	case string:
+		return value == clientID
+	case []any:
+		for _, item := range value {
+			if item == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// notExpired 判断以秒为单位的 exp 声明是否仍在有效期内，允许一分钟的时钟偏差。
+func notExpired(exp json.Number) bool {
+	seconds, err := exp.Int64()
+	if err != nil {
+		return false
+	}
+	return time.Now().Add(-time.Minute).Before(time.Unix(seconds, 0))
+}
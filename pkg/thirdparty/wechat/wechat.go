@@ -0,0 +1,162 @@
+package wechat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// 微信开放接口地址。
+const (
+	apiAccessToken    = "https://api.weixin.qq.com/sns/oauth2/access_token"
+	apiUserInfo       = "https://api.weixin.qq.com/sns/userinfo"
+	apiJSCode2Session = "https://api.weixin.qq.com/sns/jscode2session"
+)
+
+// Client 表示微信开放接口的调用客户端，一个客户端对应一个微信 AppID。
+//
+// 字段说明：
+//   - AppID: 微信分配的应用 AppID，对应 ThirdPartyProvider.ClientID。
+//   - AppSecret: 微信分配的应用密钥，对应 ThirdPartyProvider.ClientSecret。
+//   - http: 发起请求所用的 HTTP 客户端。
+type Client struct {
+	AppID     string
+	AppSecret string
+	http      *http.Client
+}
+
+// New 创建一个新的微信客户端实例。
+func New(appID, appSecret string) *Client {
+	return &Client{
+		AppID:     appID,
+		AppSecret: appSecret,
+		http:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// apiError 表示微信接口返回的通用错误字段。
+type apiError struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// err 在微信接口返回错误码时将其转换为 error。
+func (e apiError) err() error {
+	if e.ErrCode != 0 {
+		return fmt.Errorf("微信接口返回错误 [%d]: %s", e.ErrCode, e.ErrMsg)
+	}
+	return nil
+}
+
+// AccessToken 表示网页授权（扫码登录/公众号授权）换取的访问令牌。
+type AccessToken struct {
+	apiError
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	OpenID       string `json:"openid"`
+	Scope        string `json:"scope"`
+	UnionID      string `json:"unionid"`
+}
+
+// UserInfo 表示通过网页授权获取的微信用户信息。
+type UserInfo struct {
+	apiError
+	OpenID     string `json:"openid"`
+	UnionID    string `json:"unionid"`
+	Nickname   string `json:"nickname"`
+	Sex        int    `json:"sex"`
+	Province   string `json:"province"`
+	City       string `json:"city"`
+	Country    string `json:"country"`
+	HeadImgURL string `json:"headimgurl"`
+	Language   string `json:"language"`
+}
+
+// Session 表示小程序 jscode2session 接口返回的会话信息。
+type Session struct {
+	apiError
+	OpenID     string `json:"openid"`
+	SessionKey string `json:"session_key"`
+	UnionID    string `json:"unionid"`
+}
+
+// AuthorizeURL 构造用户跳转授权的地址。
+//
+// authURL 为提供商配置的授权地址：网站应用扫码登录使用 qrconnect，公众号网页授权使用 oauth2/authorize；
+// scope 分别为 snsapi_login 与 snsapi_userinfo。
+func (cli *Client) AuthorizeURL(authURL, redirectURL, scope, state string) string {
+	query := url.Values{}
+	query.Set("appid", cli.AppID)
+	query.Set("redirect_uri", redirectURL)
+	query.Set("response_type", "code")
+	query.Set("scope", scope)
+	query.Set("state", state)
+	return authURL + "?" + query.Encode() + "#wechat_redirect"
+}
+
+// ExchangeCode 使用授权回调中的 code 换取网页授权访问令牌。
+func (cli *Client) ExchangeCode(ctx context.Context, code string) (*AccessToken, error) {
+	query := url.Values{}
+	query.Set("appid", cli.AppID)
+	query.Set("secret", cli.AppSecret)
+	query.Set("code", code)
+	query.Set("grant_type", "authorization_code")
+
+	var token AccessToken
+	if err := cli.get(ctx, apiAccessToken, query, &token); err != nil {
+		return nil, err
+	}
+	return &token, token.err()
+}
+
+// GetUserInfo 使用网页授权访问令牌获取用户信息。
+func (cli *Client) GetUserInfo(ctx context.Context, accessToken, openID string) (*UserInfo, error) {
+	query := url.Values{}
+	query.Set("access_token", accessToken)
+	query.Set("openid", openID)
+	query.Set("lang", "zh_CN")
+
+	var info UserInfo
+	if err := cli.get(ctx, apiUserInfo, query, &info); err != nil {
+		return nil, err
+	}
+	return &info, info.err()
+}
+
+// JSCode2Session 使用小程序 wx.login 获取的 code 换取 openid、session_key 与 unionid。
+func (cli *Client) JSCode2Session(ctx context.Context, code string) (*Session, error) {
+	query := url.Values{}
+	query.Set("appid", cli.AppID)
+	query.Set("secret", cli.AppSecret)
+	query.Set("js_code", code)
+	query.Set("grant_type", "authorization_code")
+
+	var session Session
+	if err := cli.get(ctx, apiJSCode2Session, query, &session); err != nil {
+		return nil, err
+	}
+	return &session, session.err()
+}
+
+// get 发起 GET 请求并将 JSON 响应解析到 out 中。
+func (cli *Client) get(ctx context.Context, api string, query url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := cli.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("微信接口响应异常: %s", resp.Status)
+	}
+	return jsoniter.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,81 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Digits 是动态口令的位数。
+	Digits = 6
+	// Period 是动态口令的有效时间步长。
+	Period = 30 * time.Second
+	// Skew 是校验时前后允许偏差的时间步数，用于容忍客户端的时钟误差。
+	Skew = 1
+	// secretSize 是共享密钥的字节数，RFC 4226 建议至少 160 位。
+	secretSize = 20
+)
+
+// encoding 是共享密钥使用的 Base32 编码，身份验证器应用普遍要求无填充。
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret 生成一个新的随机共享密钥，以 Base32 编码返回。
+func GenerateSecret() string {
+	buf := make([]byte, secretSize)
+	if _, err := rand.Read(buf); err != nil {
+		panic("生成随机数失败: " + err.Error())
+	}
+	return encoding.EncodeToString(buf)
+}
+
+// URI 构造身份验证器应用扫码使用的 otpauth 地址。
+func URI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int(Period.Seconds())))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Validate 校验动态口令，通过时返回口令对应的时间步。
+//
+// 调用方应记录最近一次通过校验的时间步，拒绝不大于它的口令，防止同一口令被重复使用。
+func Validate(secret, code string, now time.Time) (int64, bool) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil || len(code) != Digits {
+		return 0, false
+	}
+
+	current := now.Unix() / int64(Period.Seconds())
+	for offset := int64(-Skew); offset <= Skew; offset++ {
+		step := current + offset
+		if subtle.ConstantTimeCompare([]byte(generate(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// generate 按 RFC 6238 计算指定时间步的动态口令。
+func generate(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%1000000)
+}
@@ -0,0 +1,60 @@
+package utility
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	// ErrImageFormat 表示图片无法解码，或不是支持的 JPEG、PNG、GIF、WebP 格式。
+	ErrImageFormat = errors.New("不支持的图片格式")
+	// ErrImageTooLarge 表示图片的像素数超出限制。
+	ErrImageTooLarge = errors.New("图片尺寸过大")
+)
+
+// SquareThumbnail 将图片居中裁剪为正方形并缩放为 size×size 像素，返回编码后的图片与其 Content-Type。
+//
+// 解码前先读取图片尺寸，像素数超过 maxPixels 时返回 ErrImageTooLarge，避免解码超大图片耗尽内存；
+// GIF 只取第一帧。JPEG 图片重新编码为 JPEG，其余格式可能带有透明通道，编码为 PNG。
+// 重新编码会丢弃原图中的 EXIF 等元数据。
+func SquareThumbnail(data []byte, size, maxPixels int) ([]byte, string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrImageFormat
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width > maxPixels/config.Height {
+		return nil, "", ErrImageTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrImageFormat
+	}
+
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+	))
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+
+	var out bytes.Buffer
+	if format == "jpeg" {
+		if err := jpeg.Encode(&out, dst, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, "", err
+		}
+		return out.Bytes(), "image/jpeg", nil
+	}
+	if err := png.Encode(&out, dst); err != nil {
+		return nil, "", err
+	}
+	return out.Bytes(), "image/png", nil
+}
@@ -0,0 +1,33 @@
+package utility
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"math/big"
+)
+
+// RandomToken 生成 size 字节的安全随机数，并以 URL 安全的 Base64 编码（无填充）返回。
+//
+// 适用于访问令牌、刷新令牌、state 等需要不可预测性的场景。
+func RandomToken(size int) string {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		panic("生成随机数失败: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// RandomDigits 生成 length 位的安全随机数字串，每一位在 0-9 之间均匀分布。
+//
+// 适用于短信验证码等需要用户手动输入的场景。
+func RandomDigits(length int) string {
+	buf := make([]byte, length)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			panic("生成随机数失败: " + err.Error())
+		}
+		buf[i] = byte('0' + n.Int64())
+	}
+	return string(buf)
+}
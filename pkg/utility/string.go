@@ -0,0 +1,27 @@
+package utility
+
+import "strings"
+
+// NilIfBlank 在字符串去除首尾空白后为空时返回 nil，否则返回其指针。
+//
+// 适用于将第三方接口返回的可选字段写入实体中的可空列。
+func NilIfBlank(value string) *string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return &value
+}
+
+// Truncate 将字符串截断为最多 maxRunes 个字符，不会截断多字节字符。
+//
+// 适用于将用户提供的文件名等不受长度约束的内容写入有长度限制的列。
+func Truncate(value string, maxRunes int) string {
+	count := 0
+	for index := range value {
+		if count == maxRunes {
+			return value[:index]
+		}
+		count++
+	}
+	return value
+}
@@ -0,0 +1,106 @@
+package utility
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 设备类型，由 ParseUserAgent 根据 User-Agent 推断。
+const (
+	DeviceDesktop = "desktop" // 桌面电脑
+	DeviceMobile  = "mobile"  // 手机
+	DeviceTablet  = "tablet"  // 平板电脑
+	DeviceBot     = "bot"     // 爬虫、命令行工具等非浏览器客户端
+	DeviceUnknown = "unknown" // 无法识别
+)
+
+// UserAgent 表示从 User-Agent 中解析出的客户端信息，无法识别的部分为空字符串。
+//
+// 字段说明：
+//   - Browser: 浏览器名称，如 "Chrome"、"微信"。
+//   - BrowserVersion: 浏览器的主版本号。
+//   - OS: 操作系统名称，如 "Windows"、"iOS"。
+//   - OSVersion: 操作系统版本号。
+//   - Device: 设备类型，取值见 DeviceDesktop 等常量。
+type UserAgent struct {
+	Browser        string `json:"browser"`
+	BrowserVersion string `json:"browser_version"`
+	OS             string `json:"os"`
+	OSVersion      string `json:"os_version"`
+	Device         string `json:"device"`
+}
+
+// uaRule 表示一条按 User-Agent 识别名称与版本的规则，pattern 的第一个分组为版本号。
+type uaRule struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// browserRules 按优先级排列：套壳浏览器的 User-Agent 同时包含 Chrome 与 Safari 字样，需要先于它们匹配。
+var browserRules = []uaRule{
+	{"微信", regexp.MustCompile(`MicroMessenger/([\d.]+)`)},
+	{"QQ", regexp.MustCompile(`\bQQ/([\d.]+)`)},
+	{"Edge", regexp.MustCompile(`Edg(?:e|A|iOS)?/([\d.]+)`)},
+	{"Opera", regexp.MustCompile(`(?:OPR|Opera)/([\d.]+)`)},
+	{"Samsung Internet", regexp.MustCompile(`SamsungBrowser/([\d.]+)`)},
+	{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)/([\d.]+)`)},
+	{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)/([\d.]+)`)},
+	{"Safari", regexp.MustCompile(`Version/([\d.]+).*Safari/`)},
+	{"Internet Explorer", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)([\d.]+)`)},
+}
+
+// osRules 按优先级排列：iPad 与 Android 的 User-Agent 中可能同时包含 Mac OS X 与 Linux 字样。
+var osRules = []uaRule{
+	{"HarmonyOS", regexp.MustCompile(`HarmonyOS(?:[ /]([\d.]+))?`)},
+	{"iOS", regexp.MustCompile(`(?:iPhone|iPad|iPod).*? OS ([\d_]+)`)},
+	{"Android", regexp.MustCompile(`Android[ /]?([\d.]+)?`)},
+	{"Windows", regexp.MustCompile(`Windows NT ([\d.]+)`)},
+	{"ChromeOS", regexp.MustCompile(`CrOS \S+ ([\d.]+)`)},
+	{"macOS", regexp.MustCompile(`Mac OS X ([\d_.]+)`)},
+	{"Linux", regexp.MustCompile(`Linux()`)},
+}
+
+// botPattern 匹配常见的爬虫与命令行客户端。
+var botPattern = regexp.MustCompile(`(?i)bot|crawler|spider|curl/|wget/|python-requests|go-http-client|okhttp|postman`)
+
+// ParseUserAgent 从 User-Agent 中解析浏览器、操作系统与设备类型，用于在设备列表中展示登录设备。
+//
+// 仅识别常见的浏览器与操作系统，结果只用于展示，不能作为安全判断的依据。
+func ParseUserAgent(userAgent string) UserAgent {
+	parsed := UserAgent{Device: DeviceUnknown}
+	if strings.TrimSpace(userAgent) == "" {
+		return parsed
+	}
+	if botPattern.MatchString(userAgent) {
+		parsed.Device = DeviceBot
+		return parsed
+	}
+
+	parsed.Browser, parsed.BrowserVersion = matchRule(browserRules, userAgent)
+	if i := strings.IndexByte(parsed.BrowserVersion, '.'); i > 0 {
+		parsed.BrowserVersion = parsed.BrowserVersion[:i]
+	}
+	parsed.OS, parsed.OSVersion = matchRule(osRules, userAgent)
+	parsed.OSVersion = strings.ReplaceAll(parsed.OSVersion, "_", ".")
+
+	switch {
+	case strings.Contains(userAgent, "iPad") || strings.Contains(userAgent, "Tablet") ||
+		(parsed.OS == "Android" && !strings.Contains(userAgent, "Mobile")):
+		parsed.Device = DeviceTablet
+	case strings.Contains(userAgent, "Mobi") || strings.Contains(userAgent, "iPhone") || strings.Contains(userAgent, "iPod"):
+		parsed.Device = DeviceMobile
+	case parsed.OS != "":
+		parsed.Device = DeviceDesktop
+	}
+	return parsed
+}
+
+// matchRule 返回第一条匹配的规则名称与版本号，均未匹配时返回空字符串。
+func matchRule(rules []uaRule, userAgent string) (string, string) {
+	for _, rule := range rules {
+		if match := rule.pattern.FindStringSubmatch(userAgent); match != nil {
+			return rule.name, match[1]
+		}
+	}
+	return "", ""
+}